@@ -0,0 +1,237 @@
+package strfs
+
+import (
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/reiver/go-erorr"
+)
+
+// The strfs bundle format is a compact binary format for a tree of files.
+//
+// All integers are little-endian.
+//
+//	header:
+//		magic        [8]byte  "strfsbnd"
+//		version      uint32   (currently 1)
+//		count        uint32   number of files
+//		index-length uint64   length of the index section, in bytes
+//		data-length  uint64   length of the data section, in bytes
+//	index (one entry per file):
+//		name-length  uint32
+//		name         [name-length]byte
+//		mode         uint32   permission-bits
+//		mtime-sec    int64    modification-time, seconds since the Unix epoch
+//		mtime-nsec   uint32   modification-time, nanoseconds
+//		offset       uint64   where the file's content starts, relative to the start of the data section
+//		size         uint64   length of the file's content, in bytes
+//	data:
+//		the content of all the files
+//	checksum:
+//		crc32        uint32   CRC-32 (Castagnoli) of everything before it
+const (
+	bundleMagic   = "strfsbnd"
+	bundleVersion = 1
+
+	bundleHeaderLength   = len(bundleMagic) + 4 + 4 + 8 + 8
+	bundleChecksumLength = 4
+
+	// Every index entry is at least this long (i.e., when the name is empty).
+	bundleIndexEntryLength = 4 + 4 + 8 + 4 + 8 + 8
+)
+
+var bundleCRC32Table *crc32.Table = crc32.MakeTable(crc32.Castagnoli)
+
+// ExportBundle writes each regular file in 'fsys' (such as a strfs.FS) to 'writer' in the strfs bundle format,
+// keeping each file's path, content, modification-time, and permission-bits.
+//
+// The strfs bundle format is meant for caching trees of files, where loading them back (with ImportBundle or DecodeBundle) needs to be fast.
+func ExportBundle(writer io.Writer, fsys fs.FS) error {
+	if nil == writer {
+		return errNilWriter
+	}
+
+	var index []byte
+	var data []byte
+	var count uint32
+
+	err := exportWalk(fsys, func(name string, fileinfo fs.FileInfo, content []byte) error {
+		var modtime time.Time = fileinfo.ModTime()
+
+		index = bundleAppendUint32(index, uint32(len(name)))
+		index = append(index, name...)
+		index = bundleAppendUint32(index, uint32(fileinfo.Mode().Perm()))
+		index = bundleAppendUint64(index, uint64(modtime.Unix()))
+		index = bundleAppendUint32(index, uint32(modtime.Nanosecond()))
+		index = bundleAppendUint64(index, uint64(len(data)))
+		index = bundleAppendUint64(index, uint64(len(content)))
+
+		data = append(data, content...)
+		count++
+		return nil
+	})
+	if nil != err {
+		return err
+	}
+
+	var bundle []byte = make([]byte, 0, bundleHeaderLength+len(index)+len(data)+bundleChecksumLength)
+	bundle = append(bundle, bundleMagic...)
+	bundle = bundleAppendUint32(bundle, bundleVersion)
+	bundle = bundleAppendUint32(bundle, count)
+	bundle = bundleAppendUint64(bundle, uint64(len(index)))
+	bundle = bundleAppendUint64(bundle, uint64(len(data)))
+	bundle = append(bundle, index...)
+	bundle = append(bundle, data...)
+	bundle = bundleAppendUint32(bundle, crc32.Checksum(bundle, bundleCRC32Table))
+
+	_, err = writer.Write(bundle)
+	return err
+}
+
+// ImportBundle reads a strfs bundle from 'reader' and returns a strfs.FS with each of its files.
+//
+// The whole bundle is read into a single string, and the content of each file shares its bytes with that string.
+// (I.e., after the bundle has been read, the files are served without copying.)
+func ImportBundle(reader io.Reader) (FS, error) {
+	if nil == reader {
+		return nil, errNilReader
+	}
+
+	var builder strings.Builder
+	if _, err := io.Copy(&builder, reader); nil != err {
+		return nil, err
+	}
+
+	return DecodeBundle(builder.String())
+}
+
+// DecodeBundle decodes the strfs bundle in 'bundle' and returns a strfs.FS with each of its files.
+//
+// The content of each file shares its bytes with 'bundle' — nothing is copied.
+//
+// DecodeBundle checks the checksum, that everything in the index is within bounds, and that no two files have the same path, before returning anything.
+func DecodeBundle(bundle string) (FS, error) {
+	if len(bundle) < bundleHeaderLength+bundleChecksumLength {
+		return nil, errBundleTooShort
+	}
+	if bundleMagic != bundle[:len(bundleMagic)] {
+		return nil, errBundleBadMagic
+	}
+
+	{
+		var body string = bundle[:len(bundle)-bundleChecksumLength]
+
+		var expected uint32 = bundleUint32(bundle[len(body):])
+		var actual   uint32 = bundleChecksum(body)
+
+		if expected != actual {
+			return nil, erorr.Errorf("bundle checksum mismatch: expected %08x but actually %08x", expected, actual)
+		}
+	}
+
+	var header string = bundle[len(bundleMagic):bundleHeaderLength]
+
+	var version     uint32 = bundleUint32(header[0:4])
+	var count       uint32 = bundleUint32(header[4:8])
+	var indexLength uint64 = bundleUint64(header[8:16])
+	var dataLength  uint64 = bundleUint64(header[16:24])
+
+	if bundleVersion != version {
+		return nil, erorr.Errorf("unsupported bundle version %d", version)
+	}
+
+	var available uint64 = uint64(len(bundle) - bundleHeaderLength - bundleChecksumLength)
+	if available < indexLength || available-indexLength != dataLength {
+		return nil, erorr.Errorf("bundle section lengths (index %d, data %d) do not match the bundle length", indexLength, dataLength)
+	}
+	if indexLength/bundleIndexEntryLength < uint64(count) {
+		return nil, erorr.Errorf("bundle index (of %d bytes) is too short for %d files", indexLength, count)
+	}
+
+	var index string = bundle[bundleHeaderLength : uint64(bundleHeaderLength)+indexLength]
+	var data  string = bundle[uint64(bundleHeaderLength)+indexLength : len(bundle)-bundleChecksumLength]
+
+	var fsys FS = make(FS, count)
+
+	for i := uint32(0); i < count; i++ {
+		if len(index) < 4 {
+			return nil, erorr.Errorf("bundle index entry #%d is truncated", i)
+		}
+		var nameLength uint64 = uint64(bundleUint32(index[:4]))
+		index = index[4:]
+
+		if uint64(len(index)) < nameLength+bundleIndexEntryLength-4 {
+			return nil, erorr.Errorf("bundle index entry #%d is truncated", i)
+		}
+		var name string = index[:nameLength]
+		index = index[nameLength:]
+
+		var mode     uint32 = bundleUint32(index[0:4])
+		var mtimeSec int64  = int64(bundleUint64(index[4:12]))
+		var mtimeNs  uint32 = bundleUint32(index[12:16])
+		var offset   uint64 = bundleUint64(index[16:24])
+		var size     uint64 = bundleUint64(index[24:32])
+		index = index[32:]
+
+		if !fs.ValidPath(name) || "." == name {
+			return nil, erorr.Errorf("bundle index entry #%d has an invalid name %q", i, name)
+		}
+		if _, duplicate := fsys[name]; duplicate {
+			return nil, erorr.Errorf("bundle has more than one file named %q", name)
+		}
+		if fs.FileMode(mode) != fs.FileMode(mode).Perm() {
+			return nil, erorr.Errorf("bundle file %q has an invalid mode %#o", name, mode)
+		}
+		if 1e9 <= mtimeNs {
+			return nil, erorr.Errorf("bundle file %q has an invalid modification-time nanoseconds %d", name, mtimeNs)
+		}
+		if dataLength < offset || dataLength-offset < size {
+			return nil, erorr.Errorf("bundle file %q (offset %d, size %d) is outside of the data section (of %d bytes)", name, offset, size, dataLength)
+		}
+
+		fsys[name] = RegularFile{
+			FileContent: CreateContent(data[offset : offset+size]),
+			FileModTime: time.Unix(mtimeSec, int64(mtimeNs)).UTC(),
+			FileMode:    fs.FileMode(mode),
+		}
+	}
+
+	if 0 != len(index) {
+		return nil, erorr.Errorf("bundle index has %d unexpected bytes at its end", len(index))
+	}
+
+	return fsys, nil
+}
+
+func bundleAppendUint32(p []byte, value uint32) []byte {
+	return append(p, byte(value), byte(value>>8), byte(value>>16), byte(value>>24))
+}
+
+func bundleAppendUint64(p []byte, value uint64) []byte {
+	return bundleAppendUint32(bundleAppendUint32(p, uint32(value)), uint32(value>>32))
+}
+
+// bundleChecksum returns the CRC-32 (Castagnoli) of 's', without copying all of 's' into a []byte.
+func bundleChecksum(s string) uint32 {
+	var buffer [32*1024]byte
+
+	var checksum uint32
+	for 0 < len(s) {
+		n := copy(buffer[:], s)
+		checksum = crc32.Update(checksum, bundleCRC32Table, buffer[:n])
+		s = s[n:]
+	}
+
+	return checksum
+}
+
+func bundleUint32(s string) uint32 {
+	return uint32(s[0]) | uint32(s[1])<<8 | uint32(s[2])<<16 | uint32(s[3])<<24
+}
+
+func bundleUint64(s string) uint64 {
+	return uint64(bundleUint32(s[:4])) | uint64(bundleUint32(s[4:8]))<<32
+}
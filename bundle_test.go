@@ -0,0 +1,70 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfstest"
+
+	"bytes"
+
+	"testing"
+)
+
+func TestExportBundle_ImportBundle(t *testing.T) {
+
+	var expected strfs.FS = exampleFS()
+
+	var buffer bytes.Buffer
+	if err := strfs.ExportBundle(&buffer, expected); nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	actual, err := strfs.ImportBundle(&buffer)
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	if !sameFS(t, expected, actual, true) {
+		return
+	}
+
+	if err := strfstest.TestFS(actual, "empty.txt", "file1.txt", "html/file2.html", "gmni/file3.gmni", "gmni/fngr/file4.fngr"); nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+}
+
+func TestDecodeBundle_corrupt(t *testing.T) {
+
+	var buffer bytes.Buffer
+	if err := strfs.ExportBundle(&buffer, exampleFS()); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var bundle string = buffer.String()
+
+	if _, err := strfs.DecodeBundle(bundle); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	for length := 0; length < len(bundle); length++ {
+		if _, err := strfs.DecodeBundle(bundle[:length]); nil == err {
+			t.Errorf("For a bundle truncated to %d bytes, expected an error but did not actually get one.", length)
+			return
+		}
+	}
+
+	for offset := 0; offset < len(bundle); offset++ {
+		var corrupted []byte = []byte(bundle)
+		corrupted[offset] ^= 0x40
+
+		if _, err := strfs.DecodeBundle(string(corrupted)); nil == err {
+			t.Errorf("For a bundle with byte #%d corrupted, expected an error but did not actually get one.", offset)
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"path"
+	"strings"
+)
+
+// config holds the settings that come from the command-line flags.
+type config struct {
+	PackageName  string
+	VariableName string
+	Includes     globs
+	Excludes     globs
+	BuildTags    string
+}
+
+// globs is a flag.Value that can be given more than once (each time adding another glob).
+type globs []string
+
+func (receiver *globs) Set(value string) error {
+	if _, err := path.Match(value, ""); nil != err {
+		return err
+	}
+
+	*receiver = append(*receiver, value)
+	return nil
+}
+
+func (receiver *globs) String() string {
+	if nil == receiver {
+		return ""
+	}
+
+	return strings.Join(*receiver, ",")
+}
+
+// match returns whether any of the globs matches either the whole (slash-separated) path, or just its last element.
+func (receiver globs) match(name string) bool {
+	for _, glob := range receiver {
+		if matched, _ := path.Match(glob, name); matched {
+			return true
+		}
+		if matched, _ := path.Match(glob, path.Base(name)); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"io/fs"
+	"strconv"
+)
+
+// generate walks 'fsys' and writes (to 'writer') a Go source file that declares a strfs.FS with each of its regular files.
+func generate(writer io.Writer, fsys fs.FS, cfg config) error {
+	// The files are generated first, because the "time" package is only imported if there is at least one file.
+	var files bytes.Buffer
+	var count int
+
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if nil != err {
+			return err
+		}
+		if "." == name {
+			return nil
+		}
+
+		if cfg.Excludes.match(name) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if 0 < len(cfg.Includes) && !cfg.Includes.match(name) {
+			return nil
+		}
+
+		fileinfo, err := entry.Info()
+		if nil != err {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if nil != err {
+			return err
+		}
+
+		var modtime = fileinfo.ModTime()
+
+		fmt.Fprintf(&files, "\t%s: strfs.RegularFile{\n", strconv.Quote(name))
+		fmt.Fprintf(&files, "\t\tFileContent: strfs.CreateContent(%s),\n", strconv.Quote(string(data)))
+		fmt.Fprintf(&files, "\t\tFileName: %s,\n", strconv.Quote(fileinfo.Name()))
+		fmt.Fprintf(&files, "\t\tFileModTime: time.Unix(%d, %d).UTC(),\n", modtime.Unix(), modtime.Nanosecond())
+		fmt.Fprintf(&files, "\t\tFileMode: %#o,\n", uint32(fileinfo.Mode().Perm()))
+		fmt.Fprintf(&files, "\t},\n")
+		count++
+
+		return nil
+	})
+	if nil != err {
+		return err
+	}
+
+	var buffer bytes.Buffer
+
+	fmt.Fprintf(&buffer, "// Code generated by strfs-gen. DO NOT EDIT.\n\n")
+	if "" != cfg.BuildTags {
+		fmt.Fprintf(&buffer, "//go:build %s\n\n", cfg.BuildTags)
+	}
+	fmt.Fprintf(&buffer, "package %s\n\n", cfg.PackageName)
+	fmt.Fprintf(&buffer, "import (\n")
+	if 0 < count {
+		fmt.Fprintf(&buffer, "\t\"time\"\n\n")
+	}
+	fmt.Fprintf(&buffer, "\t\"codeberg.org/reiver/go-strfs\"\n")
+	fmt.Fprintf(&buffer, ")\n\n")
+	fmt.Fprintf(&buffer, "var %s = strfs.FS{\n", cfg.VariableName)
+	files.WriteTo(&buffer)
+	fmt.Fprintf(&buffer, "}\n")
+
+	formatted, err := format.Source(buffer.Bytes())
+	if nil != err {
+		return fmt.Errorf("problem formatting generated code: %w", err)
+	}
+
+	_, err = writer.Write(formatted)
+	return err
+}
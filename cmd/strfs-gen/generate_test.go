@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing/fstest"
+	"time"
+
+	"testing"
+)
+
+// typeCheck fails the test if 'code' does not compile (i.e., does not parse, or does not type-check).
+func typeCheck(t *testing.T, code string) *ast.File {
+	t.Helper()
+
+	var fileset *token.FileSet = token.NewFileSet()
+
+	file, err := parser.ParseFile(fileset, "site_strfs.go", code, parser.ParseComments)
+	if nil != err {
+		t.Fatalf("Expected the generated code to parse but actually didn't: %s\nCODE:\n%s", err, code)
+	}
+
+	var typesConfig types.Config = types.Config{
+		Importer: importer.ForCompiler(fileset, "source", nil),
+	}
+	if _, err := typesConfig.Check(file.Name.Name, fileset, []*ast.File{file}, nil); nil != err {
+		t.Fatalf("Expected the generated code to type-check but actually didn't: %s\nCODE:\n%s", err, code)
+	}
+
+	return file
+}
+
+func TestGenerate(t *testing.T) {
+
+	var fsys fstest.MapFS = fstest.MapFS{
+		"index.html":      &fstest.MapFile{Data: []byte("<!DOCTYPE html>\n<html><body>Hello world!</body></html>"), Mode: 0644, ModTime: time.Unix(1670841014, 2)},
+		"css/style.css":   &fstest.MapFile{Data: []byte("body{color:red}"), Mode: 0600},
+		"gmni/file3.gmni": &fstest.MapFile{Data: []byte("once twice thrice")},
+		"gmni/file4.fngr": &fstest.MapFile{Data: []byte("once twice thrice fource")},
+		"drafts/x.html":   &fstest.MapFile{Data: []byte("draft")},
+		"generate.go":     &fstest.MapFile{Data: []byte("package www")},
+	}
+
+	var cfg config = config{
+		PackageName:  "www",
+		VariableName: "Site",
+		BuildTags:    "!nostrfs",
+	}
+	cfg.Excludes.Set("*.go")
+	cfg.Excludes.Set("drafts")
+	cfg.Includes.Set("*.html")
+	cfg.Includes.Set("css/*")
+	cfg.Includes.Set("gmni/*.gmni")
+
+	var buffer bytes.Buffer
+	if err := generate(&buffer, fsys, cfg); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var code string = buffer.String()
+
+	var file *ast.File = typeCheck(t, code)
+	if expected, actual := "www", file.Name.Name; expected != actual {
+		t.Errorf("The actual package-name was not what was expected.")
+		t.Logf("EXPECTED PACKAGE-NAME: %q", expected)
+		t.Logf("ACTUAL   PACKAGE-NAME: %q", actual)
+		return
+	}
+
+	for _, expected := range []string{
+		"//go:build !nostrfs",
+		"var Site = strfs.FS{",
+		`"index.html": strfs.RegularFile{`,
+		`FileContent: strfs.CreateContent("<!DOCTYPE html>\n<html><body>Hello world!</body></html>"),`,
+		"FileModTime: time.Unix(1670841014, 2).UTC(),",
+		"FileMode:    0644,",
+		`"css/style.css": strfs.RegularFile{`,
+		`FileName:    "style.css",`,
+		"FileMode:    0600,",
+		`"gmni/file3.gmni": strfs.RegularFile{`,
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected the generated code to contain %q but actually didn't.", expected)
+			t.Logf("CODE:\n%s", code)
+			return
+		}
+	}
+
+	for _, unexpected := range []string{
+		"gmni/file4.fngr",
+		"drafts/x.html",
+		"generate.go",
+	} {
+		if strings.Contains(code, unexpected) {
+			t.Errorf("Did not expect the generated code to contain %q but actually did.", unexpected)
+			t.Logf("CODE:\n%s", code)
+			return
+		}
+	}
+}
+
+func TestGenerate_noFiles(t *testing.T) {
+
+	tests := []struct{
+		FS       fstest.MapFS
+		Excludes []string
+	}{
+		{
+			FS: fstest.MapFS{},
+		},
+		{
+			FS: fstest.MapFS{
+				"generate.go":   &fstest.MapFile{Data: []byte("package www")},
+				"drafts/x.html": &fstest.MapFile{Data: []byte("draft")},
+			},
+			Excludes: []string{"*.go", "drafts"},
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var cfg config = config{
+			PackageName:  "www",
+			VariableName: "Site",
+		}
+		for _, exclude := range test.Excludes {
+			cfg.Excludes.Set(exclude)
+		}
+
+		var buffer bytes.Buffer
+		if err := generate(&buffer, test.FS, cfg); nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		typeCheck(t, buffer.String())
+	}
+}
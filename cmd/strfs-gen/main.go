@@ -0,0 +1,85 @@
+// Command strfs-gen walks a directory and generates a Go source file that declares
+// a strfs.FS (made up of strfs.RegularFile) with the contents, names, modification-times,
+// and permission-bits of each regular file in that directory.
+//
+// (Unlike embed.FS, the generated strfs.FS can be post-processed, and keeps the files' modification-times and modes.)
+//
+// Usage:
+//
+//	strfs-gen [flags] directory
+//
+// The flags are:
+//
+//	-o file
+//		write the generated Go code to 'file' (rather than to STDOUT)
+//	-pkg name
+//		the package name of the generated Go code (defaults to $GOPACKAGE, or "main")
+//	-var name
+//		the name of the generated strfs.FS variable (defaults to "Files")
+//	-include glob
+//		only include files that match 'glob' (can be given more than once)
+//	-exclude glob
+//		exclude files (and directories) that match 'glob' (can be given more than once)
+//	-tags expression
+//		add a "//go:build expression" line to the generated Go code
+//
+// A glob matches a file if it matches either the file's whole (slash-separated) path, or just the file's name.
+// Symbolic links and other irregular files are skipped.
+//
+// Example usage with go:generate:
+//
+//	//go:generate strfs-gen -o files_strfs.go -var Files -exclude "*.go" ./www
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	var cfg config
+	var output string
+
+	var defaultPackageName string = os.Getenv("GOPACKAGE")
+	if "" == defaultPackageName {
+		defaultPackageName = "main"
+	}
+
+	flag.StringVar(&output, "o", "", "write the generated Go code to this file (rather than to STDOUT)")
+	flag.StringVar(&cfg.PackageName, "pkg", defaultPackageName, "the package name of the generated Go code")
+	flag.StringVar(&cfg.VariableName, "var", "Files", "the name of the generated strfs.FS variable")
+	flag.Var(&cfg.Includes, "include", "only include files that match this glob (can be given more than once)")
+	flag.Var(&cfg.Excludes, "exclude", "exclude files and directories that match this glob (can be given more than once)")
+	flag.StringVar(&cfg.BuildTags, "tags", "", "add a //go:build line with this expression to the generated Go code")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: strfs-gen [flags] directory\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if 1 != flag.NArg() {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var buffer bytes.Buffer
+	if err := generate(&buffer, os.DirFS(flag.Arg(0)), cfg); nil != err {
+		fmt.Fprintf(os.Stderr, "strfs-gen: %s\n", err)
+		os.Exit(1)
+	}
+
+	if "" != output {
+		if err := os.WriteFile(output, buffer.Bytes(), 0644); nil != err {
+			fmt.Fprintf(os.Stderr, "strfs-gen: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if _, err := os.Stdout.Write(buffer.Bytes()); nil != err {
+		fmt.Fprintf(os.Stderr, "strfs-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"codeberg.org/reiver/go-strfs"
+)
+
+// The bundle formats that the strfs command knows about.
+const (
+	formatBundle = "strfs"
+	formatTar    = "tar"
+	formatTxtar  = "txtar"
+	formatZip    = "zip"
+)
+
+// detectFormat returns the bundle format to use for 'filename'.
+// If 'format' is not empty, then it is used. Otherwise the format is guessed from the file extension.
+func detectFormat(filename string, format string) (string, error) {
+	if "" != format {
+		switch format {
+		case formatBundle, formatTar, formatTxtar, formatZip:
+			return format, nil
+		default:
+			return "", fmt.Errorf("unknown format %q (expected %q, %q, %q, or %q)", format, formatBundle, formatTar, formatTxtar, formatZip)
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".strfs":
+		return formatBundle, nil
+	case ".tar":
+		return formatTar, nil
+	case ".txtar", ".txt":
+		return formatTxtar, nil
+	case ".zip":
+		return formatZip, nil
+	default:
+		return "", fmt.Errorf("cannot tell the format of %q from its name (use the -format flag)", filename)
+	}
+}
+
+// loadBundle reads the bundle at 'filename' (or from 'stdin', if 'filename' is "-").
+func loadBundle(stdin io.Reader, filename string, format string) (strfs.FS, error) {
+	format, err := detectFormat(filename, format)
+	if nil != err {
+		return nil, err
+	}
+
+	var data []byte
+	if "-" == filename {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(filename)
+	}
+	if nil != err {
+		return nil, err
+	}
+
+	switch format {
+	case formatBundle:
+		return strfs.DecodeBundle(string(data))
+	case formatTar:
+		return strfs.ImportTar(bytes.NewReader(data))
+	case formatTxtar:
+		return strfs.ImportTxtar(bytes.NewReader(data))
+	case formatZip:
+		return strfs.ImportZip(bytes.NewReader(data), int64(len(data)))
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// saveBundle writes 'fsys' as a bundle to 'filename' (or to 'stdout', if 'filename' is "-").
+func saveBundle(stdout io.Writer, filename string, format string, fsys strfs.FS) error {
+	format, err := detectFormat(filename, format)
+	if nil != err {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	switch format {
+	case formatBundle:
+		err = strfs.ExportBundle(&buffer, fsys)
+	case formatTar:
+		err = strfs.ExportTar(&buffer, fsys)
+	case formatTxtar:
+		err = strfs.ExportTxtar(&buffer, fsys)
+	case formatZip:
+		err = strfs.ExportZip(&buffer, fsys)
+	default:
+		err = fmt.Errorf("unknown format %q", format)
+	}
+	if nil != err {
+		return err
+	}
+
+	if "-" == filename {
+		_, err = stdout.Write(buffer.Bytes())
+		return err
+	}
+	return os.WriteFile(filename, buffer.Bytes(), 0644)
+}
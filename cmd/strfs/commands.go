@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"time"
+
+	"codeberg.org/reiver/go-strfs"
+)
+
+// command is a sub-command of the strfs command.
+type command struct {
+	Usage string
+	Run   func(stdin io.Reader, stdout io.Writer, bundle strfs.FS, args []string) error
+}
+
+var commands = map[string]command{
+	"cat": {
+		Usage: "cat [-format f] bundle path...",
+		Run:   runCat,
+	},
+	"list": {
+		Usage: "list [-format f] bundle",
+		Run:   runList,
+	},
+	"stat": {
+		Usage: "stat [-format f] bundle path...",
+		Run:   runStat,
+	},
+	"tree": {
+		Usage: "tree [-format f] bundle",
+		Run:   runTree,
+	},
+}
+
+func runCat(stdin io.Reader, stdout io.Writer, bundle strfs.FS, args []string) error {
+	for _, name := range args {
+		data, err := fs.ReadFile(bundle, name)
+		if nil != err {
+			return err
+		}
+		if _, err := stdout.Write(data); nil != err {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runList(stdin io.Reader, stdout io.Writer, bundle strfs.FS, args []string) error {
+	var names []string
+	for name := range bundle {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintln(stdout, name)
+	}
+
+	return nil
+}
+
+func runStat(stdin io.Reader, stdout io.Writer, bundle strfs.FS, args []string) error {
+	for _, name := range args {
+		fileinfo, err := fs.Stat(bundle, name)
+		if nil != err {
+			return err
+		}
+
+		fmt.Fprintf(stdout, "%s\t%v\t%d\t%s\n", name, fileinfo.Mode(), fileinfo.Size(), fileinfo.ModTime().Format(time.RFC3339Nano))
+	}
+
+	return nil
+}
+
+func runTree(stdin io.Reader, stdout io.Writer, bundle strfs.FS, args []string) error {
+	fmt.Fprintln(stdout, ".")
+	return tree(stdout, bundle, ".", "")
+}
+
+func tree(stdout io.Writer, bundle strfs.FS, dir string, indent string) error {
+	entries, err := fs.ReadDir(bundle, dir)
+	if nil != err {
+		return err
+	}
+
+	for i, entry := range entries {
+		var branch, nextIndent string = "├── ", "│   "
+		if len(entries)-1 == i {
+			branch, nextIndent = "└── ", "    "
+		}
+
+		fmt.Fprintf(stdout, "%s%s%s\n", indent, branch, entry.Name())
+
+		if entry.IsDir() {
+			var name string = entry.Name()
+			if "." != dir {
+				name = dir + "/" + name
+			}
+			if err := tree(stdout, bundle, name, indent+nextIndent); nil != err {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// runConvert handles the "convert" sub-command (which, unlike the other sub-commands, has two bundles).
+func runConvert(stdin io.Reader, stdout io.Writer, args []string) error {
+	var flagset *flag.FlagSet = flag.NewFlagSet("convert", flag.ContinueOnError)
+	flagset.SetOutput(io.Discard)
+
+	var from, to string
+	flagset.StringVar(&from, "from", "", "the format of the input bundle")
+	flagset.StringVar(&to, "to", "", "the format of the output bundle")
+	if err := flagset.Parse(args); nil != err {
+		return err
+	}
+	if 2 != flagset.NArg() {
+		return fmt.Errorf("usage: strfs convert [-from f] [-to f] input output")
+	}
+
+	bundle, err := loadBundle(stdin, flagset.Arg(0), from)
+	if nil != err {
+		return err
+	}
+
+	return saveBundle(stdout, flagset.Arg(1), to, bundle)
+}
@@ -0,0 +1,100 @@
+// Command strfs lets you inspect, and convert between the formats of, strfs bundles.
+//
+// A strfs bundle is a file that holds a tree of files — a strfs bundle (see strfs.ExportBundle), a tar archive, a zip archive, or a txtar archive.
+// (The strfs command is built on the strfs package's Import and Export functions for each of those formats.)
+//
+// Usage:
+//
+//	strfs list    [-format f] bundle
+//	strfs cat     [-format f] bundle path...
+//	strfs stat    [-format f] bundle path...
+//	strfs tree    [-format f] bundle
+//	strfs convert [-from f] [-to f] input output
+//
+// The format of a bundle is guessed from its file extension (".strfs", ".tar", ".zip", ".txtar", or ".txt"),
+// unless it is given with a flag as "strfs", "tar", "zip", or "txtar".
+//
+// A bundle name of "-" means STDIN (for an input bundle) or STDOUT (for an output bundle);
+// the format must then be given with a flag.
+//
+// Example usage:
+//
+//	strfs tree site.tar
+//	strfs cat site.tar index.html
+//	strfs convert site.tar site.zip
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the strfs command with the command-line arguments 'args', and returns the exit code.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) < 1 {
+		usage(stderr)
+		return 2
+	}
+
+	var name string = args[0]
+	args = args[1:]
+
+	if "convert" == name {
+		if err := runConvert(stdin, stdout, args); nil != err {
+			fmt.Fprintf(stderr, "strfs convert: %s\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	cmd, found := commands[name]
+	if !found {
+		fmt.Fprintf(stderr, "strfs: unknown command %q\n", name)
+		usage(stderr)
+		return 2
+	}
+
+	var flagset *flag.FlagSet = flag.NewFlagSet(name, flag.ContinueOnError)
+	flagset.SetOutput(io.Discard)
+
+	var format string
+	flagset.StringVar(&format, "format", "", "the format of the bundle")
+	if err := flagset.Parse(args); nil != err || flagset.NArg() < 1 {
+		fmt.Fprintf(stderr, "usage: strfs %s\n", cmd.Usage)
+		return 2
+	}
+
+	bundle, err := loadBundle(stdin, flagset.Arg(0), format)
+	if nil != err {
+		fmt.Fprintf(stderr, "strfs %s: %s\n", name, err)
+		return 1
+	}
+
+	if err := cmd.Run(stdin, stdout, bundle, flagset.Args()[1:]); nil != err {
+		fmt.Fprintf(stderr, "strfs %s: %s\n", name, err)
+		return 1
+	}
+
+	return 0
+}
+
+func usage(stderr io.Writer) {
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(stderr, "usage:\n")
+	for _, name := range names {
+		fmt.Fprintf(stderr, "\tstrfs %s\n", commands[name].Usage)
+	}
+	fmt.Fprintf(stderr, "\tstrfs convert [-from f] [-to f] input output\n")
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"testing"
+)
+
+const exampleTxtar string =
+	"-- file1.txt --"+"\n"+
+	"once"+"\n"+
+	"-- gmni/file3.gmni --"+"\n"+
+	"once twice thrice"+"\n"+
+	"-- gmni/fngr/file4.fngr --"+"\n"+
+	"once twice thrice fource"+"\n"
+
+func TestRun(t *testing.T) {
+
+	tests := []struct{
+		Args     []string
+		Expected string
+	}{
+		{
+			Args:     []string{"list", "-format", "txtar", "-"},
+			Expected: "file1.txt"+"\n"+"gmni/file3.gmni"+"\n"+"gmni/fngr/file4.fngr"+"\n",
+		},
+		{
+			Args:     []string{"cat", "-format", "txtar", "-", "gmni/file3.gmni", "file1.txt"},
+			Expected: "once twice thrice"+"\n"+"once"+"\n",
+		},
+		{
+			Args:     []string{"stat", "-format", "txtar", "-", "file1.txt"},
+			Expected: "file1.txt"+"\t"+"----------"+"\t"+"5"+"\t"+"0001-01-01T00:00:00Z"+"\n",
+		},
+		{
+			Args:     []string{"tree", "-format", "txtar", "-"},
+			Expected:
+				"."+"\n"+
+				"├── file1.txt"+"\n"+
+				"└── gmni"+"\n"+
+				"    ├── file3.gmni"+"\n"+
+				"    └── fngr"+"\n"+
+				"        └── file4.fngr"+"\n",
+		},
+		{
+			Args:     []string{"convert", "-from", "txtar", "-to", "txtar", "-", "-"},
+			Expected: exampleTxtar,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var stdout, stderr bytes.Buffer
+
+		if expected, actual := 0, run(test.Args, strings.NewReader(exampleTxtar), &stdout, &stderr); expected != actual {
+			t.Errorf("For test #%d, the actual exit-code is not what was expected.", testNumber)
+			t.Logf("EXPECTED EXIT-CODE: %d", expected)
+			t.Logf("ACTUAL   EXIT-CODE: %d", actual)
+			t.Logf("ARGS: %#v", test.Args)
+			t.Logf("STDERR: %q", stderr.String())
+			continue
+		}
+
+		if expected, actual := test.Expected, stdout.String(); expected != actual {
+			t.Errorf("For test #%d, the actual output is not what was expected.", testNumber)
+			t.Logf("EXPECTED OUTPUT:\n%s", expected)
+			t.Logf("ACTUAL   OUTPUT:\n%s", actual)
+			t.Logf("ARGS: %#v", test.Args)
+			continue
+		}
+	}
+}
+
+func TestRun_convert(t *testing.T) {
+
+	var dir string = t.TempDir()
+
+	var txtarPath string = filepath.Join(dir, "bundle.txtar")
+	var tarPath   string = filepath.Join(dir, "bundle.tar")
+	var zipPath   string = filepath.Join(dir, "bundle.zip")
+	var strfsPath string = filepath.Join(dir, "bundle.strfs")
+
+	if err := os.WriteFile(txtarPath, []byte(exampleTxtar), 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	for _, args := range [][]string{
+		{"convert", txtarPath, tarPath},
+		{"convert", tarPath, zipPath},
+		{"convert", zipPath, strfsPath},
+	} {
+		var stdout, stderr bytes.Buffer
+		if 0 != run(args, nil, &stdout, &stderr) {
+			t.Fatalf("Did not expect an error but actually got one: %s", stderr.String())
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if 0 != run([]string{"cat", strfsPath, "gmni/fngr/file4.fngr"}, nil, &stdout, &stderr) {
+		t.Fatalf("Did not expect an error but actually got one: %s", stderr.String())
+	}
+
+	if expected, actual := "once twice thrice fource"+"\n", stdout.String(); expected != actual {
+		t.Errorf("The actual output is not what was expected.")
+		t.Logf("EXPECTED OUTPUT: %q", expected)
+		t.Logf("ACTUAL   OUTPUT: %q", actual)
+		return
+	}
+}
@@ -1,8 +1,13 @@
 package strfs
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"io"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/reiver/go-erorr"
 )
 
 // Content represents the content part of a file.
@@ -11,6 +16,10 @@ import (
 //
 // But, for example, is used to create a RegularFile (which maps to a fs.File).
 //
+// Usually a strfs.Content wraps a single string (see CreateContent).
+// But it can also be a view of something that is NOT a single string — such as a strfs.NormalizedContent (see its Content method).
+// Such a strfs.Content reads from what it is a view of, and only joins it into a single string if its String method is called.
+//
 // Example usage:
 //
 //	var content strfs.Content = strfs.CreateContent("<!DOCTYPE html>"+"\n"+"<html></html>")
@@ -22,15 +31,27 @@ import (
 //	}
 type Content struct{
 	value string
+	source *internalContentSource
 	reader io.ReadSeeker
 	size int64
 	closed bool
+	lines *internalLineIndex
 }
 
 // A trick to make sure strfs.Content fits the io.ReadCloser interface.
 // This is a compile-time check.
 var _ io.ReadSeekCloser = &Content{}
 
+var (
+	// A trick to make sure strfs.Content fits the json.Marshaler interface.
+	// This is a compile-time check.
+	_ json.Marshaler = Content{}
+
+	// A trick to make sure strfs.Content fits the json.Unmarshaler interface.
+	// This is a compile-time check.
+	_ json.Unmarshaler = &Content{}
+)
+
 // CreateContent returns a strfs.Content whose content is the string given to it.
 //
 // Example usage:
@@ -50,6 +71,7 @@ func CreateContent(value string) Content {
 		value:value,
 		reader:reader,
 		size:size,
+		lines:&internalLineIndex{},
 	}
 }
 
@@ -103,10 +125,98 @@ func (receiver *Content) Closed() bool {
 	return receiver.closed
 }
 
+// The kinds of strfs.Content in the gob encoding.
+const (
+	gobContentEmpty = 0
+	gobContentValue = 1
+)
+
+// GobDecode makes *strfs.Content fit the gob.GobDecoder interface.
+//
+// See GobEncode.
+func (receiver *Content) GobDecode(data []byte) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if len(data) < 1 {
+		return errGobTooShort
+	}
+
+	switch data[0] {
+	case gobContentEmpty:
+		*receiver = EmptyContent()
+	case gobContentValue:
+		*receiver = CreateContent(string(data[1:]))
+	default:
+		return erorr.Errorf("unknown strfs.Content gob kind %d", data[0])
+	}
+
+	return nil
+}
+
+// GobEncode makes strfs.Content fit the gob.GobEncoder interface.
+//
+// The string that the strfs.Content is wrapping is kept exactly (as is whether the strfs.Content is empty — see EmptyContent).
+// Whether it is closed, and where it has been read up to, are NOT kept.
+func (receiver Content) GobEncode() ([]byte, error) {
+	if EmptyContent() == receiver {
+		return []byte{gobContentEmpty}, nil
+	}
+
+	var value string = receiver.String()
+
+	var data []byte = make([]byte, 1+len(value))
+	data[0] = gobContentValue
+	copy(data[1:], value)
+
+	return data, nil
+}
+
 func (*Content) IsDir() bool {
 	return false
 }
 
+// MarshalJSON makes strfs.Content fit the json.Marshaler interface.
+//
+// If the string that the strfs.Content is wrapping is valid UTF-8, then it is marshaled as a JSON string.
+// Otherwise it is marshaled (so that it is kept exactly) as a JSON object with its base64 encoding, such as:
+//
+//	{"base64":"//4AAQ=="}
+//
+// An empty strfs.Content (see EmptyContent) is marshaled as a JSON null.
+//
+// Whether it is closed, and where it has been read up to, are NOT kept.
+func (receiver Content) MarshalJSON() ([]byte, error) {
+	if EmptyContent() == receiver {
+		return []byte("null"), nil
+	}
+
+	var value string = receiver.String()
+
+	if utf8.ValidString(value) {
+		return json.Marshal(value)
+	}
+
+	return json.Marshal(struct{
+		Base64 string `json:"base64"`
+	}{
+		Base64: base64.StdEncoding.EncodeToString([]byte(value)),
+	})
+}
+
+// MarshalText makes strfs.Content fit the encoding.TextMarshaler interface.
+//
+// The text is the string that the strfs.Content is wrapping.
+//
+// Text has no way of saying "empty" (see EmptyContent), so an empty strfs.Content is marshaled as an empty text —
+// which UnmarshalText turns into strfs.CreateContent(""), which is NOT empty.
+// (Use MarshalJSON, or GobEncode, if whether the strfs.Content is empty needs to be kept.)
+//
+// Whether it is closed, and where it has been read up to, are NOT kept.
+func (receiver Content) MarshalText() ([]byte, error) {
+	return []byte(receiver.String()), nil
+}
+
 // Read reads up to len(p) bytes into 'p'.
 // Read returns the number of bytes actually read, and any errors it encountered.
 //
@@ -137,9 +247,6 @@ func (receiver *Content) Read(p []byte) (int, error) {
 	if nil == receiver {
 		return 0, errNilReceiver
 	}
-	if nil == p {
-		return 0, errNilByteSlice
-	}
 
 	if receiver.Closed() {
 		return 0, errClosed
@@ -153,6 +260,9 @@ func (receiver *Content) Read(p []byte) (int, error) {
 	return receiver.reader.Read(p)
 }
 
+// Seek sets the offset for the next Read.
+//
+// Seek makes strfs.Content fit the io.Seeker interface.
 func (receiver *Content) Seek(offset int64, whence int) (int64, error) {
 	if nil == receiver {
 		var nada int64
@@ -163,7 +273,12 @@ func (receiver *Content) Seek(offset int64, whence int) (int64, error) {
 		return nada, errNilReadSeeker
 	}
 
-	return receiver.Seek(offset, whence)
+	if receiver.Closed() {
+		var nada int64
+		return nada, errClosed
+	}
+
+	return receiver.reader.Seek(offset, whence)
 }
 
 // Size returns the of the string given to it as the number of bytes.
@@ -177,11 +292,70 @@ func (receiver *Content) Size() int64 {
 
 // String retusn the value of the string that strfs.Content is wrapping.
 //
+// If the strfs.Content is a view of something that is NOT a single string, then String joins it into a single string
+// (the first time String is called).
+//
 // String makes *strfs.Content fit the fmt.Stringer interface.
 func (receiver *Content) String() string {
 	if nil == receiver {
 		return ""
 	}
 
+	if nil != receiver.source {
+		return receiver.source.String()
+	}
+
 	return receiver.value
 }
+
+// UnmarshalJSON makes *strfs.Content fit the json.Unmarshaler interface.
+//
+// See MarshalJSON.
+func (receiver *Content) UnmarshalJSON(data []byte) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	if "null" == string(data) {
+		*receiver = EmptyContent()
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); nil == err {
+		*receiver = CreateContent(value)
+		return nil
+	}
+
+	var encoded struct{
+		Base64 *string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); nil != err {
+		return erorr.Errorf("strfs.Content must be a JSON string, a JSON object with \"base64\", or null: %w", err)
+	}
+	if nil == encoded.Base64 {
+		return erorr.Errorf("strfs.Content JSON object is missing \"base64\"")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(*encoded.Base64)
+	if nil != err {
+		return erorr.Errorf("strfs.Content has invalid base64: %w", err)
+	}
+
+	*receiver = CreateContent(string(decoded))
+	return nil
+}
+
+// UnmarshalText makes *strfs.Content fit the encoding.TextUnmarshaler interface.
+//
+// The strfs.Content it makes is never empty (see EmptyContent) — even if 'text' is empty.
+//
+// See MarshalText.
+func (receiver *Content) UnmarshalText(text []byte) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	*receiver = CreateContent(string(text))
+	return nil
+}
@@ -0,0 +1,255 @@
+package strfs
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// internalLineIndex is the (lazily built) index of where each line of a strfs.Content starts.
+//
+// It is built the first time it is needed, and is then shared by all the copies of the strfs.Content.
+type internalLineIndex struct {
+	once   sync.Once
+	starts []int64
+}
+
+// LineCol is a position in a strfs.Content, as a line and a column.
+//
+// Lines and columns start at 1.
+type LineCol struct {
+	// Line is the line-number.
+	Line int
+
+	// Column is the column, in bytes.
+	Column int
+
+	// RuneColumn is the column, in runes (i.e., UTF-8 encoded Unicode code-points).
+	// A byte that is not part of valid UTF-8 counts as one rune.
+	RuneColumn int
+}
+
+// lineStarts returns the byte-offset of the start of each line.
+//
+// The first time it is called, it scans the whole string for "\n".
+// After that, it just returns what it found the first time.
+func (receiver *Content) lineStarts() []int64 {
+	if nil == receiver.lines {
+		return scanLineStarts(receiver.String())
+	}
+
+	receiver.lines.once.Do(func() {
+		receiver.lines.starts = scanLineStarts(receiver.String())
+	})
+
+	return receiver.lines.starts
+}
+
+// scanLineStarts returns the byte-offset of the start of each line of 'value'.
+func scanLineStarts(value string) []int64 {
+	var starts []int64 = make([]int64, 1, 1+strings.Count(value, "\n"))
+
+	var offset int
+	for {
+		index := strings.IndexByte(value[offset:], '\n')
+		if index < 0 {
+			break
+		}
+
+		offset += index + 1
+		starts = append(starts, int64(offset))
+	}
+
+	return starts
+}
+
+// lineBounds returns the byte-offset of the start of line 'line', and the byte-offset of its end (i.e., of its "\n", or else the end of the content).
+func (receiver *Content) lineBounds(line int) (int64, int64, error) {
+	var starts []int64 = receiver.lineStarts()
+
+	if line < 1 || len(starts) < line {
+		return 0, 0, errLineOutOfBounds
+	}
+
+	var start int64 = starts[line-1]
+	var end int64 = receiver.size
+	if line < len(starts) {
+		end = starts[line] - 1
+	}
+
+	return start, end, nil
+}
+
+// Line returns line 'line' (where the first line is 1), without the "\n" (or "\r\n") at the end of it.
+//
+// After the first call to any of the line methods (which scans the whole string once), Line does not scan the string.
+//
+// Example usage:
+//
+//	var content strfs.Content = strfs.CreateContent("once"+"\n"+"twice"+"\n"+"thrice"+"\n")
+//
+//	line, err := content.Line(2)
+//
+//	// line == "twice"
+func (receiver *Content) Line(line int) (string, error) {
+	if nil == receiver {
+		return "", errNilReceiver
+	}
+
+	start, end, err := receiver.lineBounds(line)
+	if nil != err {
+		return "", err
+	}
+
+	return strings.TrimSuffix(receiver.String()[start:end], "\r"), nil
+}
+
+// LineColToOffset returns the byte-offset of the position at line 'line', and (byte) column 'column'.
+//
+// The column can be one past the last byte of the line — i.e., the position of the "\n" at the end of the line
+// (or, for the last line, the end of the content).
+//
+// See also LineRuneColToOffset.
+func (receiver *Content) LineColToOffset(line int, column int) (int64, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	start, end, err := receiver.lineBounds(line)
+	if nil != err {
+		return 0, err
+	}
+
+	if column < 1 || end-start+1 < int64(column) {
+		return 0, errColumnOutOfBounds
+	}
+
+	return start + int64(column) - 1, nil
+}
+
+// LineCount returns the number of lines.
+//
+// Each "\n" ends a line.
+// The (possibly empty) text after the last "\n" is the last line — so a content that ends with "\n" has an empty last line,
+// and an empty content has 1 (empty) line.
+func (receiver *Content) LineCount() int {
+	if nil == receiver {
+		return 0
+	}
+
+	return len(receiver.lineStarts())
+}
+
+// LineRuneColToOffset returns the byte-offset of the position at line 'line', and rune column 'column'.
+//
+// The column can be one past the last rune of the line — i.e., the position of the "\n" at the end of the line
+// (or, for the last line, the end of the content).
+//
+// See also LineColToOffset.
+func (receiver *Content) LineRuneColToOffset(line int, column int) (int64, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	start, end, err := receiver.lineBounds(line)
+	if nil != err {
+		return 0, err
+	}
+
+	if column < 1 {
+		return 0, errColumnOutOfBounds
+	}
+
+	var offset int64 = start
+	for runeColumn := 1; runeColumn < column; runeColumn++ {
+		if end <= offset {
+			return 0, errColumnOutOfBounds
+		}
+
+		_, size := utf8.DecodeRuneInString(receiver.String()[offset:end])
+		offset += int64(size)
+	}
+
+	return offset, nil
+}
+
+// LinesReader returns an io.Reader for the lines from line 'first' to line 'last' (inclusive),
+// including the "\n" at the end of each of them.
+//
+// The string that the strfs.Content is wrapping is NOT copied (see RangeReader).
+//
+// Example usage:
+//
+//	var content strfs.Content = strfs.CreateContent("once"+"\n"+"twice"+"\n"+"thrice"+"\n"+"fource"+"\n")
+//
+//	reader, err := content.LinesReader(2, 3)
+//
+//	// reader reads "twice\nthrice\n"
+func (receiver *Content) LinesReader(first int, last int) (io.Reader, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+	if last < first {
+		return nil, errLineOutOfBounds
+	}
+
+	start, _, err := receiver.lineBounds(first)
+	if nil != err {
+		return nil, err
+	}
+
+	_, end, err := receiver.lineBounds(last)
+	if nil != err {
+		return nil, err
+	}
+	if end < receiver.size {
+		end++
+	}
+
+	return RangeReader(*receiver, ByteRange{Start:start, Length:end-start})
+}
+
+// OffsetToLineCol returns the line, and the (byte and rune) columns, of the byte-offset 'offset'.
+//
+// The offset can be anything from 0 to Size (inclusive).
+//
+// Example usage:
+//
+//	var content strfs.Content = strfs.CreateContent("once"+"\n"+"😈 twice"+"\n")
+//
+//	linecol, err := content.OffsetToLineCol(10)
+//
+//	// linecol.Line       == 2
+//	// linecol.Column     == 6
+//	// linecol.RuneColumn == 3
+func (receiver *Content) OffsetToLineCol(offset int64) (LineCol, error) {
+	if nil == receiver {
+		return LineCol{}, errNilReceiver
+	}
+	if offset < 0 || receiver.size < offset {
+		return LineCol{}, errOffsetOutOfBounds
+	}
+
+	var starts []int64 = receiver.lineStarts()
+
+	// Binary search for the last line that starts at or before 'offset'.
+	var low int = 0
+	var high int = len(starts) - 1
+	for low < high {
+		var middle int = (low + high + 1) / 2
+		if starts[middle] <= offset {
+			low = middle
+		} else {
+			high = middle - 1
+		}
+	}
+
+	var start int64 = starts[low]
+
+	return LineCol{
+		Line:       low + 1,
+		Column:     int(offset-start) + 1,
+		RuneColumn: utf8.RuneCountInString(receiver.String()[start:offset]) + 1,
+	}, nil
+}
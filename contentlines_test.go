@@ -0,0 +1,184 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"io"
+
+	"testing"
+)
+
+func TestContent_Line(t *testing.T) {
+
+	tests := []struct{
+		Content       string
+		ExpectedLines []string
+	}{
+		{
+			Content:       "",
+			ExpectedLines: []string{""},
+		},
+		{
+			Content:       "once",
+			ExpectedLines: []string{"once"},
+		},
+		{
+			Content:       "once"+"\n",
+			ExpectedLines: []string{"once", ""},
+		},
+		{
+			Content:       "once"+"\n"+"twice"+"\r\n"+"\n"+"thrice",
+			ExpectedLines: []string{"once", "twice", "", "thrice"},
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var content strfs.Content = strfs.CreateContent(test.Content)
+
+		if expected, actual := len(test.ExpectedLines), content.LineCount(); expected != actual {
+			t.Errorf("For test #%d, the actual line-count was not what was expected: expected %d, actually %d", testNumber, expected, actual)
+			t.Logf("CONTENT: %q", test.Content)
+			continue
+		}
+
+		for index, expected := range test.ExpectedLines {
+			actual, err := content.Line(index+1)
+			if nil != err {
+				t.Errorf("For test #%d and line %d, did not expect an error but actually got one: (%T) %s", testNumber, index+1, err, err)
+				continue
+			}
+			if expected != actual {
+				t.Errorf("For test #%d and line %d, the actual line was not what was expected.", testNumber, index+1)
+				t.Logf("EXPECTED: %q", expected)
+				t.Logf("ACTUAL:   %q", actual)
+				continue
+			}
+		}
+
+		if _, err := content.Line(0); nil == err {
+			t.Errorf("For test #%d, expected an error for line 0 but did not actually get one.", testNumber)
+		}
+		if _, err := content.Line(len(test.ExpectedLines)+1); nil == err {
+			t.Errorf("For test #%d, expected an error for the line after the last line but did not actually get one.", testNumber)
+		}
+	}
+}
+
+func TestContent_OffsetToLineCol(t *testing.T) {
+
+	var content strfs.Content = strfs.CreateContent("once"+"\n"+"😈 twice"+"\n"+"thrice")
+
+	tests := []struct{
+		Offset   int64
+		Expected strfs.LineCol
+	}{
+		{Offset:  0, Expected: strfs.LineCol{Line:1, Column:1,  RuneColumn:1}},
+		{Offset:  4, Expected: strfs.LineCol{Line:1, Column:5,  RuneColumn:5}},
+		{Offset:  5, Expected: strfs.LineCol{Line:2, Column:1,  RuneColumn:1}},
+		{Offset:  9, Expected: strfs.LineCol{Line:2, Column:5,  RuneColumn:2}},
+		{Offset: 10, Expected: strfs.LineCol{Line:2, Column:6,  RuneColumn:3}},
+		{Offset: 15, Expected: strfs.LineCol{Line:2, Column:11, RuneColumn:8}},
+		{Offset: 16, Expected: strfs.LineCol{Line:3, Column:1,  RuneColumn:1}},
+		{Offset: 22, Expected: strfs.LineCol{Line:3, Column:7,  RuneColumn:7}},
+	}
+
+	for testNumber, test := range tests {
+
+		actual, err := content.OffsetToLineCol(test.Offset)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		if expected := test.Expected; expected != actual {
+			t.Errorf("For test #%d, the actual line and column was not what was expected.", testNumber)
+			t.Logf("OFFSET: %d", test.Offset)
+			t.Logf("EXPECTED: %#v", expected)
+			t.Logf("ACTUAL:   %#v", actual)
+			continue
+		}
+
+		{
+			offset, err := content.LineColToOffset(test.Expected.Line, test.Expected.Column)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+				continue
+			}
+			if expected, actual := test.Offset, offset; expected != actual {
+				t.Errorf("For test #%d, the actual offset from the (byte) column was not what was expected: expected %d, actually %d", testNumber, expected, actual)
+				continue
+			}
+		}
+
+		{
+			offset, err := content.LineRuneColToOffset(test.Expected.Line, test.Expected.RuneColumn)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+				continue
+			}
+			if expected, actual := test.Offset, offset; expected != actual {
+				t.Errorf("For test #%d, the actual offset from the rune column was not what was expected: expected %d, actually %d", testNumber, expected, actual)
+				continue
+			}
+		}
+	}
+
+	if _, err := content.OffsetToLineCol(23); nil == err {
+		t.Errorf("Expected an error for an offset past the end, but did not actually get one.")
+	}
+	if _, err := content.LineColToOffset(1, 6); nil == err {
+		t.Errorf("Expected an error for a column past the end of the line, but did not actually get one.")
+	}
+	if _, err := content.LineRuneColToOffset(2, 9); nil == err {
+		t.Errorf("Expected an error for a rune column past the end of the line, but did not actually get one.")
+	}
+	if _, err := content.LineColToOffset(4, 1); nil == err {
+		t.Errorf("Expected an error for a line past the last line, but did not actually get one.")
+	}
+}
+
+func TestContent_LinesReader(t *testing.T) {
+
+	var content strfs.Content = strfs.CreateContent("once"+"\n"+"twice"+"\n"+"thrice"+"\n"+"fource")
+
+	tests := []struct{
+		First    int
+		Last     int
+		Expected string
+	}{
+		{First:1, Last:1, Expected: "once"+"\n"},
+		{First:2, Last:3, Expected: "twice"+"\n"+"thrice"+"\n"},
+		{First:3, Last:4, Expected: "thrice"+"\n"+"fource"},
+		{First:1, Last:4, Expected: "once"+"\n"+"twice"+"\n"+"thrice"+"\n"+"fource"},
+	}
+
+	for testNumber, test := range tests {
+
+		reader, err := content.LinesReader(test.First, test.Last)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		data, err := io.ReadAll(reader)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, string(data); expected != actual {
+			t.Errorf("For test #%d, the actual lines were not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+
+	if _, err := content.LinesReader(3, 2); nil == err {
+		t.Errorf("Expected an error for a last line before the first line, but did not actually get one.")
+	}
+	if _, err := content.LinesReader(4, 5); nil == err {
+		t.Errorf("Expected an error for a line past the last line, but did not actually get one.")
+	}
+}
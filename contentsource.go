@@ -0,0 +1,85 @@
+package strfs
+
+import (
+	"io"
+	"sync"
+)
+
+// internalContentSource is what a strfs.Content reads from when it is a view of something that is NOT a single string —
+// such as a strfs.NormalizedContent.
+//
+// The pieces of an internalContentSource never change after it is created, so it is shared by all the copies of the strfs.Content.
+//
+// The pieces are only joined into a single string the first time String is called.
+type internalContentSource struct {
+	pieces    internalPieces
+	once      sync.Once
+	flattened string
+}
+
+// createPiecesContent returns a strfs.Content whose content is the pieces given to it.
+//
+// The pieces are NOT joined into a single string (unless the String method of the returned strfs.Content is called).
+func createPiecesContent(pieces internalPieces) Content {
+	pieces.offset = 0
+
+	return createSourceContent(&internalContentSource{
+		pieces:pieces,
+	})
+}
+
+// createSourceContent returns a (new) strfs.Content, with its own read offset, that reads from 'source'.
+func createSourceContent(source *internalContentSource) Content {
+	var size int64 = source.pieces.Size()
+
+	return Content{
+		source:source,
+		reader:io.NewSectionReader(&source.pieces, 0, size),
+		size:size,
+		lines:&internalLineIndex{},
+	}
+}
+
+// appendTo appends the content of a strfs.Content to 'pieces' (without joining anything into a single string).
+func (receiver *Content) appendTo(pieces *internalPieces) {
+	if nil == receiver.source {
+		pieces.append(receiver.value)
+		return
+	}
+
+	for _, part := range receiver.source.pieces.parts {
+		pieces.append(part)
+	}
+}
+
+// reopen returns a strfs.Content with the same content, but with its own read offset (and NOT closed).
+func (receiver *Content) reopen() Content {
+	if nil == receiver.source {
+		return CreateContent(receiver.value)
+	}
+
+	return createSourceContent(receiver.source)
+}
+
+// String returns all the pieces joined into a single string.
+//
+// They are only joined the first time String is called — after that, String just returns what it joined the first time.
+func (receiver *internalContentSource) String() string {
+	receiver.once.Do(func() {
+		receiver.flattened = receiver.pieces.String()
+	})
+
+	return receiver.flattened
+}
+
+// sys returns what the Sys method of the fs.FileInfo of a file with this content returns.
+//
+// That is the string that the strfs.Content is wrapping.
+// Or nil, if the strfs.Content is a view of something that is NOT a single string (so that a Stat does NOT join it into a single string).
+func (receiver *Content) sys() any {
+	if nil != receiver.source {
+		return nil
+	}
+
+	return receiver.value
+}
@@ -0,0 +1,215 @@
+package strfs
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// ChangeKind says how a file differs between two file-systems.
+//
+// A file can differ in more than one way, so a ChangeKind can have more than one bit set. For example:
+//
+//	strfs.ChangeModified | strfs.ChangeModeChanged
+type ChangeKind uint
+
+const (
+	// ChangeAdded means the file is only in the second file-system.
+	ChangeAdded ChangeKind = 1 << iota
+
+	// ChangeRemoved means the file is only in the first file-system.
+	ChangeRemoved
+
+	// ChangeModified means the content of the file is different.
+	ChangeModified
+
+	// ChangeModeChanged means the permission-bits of the file are different.
+	ChangeModeChanged
+
+	// ChangeModTimeChanged means the modification-time of the file is different.
+	ChangeModTimeChanged
+)
+
+// String returns the names of the bits that are set, such as "MODIFIED|MODE".
+//
+// String makes strfs.ChangeKind fit the fmt.Stringer interface.
+func (receiver ChangeKind) String() string {
+	var names []string
+
+	if 0 != receiver & ChangeAdded {
+		names = append(names, "ADDED")
+	}
+	if 0 != receiver & ChangeRemoved {
+		names = append(names, "REMOVED")
+	}
+	if 0 != receiver & ChangeModified {
+		names = append(names, "MODIFIED")
+	}
+	if 0 != receiver & ChangeModeChanged {
+		names = append(names, "MODE")
+	}
+	if 0 != receiver & ChangeModTimeChanged {
+		names = append(names, "MTIME")
+	}
+
+	return strings.Join(names, "|")
+}
+
+// Change is how a single file differs between two file-systems.
+//
+// Old is the file in the first file-system (and is the zero value for ChangeAdded).
+// New is the file in the second file-system (and is the zero value for ChangeRemoved).
+type Change struct {
+	Name string
+	Kind ChangeKind
+	Old RegularFile
+	New RegularFile
+}
+
+// UnifiedDiff returns a unified diff (like the one from "diff -u", or "git diff") of the content of the file.
+//
+// If the content did NOT change, then UnifiedDiff returns an empty string.
+// If the content is NOT text, then just a "Binary files ... differ" line is returned.
+func (receiver Change) UnifiedDiff() string {
+	if 0 == receiver.Kind & (ChangeAdded | ChangeRemoved | ChangeModified) {
+		return ""
+	}
+
+	var oldname string = "a/" + receiver.Name
+	var newname string = "b/" + receiver.Name
+	switch {
+	case 0 != receiver.Kind & ChangeAdded:
+		oldname = "/dev/null"
+	case 0 != receiver.Kind & ChangeRemoved:
+		newname = "/dev/null"
+	}
+
+	return unifiedDiff(oldname, newname, receiver.Old.FileContent.String(), receiver.New.FileContent.String())
+}
+
+// Diff is all the ways two file-systems differ, sorted by the name of the file.
+//
+// A Diff is returned by DiffFS, and can be applied to a strfs.Tree (see the Apply method of strfs.Tree).
+type Diff []Change
+
+// DiffFS returns how the (regular) files in 'to' differ from the (regular) files in 'from'.
+//
+// Files that are the same (in content, permission-bits, and modification-time) are NOT included.
+//
+// Example usage:
+//
+//	diff, err := strfs.DiffFS(expected, actual)
+//
+//	// ...
+//
+//	for _, change := range diff {
+//		fmt.Println(change.Kind, change.Name)
+//		fmt.Print(change.UnifiedDiff())
+//	}
+func DiffFS(from fs.FS, to fs.FS) (Diff, error) {
+	oldfiles, err := diffFiles(from)
+	if nil != err {
+		return nil, err
+	}
+
+	newfiles, err := diffFiles(to)
+	if nil != err {
+		return nil, err
+	}
+
+	var names []string
+	for name := range oldfiles {
+		names = append(names, name)
+	}
+	for name := range newfiles {
+		if _, found := oldfiles[name]; !found {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diff Diff
+	for _, name := range names {
+		oldfile, inOld := oldfiles[name]
+		newfile, inNew := newfiles[name]
+
+		var kind ChangeKind
+		switch {
+		case !inOld:
+			kind = ChangeAdded
+		case !inNew:
+			kind = ChangeRemoved
+		default:
+			if oldfile.FileContent.String() != newfile.FileContent.String() {
+				kind |= ChangeModified
+			}
+			if oldfile.FileMode.Perm() != newfile.FileMode.Perm() {
+				kind |= ChangeModeChanged
+			}
+			if !oldfile.FileModTime.Equal(newfile.FileModTime) {
+				kind |= ChangeModTimeChanged
+			}
+		}
+		if 0 == kind {
+			continue
+		}
+
+		diff = append(diff, Change{
+			Name:name,
+			Kind:kind,
+			Old:oldfile,
+			New:newfile,
+		})
+	}
+
+	return diff, nil
+}
+
+// diffFiles returns all the (regular) files in 'fsys'.
+func diffFiles(fsys fs.FS) (map[string]RegularFile, error) {
+	var files map[string]RegularFile = map[string]RegularFile{}
+
+	err := exportWalk(fsys, func(name string, fileinfo fs.FileInfo, data []byte) error {
+		files[name] = RegularFile{
+			FileContent: CreateContent(string(data)),
+			FileName:    fileinfo.Name(),
+			FileModTime: fileinfo.ModTime(),
+			FileMode:    fileinfo.Mode().Perm(),
+		}
+		return nil
+	})
+	if nil != err {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// String returns a line for each change, such as:
+//
+//	MODIFIED|MODE css/style.css
+//
+// String makes strfs.Diff fit the fmt.Stringer interface.
+func (receiver Diff) String() string {
+	var builder strings.Builder
+
+	for _, change := range receiver {
+		builder.WriteString(change.Kind.String())
+		builder.WriteString(" ")
+		builder.WriteString(change.Name)
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// UnifiedDiff returns the unified diffs (see the UnifiedDiff method of strfs.Change) of all the changes, one after the other.
+func (receiver Diff) UnifiedDiff() string {
+	var builder strings.Builder
+
+	for _, change := range receiver {
+		builder.WriteString(change.UnifiedDiff())
+	}
+
+	return builder.String()
+}
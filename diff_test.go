@@ -0,0 +1,257 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"errors"
+	"io/fs"
+	"strings"
+	"time"
+
+	"testing"
+)
+
+func TestDiffFS(t *testing.T) {
+
+	var mtime1 time.Time = time.Date(2022, 12, 12, 10, 30, 14, 0, time.UTC)
+	var mtime2 time.Time = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var from strfs.FS = strfs.FS{
+		"same.txt":      strfs.RegularFile{FileContent: strfs.CreateContent("same"), FileModTime: mtime1, FileMode: 0644},
+		"removed.txt":   strfs.RegularFile{FileContent: strfs.CreateContent("gone"), FileModTime: mtime1, FileMode: 0644},
+		"modified.txt":  strfs.RegularFile{FileContent: strfs.CreateContent("before"), FileModTime: mtime1, FileMode: 0644},
+		"mode.txt":      strfs.RegularFile{FileContent: strfs.CreateContent("mode"), FileModTime: mtime1, FileMode: 0644},
+		"dir/mtime.txt": strfs.RegularFile{FileContent: strfs.CreateContent("mtime"), FileModTime: mtime1, FileMode: 0644},
+	}
+
+	var to strfs.FS = strfs.FS{
+		"same.txt":      strfs.RegularFile{FileContent: strfs.CreateContent("same"), FileModTime: mtime1, FileMode: 0644},
+		"added.txt":     strfs.RegularFile{FileContent: strfs.CreateContent("new"), FileModTime: mtime2, FileMode: 0600},
+		"modified.txt":  strfs.RegularFile{FileContent: strfs.CreateContent("after"), FileModTime: mtime1, FileMode: 0755},
+		"mode.txt":      strfs.RegularFile{FileContent: strfs.CreateContent("mode"), FileModTime: mtime1, FileMode: 0600},
+		"dir/mtime.txt": strfs.RegularFile{FileContent: strfs.CreateContent("mtime"), FileModTime: mtime2, FileMode: 0644},
+	}
+
+	diff, err := strfs.DiffFS(from, to)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	{
+		var expected string =
+			"ADDED added.txt"+"\n"+
+			"MTIME dir/mtime.txt"+"\n"+
+			"MODE mode.txt"+"\n"+
+			"MODIFIED|MODE modified.txt"+"\n"+
+			"REMOVED removed.txt"+"\n"
+
+		if actual := diff.String(); expected != actual {
+			t.Errorf("The actual diff was not what was expected.")
+			t.Logf("EXPECTED:\n%s", expected)
+			t.Logf("ACTUAL:\n%s", actual)
+		}
+	}
+
+	{
+		var expected string =
+			"--- /dev/null"+"\n"+
+			"+++ b/added.txt"+"\n"+
+			"@@ -0,0 +1 @@"+"\n"+
+			"+new"+"\n"+
+			`\ No newline at end of file`+"\n"+
+			"--- a/modified.txt"+"\n"+
+			"+++ b/modified.txt"+"\n"+
+			"@@ -1 +1 @@"+"\n"+
+			"-before"+"\n"+
+			`\ No newline at end of file`+"\n"+
+			"+after"+"\n"+
+			`\ No newline at end of file`+"\n"+
+			"--- a/removed.txt"+"\n"+
+			"+++ /dev/null"+"\n"+
+			"@@ -1 +0,0 @@"+"\n"+
+			"-gone"+"\n"+
+			`\ No newline at end of file`+"\n"
+
+		if actual := diff.UnifiedDiff(); expected != actual {
+			t.Errorf("The actual unified-diff was not what was expected.")
+			t.Logf("EXPECTED:\n%s", expected)
+			t.Logf("ACTUAL:\n%s", actual)
+		}
+	}
+
+	{
+		tree, err := strfs.CreateTree(from)
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		if err := tree.Apply(diff); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		again, err := strfs.DiffFS(tree, to)
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		if 0 != len(again) {
+			t.Errorf("Expected the tree to be the same as the file-system the diff was to, after the diff was applied.")
+			t.Logf("DIFF:\n%s", again)
+		}
+	}
+}
+
+func TestChange_UnifiedDiff(t *testing.T) {
+
+	tests := []struct{
+		Old      string
+		New      string
+		Expected string
+	}{
+		{
+			Old:      "once\ntwice\n",
+			New:      "once\ntwice\n",
+			Expected: "",
+		},
+		{
+			Old: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n17\n18\n19\n20\n",
+			New: "1\n2\nTHREE\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n17\n19\n20\n21\n",
+			Expected:
+				"--- a/file.txt"+"\n"+
+				"+++ b/file.txt"+"\n"+
+				"@@ -1,6 +1,6 @@"+"\n"+
+				" 1"+"\n"+
+				" 2"+"\n"+
+				"-3"+"\n"+
+				"+THREE"+"\n"+
+				" 4"+"\n"+
+				" 5"+"\n"+
+				" 6"+"\n"+
+				"@@ -15,6 +15,6 @@"+"\n"+
+				" 15"+"\n"+
+				" 16"+"\n"+
+				" 17"+"\n"+
+				"-18"+"\n"+
+				" 19"+"\n"+
+				" 20"+"\n"+
+				"+21"+"\n",
+		},
+		{
+			Old: "a\nb\nc\nd\n",
+			New: "a\nB\nc\nD\n",
+			Expected:
+				"--- a/file.txt"+"\n"+
+				"+++ b/file.txt"+"\n"+
+				"@@ -1,4 +1,4 @@"+"\n"+
+				" a"+"\n"+
+				"-b"+"\n"+
+				"+B"+"\n"+
+				" c"+"\n"+
+				"-d"+"\n"+
+				"+D"+"\n",
+		},
+		{
+			Old:      "\x89PNG\r\n\x1A\n",
+			New:      "\x89PNG\r\n\x1A\n\x00",
+			Expected: "Binary files a/file.txt and b/file.txt differ\n",
+		},
+	}
+
+	for testNumber, test := range tests {
+		var change strfs.Change = strfs.Change{
+			Name: "file.txt",
+			Kind: strfs.ChangeModified,
+			Old:  strfs.RegularFile{FileContent: strfs.CreateContent(test.Old)},
+			New:  strfs.RegularFile{FileContent: strfs.CreateContent(test.New)},
+		}
+
+		if expected, actual := test.Expected, change.UnifiedDiff(); expected != actual {
+			t.Errorf("For test #%d, the actual unified-diff was not what was expected.", testNumber)
+			t.Logf("EXPECTED:\n%s", expected)
+			t.Logf("ACTUAL:\n%s", actual)
+			continue
+		}
+	}
+}
+
+func TestChange_UnifiedDiff_large(t *testing.T) {
+
+	var oldlines []string
+	var newlines []string
+	for i := 0; i < 5000; i++ {
+		oldlines = append(oldlines, "old\n")
+		newlines = append(newlines, "new\n")
+	}
+
+	var change strfs.Change = strfs.Change{
+		Name: "file.txt",
+		Kind: strfs.ChangeModified,
+		Old:  strfs.RegularFile{FileContent: strfs.CreateContent(strings.Join(oldlines, ""))},
+		New:  strfs.RegularFile{FileContent: strfs.CreateContent(strings.Join(newlines, ""))},
+	}
+
+	var actual string = change.UnifiedDiff()
+
+	if expected, actual := 5000, strings.Count(actual, "-old\n"); expected != actual {
+		t.Errorf("The actual number of removed lines was not what was expected: expected %d, actually %d", expected, actual)
+	}
+	if expected, actual := 5000, strings.Count(actual, "+new\n"); expected != actual {
+		t.Errorf("The actual number of added lines was not what was expected: expected %d, actually %d", expected, actual)
+	}
+}
+
+func TestTree_Apply_error(t *testing.T) {
+
+	tree, err := strfs.CreateTree(strfs.FS{
+		"file.txt": strfs.RegularFile{FileContent: strfs.CreateContent("once")},
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	tests := []struct{
+		Diff     strfs.Diff
+		Expected error
+	}{
+		{
+			Diff: strfs.Diff{
+				{Name:"file.txt", Kind:strfs.ChangeAdded, New:strfs.RegularFile{FileContent:strfs.CreateContent("twice")}},
+			},
+			Expected: fs.ErrExist,
+		},
+		{
+			Diff: strfs.Diff{
+				{Name:"other.txt", Kind:strfs.ChangeAdded, New:strfs.RegularFile{FileContent:strfs.CreateContent("twice")}},
+				{Name:"missing.txt", Kind:strfs.ChangeRemoved},
+			},
+			Expected: fs.ErrNotExist,
+		},
+		{
+			Diff: strfs.Diff{
+				{Name:"missing.txt", Kind:strfs.ChangeModified, New:strfs.RegularFile{FileContent:strfs.CreateContent("twice")}},
+			},
+			Expected: fs.ErrNotExist,
+		},
+		{
+			Diff: strfs.Diff{
+				{Name:"../file.txt", Kind:strfs.ChangeRemoved},
+			},
+			Expected: fs.ErrInvalid,
+		},
+	}
+
+	for testNumber, test := range tests {
+		err := tree.Apply(test.Diff)
+		if !errors.Is(err, test.Expected) {
+			t.Errorf("For test #%d, the actual error was not what was expected.", testNumber)
+			t.Logf("EXPECTED ERROR: %s", test.Expected)
+			t.Logf("ACTUAL   ERROR: (%T) %s", err, err)
+			continue
+		}
+	}
+
+	if _, err := fs.Stat(tree, "other.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected a diff that failed to apply to NOT change the tree.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+}
@@ -0,0 +1,96 @@
+package strfs
+
+import (
+	"io"
+	"io/fs"
+	"path"
+)
+
+const modeDirectory = fs.ModeDir | 0555
+
+type internalDirectory struct {
+	name string
+	entries []fs.DirEntry
+	offset int
+	closed bool
+}
+
+var (
+	// A trick to make sure internalDirectory fits the fs.ReadDirFile interface.
+	// This is a compile-time check.
+	_ fs.ReadDirFile = &internalDirectory{}
+)
+
+func (receiver *internalDirectory) Close() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.closed = true
+	return nil
+}
+
+func (receiver *internalDirectory) Read([]byte) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	return 0, &fs.PathError{Op:"read", Path:receiver.name, Err:errIsDirectory}
+}
+
+func (receiver *internalDirectory) ReadDir(n int) ([]fs.DirEntry, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+	if receiver.closed {
+		return nil, &fs.PathError{Op:"readdir", Path:receiver.name, Err:fs.ErrClosed}
+	}
+
+	var remaining []fs.DirEntry = receiver.entries[receiver.offset:]
+
+	if 0 < n && 0 == len(remaining) {
+		return nil, io.EOF
+	}
+	if 0 < n && n < len(remaining) {
+		remaining = remaining[:n]
+	}
+
+	receiver.offset += len(remaining)
+	return append([]fs.DirEntry(nil), remaining...), nil
+}
+
+func (receiver *internalDirectory) Stat() (fs.FileInfo, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+
+	return internalFileInfo{
+		name: path.Base(receiver.name),
+		mode: modeDirectory,
+	}, nil
+}
+
+type internalDirEntry struct {
+	name string
+}
+
+var _ fs.DirEntry = internalDirEntry{}
+
+func (receiver internalDirEntry) Info() (fs.FileInfo, error) {
+	return internalFileInfo{
+		name: receiver.name,
+		mode: modeDirectory,
+	}, nil
+}
+
+func (internalDirEntry) IsDir() bool {
+	return true
+}
+
+func (receiver internalDirEntry) Name() string {
+	return receiver.name
+}
+
+func (internalDirEntry) Type() fs.FileMode {
+	return fs.ModeDir
+}
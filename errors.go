@@ -5,10 +5,30 @@ import (
 )
 
 const (
-	errClosed        = erorr.Error("closed")
-	errEmptyContent  = erorr.Error("empty content")
-	errInternalError = erorr.Error("internal error")
-	errNilByteSlice  = erorr.Error("nil byte slice")
-	errNilReadSeeker = erorr.Error("nil read-seeker")
-	errNilReceiver   = erorr.Error("nil receiver")
+	errBundleBadMagic      = erorr.Error("not a strfs bundle")
+	errBundleTooShort      = erorr.Error("bundle too short")
+	errClosed              = erorr.Error("closed")
+	errColumnOutOfBounds   = erorr.Error("column out of bounds")
+	errEmptyContent        = erorr.Error("empty content")
+	errEmptyPattern        = erorr.Error("empty pattern")
+	errGobTooShort         = erorr.Error("gob data too short")
+	errInternalError       = erorr.Error("internal error")
+	errInvalidRange        = erorr.Error("invalid range")
+	errInvalidWhence       = erorr.Error("invalid whence")
+	errIsDirectory         = erorr.Error("is a directory")
+	errLineOutOfBounds     = erorr.Error("line out of bounds")
+	errMaxMatches          = erorr.Error("maximum number of matches reached")
+	errNegativeLength      = erorr.Error("negative length")
+	errNegativeOffset      = erorr.Error("negative offset")
+	errNilEncoding         = erorr.Error("nil encoding")
+	errNilFS               = erorr.Error("nil file-system")
+	errNilReadSeeker       = erorr.Error("nil read-seeker")
+	errNilReader           = erorr.Error("nil reader")
+	errNilReceiver         = erorr.Error("nil receiver")
+	errNilWriter           = erorr.Error("nil writer")
+	errNotDirectory        = erorr.Error("not a directory")
+	errOffsetOutOfBounds   = erorr.Error("offset out of bounds")
+	errRangeNotSatisfiable = erorr.Error("range not satisfiable")
+	errRangesTooLong       = erorr.Error("ranges are longer than the whole content")
+	errTooManyRanges       = erorr.Error("too many ranges")
 )
@@ -0,0 +1,99 @@
+package strfs
+
+import (
+	"strconv"
+	"strings"
+)
+
+// EventOp says what kind of change a strfs.Event is about.
+//
+// Because events can be coalesced (see Watch), an EventOp can have more than one bit set. For example:
+//
+//	strfs.EventCreate | strfs.EventWrite
+type EventOp uint
+
+const (
+	// EventCreate means a file was created.
+	EventCreate EventOp = 1 << iota
+
+	// EventWrite means a file was written to.
+	EventWrite
+
+	// EventRemove means a file was removed.
+	EventRemove
+
+	// EventRename means a file was renamed (i.e., moved) — from the strfs.Event's OldName to its Name.
+	EventRename
+
+	// EventChmod means the permission-bits of a file were changed.
+	EventChmod
+)
+
+// String returns the names of the bits that are set, such as "CREATE|WRITE".
+//
+// String makes strfs.EventOp fit the fmt.Stringer interface.
+func (receiver EventOp) String() string {
+	var names []string
+
+	if 0 != receiver & EventCreate {
+		names = append(names, "CREATE")
+	}
+	if 0 != receiver & EventWrite {
+		names = append(names, "WRITE")
+	}
+	if 0 != receiver & EventRemove {
+		names = append(names, "REMOVE")
+	}
+	if 0 != receiver & EventRename {
+		names = append(names, "RENAME")
+	}
+	if 0 != receiver & EventChmod {
+		names = append(names, "CHMOD")
+	}
+
+	return strings.Join(names, "|")
+}
+
+// Event is a change to a file in a strfs.Tree.
+//
+// Name is the path of the file that was changed.
+// OldName is only set for EventRename, and is the path the file was renamed from.
+type Event struct {
+	Name string
+	OldName string
+	Op EventOp
+}
+
+// String returns a human-readable version of the event, such as:
+//
+//	RENAME "index.html" (from "index.htm")
+//
+// String makes strfs.Event fit the fmt.Stringer interface.
+func (receiver Event) String() string {
+	var builder strings.Builder
+
+	builder.WriteString(receiver.Op.String())
+	builder.WriteString(" ")
+	builder.WriteString(strconv.Quote(receiver.Name))
+	if "" != receiver.OldName {
+		builder.WriteString(" (from ")
+		builder.WriteString(strconv.Quote(receiver.OldName))
+		builder.WriteString(")")
+	}
+
+	return builder.String()
+}
+
+// matchesPrefix returns whether the path 'name' is 'prefix', or is in the directory 'prefix'.
+//
+// An empty prefix (or ".") matches everything.
+func matchesPrefix(prefix string, name string) bool {
+	switch {
+	case "" == prefix || "." == prefix:
+		return true
+	case name == prefix:
+		return true
+	default:
+		return strings.HasPrefix(name, prefix) && '/' == name[len(prefix)]
+	}
+}
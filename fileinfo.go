@@ -6,7 +6,7 @@ import (
 )
 
 type internalFileInfo struct {
-	sys string
+	sys any
 	mode fs.FileMode
 	modtime time.Time
 	name string
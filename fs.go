@@ -0,0 +1,135 @@
+package strfs
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// FS is a read-only file-system (i.e., a [fs.FS]) made up of strfs.RegularFile.
+//
+// The keys of the map are the (slash-separated) paths of the files, such as "css/style.css".
+// Directories are NOT listed in the map — they are implied by the paths of the files.
+//
+// Each key must be a valid path (see fs.ValidPath) — and, because a path cannot be both a file and a directory,
+// no key can be a parent directory of another key (such as both "a" and "a/b").
+// Use Validate to check this.
+//
+// Each time a file is opened, a new strfs.RegularFile (with its own read offset) is returned,
+// so the same file can be opened (and read) more than once at the same time.
+// The FileName of the opened strfs.RegularFile is always the last element of its path.
+//
+// Example usage:
+//
+//	var fsys strfs.FS = strfs.FS{
+//		"index.html": strfs.RegularFile{
+//			FileContent: strfs.CreateContent("<!DOCTYPE html>"+"\n"+"<html><body>Hello world!</body></html>"),
+//			FileModTime: time.Date(2022, 12, 12, 10, 30, 14, 2, time.UTC),
+//			FileMode:    0644,
+//		},
+//		"css/style.css": strfs.RegularFile{
+//			FileContent: strfs.CreateContent("body{color:red}"),
+//			FileModTime: time.Date(2022, 12, 12, 10, 30, 14, 2, time.UTC),
+//			FileMode:    0644,
+//		},
+//	}
+type FS map[string]RegularFile
+
+// A trick to make sure strfs.FS fits the fs.FS interface.
+// This is a compile-time check.
+var _ fs.FS = FS{}
+
+// Open opens the file (or directory) at 'name'.
+//
+// Open makes strfs.FS fit the fs.FS interface.
+func (receiver FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrInvalid}
+	}
+
+	if regularfile, found := receiver[name]; found {
+		return openRegularFile(name, regularfile), nil
+	}
+
+	entries, found := receiver.readDir(name)
+	if !found {
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrNotExist}
+	}
+
+	return &internalDirectory{
+		name:name,
+		entries:entries,
+	}, nil
+}
+
+// Validate returns an error if any of the keys of the strfs.FS is NOT a valid path (see fs.ValidPath),
+// or if any of the keys is a parent directory of another key (such as both "a" and "a/b").
+//
+// A strfs.FS that Validate returns an error for does NOT fit the fs.FS interface properly.
+func (receiver FS) Validate() error {
+	var names []string
+	for name := range receiver {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if "." == name || !fs.ValidPath(name) {
+			return &fs.PathError{Op:"validate", Path:name, Err:fs.ErrInvalid}
+		}
+
+		for index := strings.LastIndexByte(name, '/'); 0 <= index; index = strings.LastIndexByte(name[:index], '/') {
+			if _, found := receiver[name[:index]]; found {
+				return &fs.PathError{Op:"validate", Path:name, Err:errNotDirectory}
+			}
+		}
+	}
+
+	return nil
+}
+
+// readDir returns the entries of the directory at 'name', sorted by name.
+// It also returns whether the directory exists.
+func (receiver FS) readDir(name string) ([]fs.DirEntry, bool) {
+	var prefix string
+	if "." != name {
+		prefix = name + "/"
+	}
+
+	var entries []fs.DirEntry
+	var found bool = "." == name
+	var seen map[string]struct{} = map[string]struct{}{}
+
+	for filepath, regularfile := range receiver {
+		if !strings.HasPrefix(filepath, prefix) {
+			continue
+		}
+		found = true
+
+		var rest string = filepath[len(prefix):]
+		if index := strings.IndexByte(rest, '/'); 0 <= index {
+			var dirname string = rest[:index]
+			if _, duplicate := seen[dirname]; !duplicate {
+				seen[dirname] = struct{}{}
+				entries = append(entries, internalDirEntry{name:dirname})
+			}
+			continue
+		}
+
+		entries = append(entries, openRegularFile(filepath, regularfile))
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return entries, found
+}
+
+func openRegularFile(name string, regularfile RegularFile) *RegularFile {
+	regularfile.FileContent = regularfile.FileContent.reopen()
+	regularfile.FileName = path.Base(name)
+
+	return &regularfile
+}
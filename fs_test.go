@@ -0,0 +1,222 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfstest"
+
+	"io/fs"
+	"time"
+
+	"testing"
+)
+
+func TestFS(t *testing.T) {
+
+	var fsys strfs.FS = strfs.FS{
+		"empty.txt": strfs.RegularFile{
+			FileContent: strfs.CreateContent(""),
+			FileModTime: time.Now(),
+		},
+		"file1.txt": strfs.RegularFile{
+			FileContent: strfs.CreateContent("once"),
+			FileModTime: time.Date(2022, 12, 12, 10, 30, 14, 2, time.UTC),
+			FileMode:    0644,
+		},
+		"html/file2.html": strfs.RegularFile{
+			FileContent: strfs.CreateContent("once twice"),
+			FileName:    "ignored.html",
+			FileModTime: time.Date(1984, 01, 14, 9, 10, 11, 12, time.Local),
+			FileMode:    0600,
+		},
+		"gmni/file3.gmni": strfs.RegularFile{
+			FileContent: strfs.CreateContent("once twice thrice"),
+			FileModTime: time.Date(1974, 12, 18, 4, 5, 6, 7, time.Local),
+		},
+		"gmni/fngr/file4.fngr": strfs.RegularFile{
+			FileContent: strfs.CreateContent("once twice thrice fource"),
+		},
+	}
+
+	err := strfstest.TestFS(fsys, "empty.txt", "file1.txt", "html/file2.html", "gmni/file3.gmni", "gmni/fngr/file4.fngr")
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	{
+		fileinfo, err := fs.Stat(fsys, "html/file2.html")
+		if nil != err {
+			t.Errorf("Did not expect an error but actually got one.")
+			t.Logf("ERROR: (%T) %s", err, err)
+			return
+		}
+
+		if expected, actual := "file2.html", fileinfo.Name(); expected != actual {
+			t.Errorf("The actual file-name is not what was expected.")
+			t.Logf("EXPECTED FILE-NAME: %q", expected)
+			t.Logf("ACTUAL   FILE-NAME: %q", actual)
+			return
+		}
+		if expected, actual := fs.FileMode(0600), fileinfo.Mode(); expected != actual {
+			t.Errorf("The actual file-mode is not what was expected.")
+			t.Logf("EXPECTED FILE-MODE: %v", expected)
+			t.Logf("ACTUAL   FILE-MODE: %v", actual)
+			return
+		}
+	}
+
+	{
+		_, err := fsys.Open("does/not/exist.txt")
+		if nil == err {
+			t.Errorf("Expected an error but did not actually get one.")
+			return
+		}
+	}
+}
+
+func TestFS_Validate(t *testing.T) {
+
+	tests := []struct{
+		FS    strfs.FS
+		Valid bool
+	}{
+		{
+			FS:    strfs.FS{},
+			Valid: true,
+		},
+		{
+			FS: strfs.FS{
+				"a/b.txt":   strfs.RegularFile{},
+				"a/c/d.txt": strfs.RegularFile{},
+				"ab":        strfs.RegularFile{},
+			},
+			Valid: true,
+		},
+		{
+			FS: strfs.FS{
+				"a":   strfs.RegularFile{},
+				"a/b": strfs.RegularFile{},
+			},
+		},
+		{
+			FS: strfs.FS{
+				"a":       strfs.RegularFile{},
+				"a/b/c/d": strfs.RegularFile{},
+			},
+		},
+		{
+			FS: strfs.FS{"/a": strfs.RegularFile{}},
+		},
+		{
+			FS: strfs.FS{"a/../b": strfs.RegularFile{}},
+		},
+		{
+			FS: strfs.FS{"a/": strfs.RegularFile{}},
+		},
+		{
+			FS: strfs.FS{".": strfs.RegularFile{}},
+		},
+		{
+			FS: strfs.FS{"": strfs.RegularFile{}},
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		err := test.FS.Validate()
+		if test.Valid && nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+			t.Logf("ERROR: (%T) %s", err, err)
+			continue
+		}
+		if !test.Valid && nil == err {
+			t.Errorf("For test #%d, expected an error but did not actually get one.", testNumber)
+			continue
+		}
+
+		if _, err := strfs.CreateTree(test.FS); test.Valid != (nil == err) {
+			t.Errorf("For test #%d, expected strfs.CreateTree to agree with Validate, but it did not.", testNumber)
+			t.Logf("ERROR: %v", err)
+			continue
+		}
+	}
+}
+
+// sameFS reports (with t.Errorf) any differences between 'expected' and 'actual'.
+// If 'metadata' is true, then modification-times and file-modes are compared too.
+func sameFS(t *testing.T, expected strfs.FS, actual strfs.FS, metadata bool) bool {
+	t.Helper()
+
+	if len(expected) != len(actual) {
+		t.Errorf("The actual number of files is not what was expected.")
+		t.Logf("EXPECTED NUMBER-OF-FILES: %d", len(expected))
+		t.Logf("ACTUAL   NUMBER-OF-FILES: %d", len(actual))
+		return false
+	}
+
+	for name, expectedFile := range expected {
+		actualFile, found := actual[name]
+		if !found {
+			t.Errorf("Expected file %q but it was not actually there.", name)
+			return false
+		}
+
+		if expected, actual := expectedFile.FileContent.String(), actualFile.FileContent.String(); expected != actual {
+			t.Errorf("The actual content of file %q is not what was expected.", name)
+			t.Logf("EXPECTED FILE-CONTENT: %q", expected)
+			t.Logf("ACTUAL   FILE-CONTENT: %q", actual)
+			return false
+		}
+
+		if !metadata {
+			continue
+		}
+
+		if expected, actual := expectedFile.FileModTime, actualFile.FileModTime; !expected.Equal(actual) {
+			t.Errorf("The actual mod-time of file %q is not what was expected.", name)
+			t.Logf("EXPECTED FILE-MOD-TIME: %v", expected)
+			t.Logf("ACTUAL   FILE-MOD-TIME: %v", actual)
+			return false
+		}
+		if expected, actual := expectedFile.FileMode, actualFile.FileMode; expected != actual {
+			t.Errorf("The actual file-mode of file %q is not what was expected.", name)
+			t.Logf("EXPECTED FILE-MODE: %v", expected)
+			t.Logf("ACTUAL   FILE-MODE: %v", actual)
+			return false
+		}
+	}
+
+	return true
+}
+
+// exampleFS returns a strfs.FS that is used by a number of tests.
+func exampleFS() strfs.FS {
+	return strfs.FS{
+		"empty.txt": strfs.RegularFile{
+			FileContent: strfs.CreateContent(""),
+			FileModTime: time.Date(2022, 12, 12, 10, 30, 14, 0, time.UTC),
+			FileMode:    0644,
+		},
+		"file1.txt": strfs.RegularFile{
+			FileContent: strfs.CreateContent("once"+"\n"),
+			FileModTime: time.Date(2022, 12, 12, 10, 30, 14, 0, time.UTC),
+			FileMode:    0644,
+		},
+		"html/file2.html": strfs.RegularFile{
+			FileContent: strfs.CreateContent("<p>once twice</p>"+"\n"),
+			FileModTime: time.Date(1984, 01, 14, 9, 10, 11, 0, time.UTC),
+			FileMode:    0600,
+		},
+		"gmni/file3.gmni": strfs.RegularFile{
+			FileContent: strfs.CreateContent("# once twice thrice"+"\n"),
+			FileModTime: time.Date(1974, 12, 18, 4, 5, 6, 0, time.UTC),
+			FileMode:    0444,
+		},
+		"gmni/fngr/file4.fngr": strfs.RegularFile{
+			FileContent: strfs.CreateContent("Hello world! 😈"+"\n"),
+			FileModTime: time.Date(2024, 8, 1, 23, 34, 37, 0, time.UTC),
+			FileMode:    0755,
+		},
+	}
+}
@@ -2,4 +2,18 @@ module codeberg.org/reiver/go-strfs
 
 go 1.18
 
-require github.com/reiver/go-erorr v0.0.0-20240801233437-8cbde6d1fa3f // indirect
+require github.com/reiver/go-erorr v0.0.0-20240801233437-8cbde6d1fa3f
+
+require golang.org/x/text v0.14.0
+
+require gopkg.in/yaml.v3 v3.0.1
+
+require golang.org/x/net v0.17.0
+
+require github.com/pkg/sftp v1.13.6
+
+require (
+	github.com/kr/fs v0.1.0 // indirect
+	golang.org/x/crypto v0.14.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+)
@@ -0,0 +1,48 @@
+package strfs
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// importPath turns a name from an archive (such as a tar file, or a zip file) into a path that can be used in a strfs.FS.
+//
+// For example, "./css/style.css" and "/css/style.css" both become "css/style.css".
+func importPath(name string) (string, error) {
+	var cleaned string = strings.TrimPrefix(path.Clean("/"+name), "/")
+
+	if "" == cleaned || !fs.ValidPath(cleaned) {
+		return "", &fs.PathError{Op:"import", Path:name, Err:fs.ErrInvalid}
+	}
+
+	return cleaned, nil
+}
+
+// exportWalk calls 'fn' for each regular file in 'fsys', along with its fs.FileInfo and its content.
+func exportWalk(fsys fs.FS, fn func(name string, fileinfo fs.FileInfo, data []byte) error) error {
+	if nil == fsys {
+		return errNilFS
+	}
+
+	return fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if nil != err {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		fileinfo, err := entry.Info()
+		if nil != err {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if nil != err {
+			return err
+		}
+
+		return fn(name, fileinfo, data)
+	})
+}
@@ -0,0 +1,255 @@
+package adapter
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"codeberg.org/reiver/go-strfs"
+)
+
+// Directories is a strfs.Tree, plus the (empty) directories that were made with Mkdir.
+//
+// A strfs.Tree only has (implied) directories that have files in them, but WebDAV and SFTP clients expect to be able to
+// make an empty directory and then put files into it.
+// So, adapter.Directories remembers the directories made with Mkdir itself.
+//
+// The names given to the methods must already be cleaned (see CleanName).
+//
+// An adapter.Directories is NOT safe to use from more than one goroutine at the same time — the caller must lock around it.
+type Directories struct {
+	tree *strfs.Tree
+	directories map[string]struct{}
+}
+
+// CreateDirectories returns an adapter.Directories over 'tree', that does not have any directories made with Mkdir yet.
+func CreateDirectories(tree *strfs.Tree) Directories {
+	return Directories{
+		tree:tree,
+		directories:map[string]struct{}{},
+	}
+}
+
+// IsDirectory returns whether there is a directory at 'name' — either implied by the files in the strfs.Tree,
+// or made with Mkdir.
+func (receiver *Directories) IsDirectory(name string) bool {
+	if _, found := receiver.directories[name]; found {
+		return true
+	}
+
+	fileinfo, err := fs.Stat(receiver.tree, name)
+	return nil == err && fileinfo.IsDir()
+}
+
+// Mkdir makes an (empty) directory at 'name'.
+func (receiver *Directories) Mkdir(name string) error {
+	if _, err := receiver.Stat(name); nil == err {
+		return &fs.PathError{Op:"mkdir", Path:name, Err:fs.ErrExist}
+	}
+	if !receiver.IsDirectory(path.Dir(name)) {
+		return &fs.PathError{Op:"mkdir", Path:name, Err:fs.ErrNotExist}
+	}
+
+	receiver.directories[name] = struct{}{}
+	return nil
+}
+
+// ReadDir returns the fs.FileInfo of what is in the directory 'name' (including the directories made with Mkdir), sorted by name.
+func (receiver *Directories) ReadDir(name string) ([]fs.FileInfo, error) {
+	if !receiver.IsDirectory(name) {
+		if _, err := fs.Stat(receiver.tree, name); nil != err {
+			return nil, err
+		}
+		return nil, &fs.PathError{Op:"readdir", Path:name, Err:errNotDirectory}
+	}
+
+	var fileinfos []fs.FileInfo
+	var seen map[string]struct{} = map[string]struct{}{}
+
+	// (A directory made with Mkdir, that has no files in it, is not in the strfs.Tree.)
+	if entries, err := fs.ReadDir(receiver.tree, name); nil == err {
+		for _, entry := range entries {
+			fileinfo, err := entry.Info()
+			if nil != err {
+				return nil, err
+			}
+			fileinfos = append(fileinfos, fileinfo)
+			seen[entry.Name()] = struct{}{}
+		}
+	}
+
+	for directory := range receiver.directories {
+		if "." == directory || name != path.Dir(directory) {
+			continue
+		}
+
+		var base string = path.Base(directory)
+		if _, found := seen[base]; found {
+			continue
+		}
+		fileinfos = append(fileinfos, internalDirectoryInfo{name:base})
+	}
+
+	sort.Slice(fileinfos, func(i, j int) bool {
+		return fileinfos[i].Name() < fileinfos[j].Name()
+	})
+
+	return fileinfos, nil
+}
+
+// RemoveAll removes the file, or the directory (and everything in it), at 'name'.
+func (receiver *Directories) RemoveAll(name string) error {
+	if "." == name {
+		return &fs.PathError{Op:"remove", Path:name, Err:fs.ErrInvalid}
+	}
+
+	var diff strfs.Diff
+	err := fs.WalkDir(receiver.tree.Snapshot(), name, func(filename string, entry fs.DirEntry, err error) error {
+		if nil != err {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		// (Only the names are needed — the content of the files is NOT read.)
+		diff = append(diff, strfs.Change{Name:filename, Kind:strfs.ChangeRemoved})
+		return nil
+	})
+	if nil != err && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := receiver.tree.Apply(diff); nil != err {
+		return err
+	}
+
+	receiver.forget(name)
+	return nil
+}
+
+// Rename moves the file, or the directory (and everything in it), at 'oldname' to 'newname'.
+func (receiver *Directories) Rename(oldname string, newname string) error {
+	switch {
+	case oldname == newname:
+		return nil
+	case "." == oldname || "." == newname:
+		return &fs.PathError{Op:"rename", Path:oldname, Err:fs.ErrInvalid}
+	case strings.HasPrefix(newname, oldname+"/"):
+		// A directory cannot be moved into itself.
+		return &fs.PathError{Op:"rename", Path:oldname, Err:fs.ErrInvalid}
+	}
+
+	if _, err := receiver.Stat(oldname); nil != err {
+		return err
+	}
+	if !receiver.IsDirectory(path.Dir(newname)) {
+		return &fs.PathError{Op:"rename", Path:newname, Err:fs.ErrNotExist}
+	}
+
+	// (A directory made with Mkdir, that has no files in it, is not in the strfs.Tree.)
+	if _, err := fs.Stat(receiver.tree, oldname); nil == err {
+		if err := receiver.tree.Rename(oldname, newname); nil != err {
+			return err
+		}
+	}
+
+	for directory := range receiver.forget(oldname) {
+		receiver.directories[newname + strings.TrimPrefix(directory, oldname)] = struct{}{}
+	}
+	return nil
+}
+
+// Rmdir removes the (empty) directory at 'name'.
+func (receiver *Directories) Rmdir(name string) error {
+	fileinfo, err := receiver.Stat(name)
+	if nil != err {
+		return err
+	}
+	if !fileinfo.IsDir() {
+		return &fs.PathError{Op:"rmdir", Path:name, Err:errNotDirectory}
+	}
+
+	if "." == name {
+		return &fs.PathError{Op:"rmdir", Path:name, Err:fs.ErrInvalid}
+	}
+	if _, err := fs.Stat(receiver.tree, name); nil == err {
+		return &fs.PathError{Op:"rmdir", Path:name, Err:errDirectoryNotEmpty}
+	}
+	for directory := range receiver.directories {
+		if strings.HasPrefix(directory, name+"/") {
+			return &fs.PathError{Op:"rmdir", Path:name, Err:errDirectoryNotEmpty}
+		}
+	}
+
+	delete(receiver.directories, name)
+	return nil
+}
+
+// Stat returns the fs.FileInfo of the file (or directory) at 'name'.
+func (receiver *Directories) Stat(name string) (fs.FileInfo, error) {
+	fileinfo, err := fs.Stat(receiver.tree, name)
+	if nil == err {
+		return fileinfo, nil
+	}
+
+	if _, found := receiver.directories[name]; found {
+		return internalDirectoryInfo{name:path.Base(name)}, nil
+	}
+
+	return nil, err
+}
+
+// forget forgets the directories made with Mkdir that are at 'name', or are in the directory 'name' — and returns them.
+func (receiver *Directories) forget(name string) map[string]struct{} {
+	var forgotten map[string]struct{} = map[string]struct{}{}
+
+	for directory := range receiver.directories {
+		if directory == name || strings.HasPrefix(directory, name+"/") {
+			delete(receiver.directories, directory)
+			forgotten[directory] = struct{}{}
+		}
+	}
+
+	return forgotten
+}
+
+// DirectoryInfo returns the fs.FileInfo of a directory (that does not have a modification-time) whose name is 'name'.
+//
+// It is the fs.FileInfo of the directories made with Mkdir.
+func DirectoryInfo(name string) fs.FileInfo {
+	return internalDirectoryInfo{name:name}
+}
+
+type internalDirectoryInfo struct {
+	name string
+}
+
+var _ fs.FileInfo = internalDirectoryInfo{}
+
+func (internalDirectoryInfo) IsDir() bool {
+	return true
+}
+
+func (internalDirectoryInfo) Mode() fs.FileMode {
+	return fs.ModeDir | 0755
+}
+
+func (internalDirectoryInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (receiver internalDirectoryInfo) Name() string {
+	return receiver.name
+}
+
+func (internalDirectoryInfo) Size() int64 {
+	return 0
+}
+
+func (internalDirectoryInfo) Sys() any {
+	return nil
+}
@@ -0,0 +1,16 @@
+package adapter
+
+import (
+	"github.com/reiver/go-erorr"
+)
+
+const (
+	ErrNilConn     = erorr.Error("nil connection")
+	ErrNilFS       = erorr.Error("nil file-system")
+	ErrNilListener = erorr.Error("nil listener")
+)
+
+const (
+	errDirectoryNotEmpty = erorr.Error("directory not empty")
+	errNotDirectory      = erorr.Error("not a directory")
+)
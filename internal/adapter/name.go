@@ -0,0 +1,21 @@
+// Package adapter has what the packages that serve a fs.FS (or a strfs.Tree) over a network protocol have in common —
+// such as strfswebdav, strfssftp, strfsgemini, and strfsfinger.
+package adapter
+
+import (
+	"path"
+)
+
+// CleanName turns a path from a client (such as "/css/style.css" — from a URL, or from a WebDAV, or SFTP, request)
+// into a path that can be used with a fs.FS (such as "css/style.css").
+//
+// The root ("/", or "") becomes ".".
+// A client cannot use ".." to get above the root.
+func CleanName(name string) string {
+	var cleaned string = path.Clean("/" + name)
+	if "/" == cleaned {
+		return "."
+	}
+
+	return cleaned[1:]
+}
@@ -0,0 +1,35 @@
+package adapter_test
+
+import (
+	"codeberg.org/reiver/go-strfs/internal/adapter"
+
+	"testing"
+)
+
+func TestCleanName(t *testing.T) {
+
+	tests := []struct{
+		Name     string
+		Expected string
+	}{
+		{Name: "",                   Expected: "."},
+		{Name: "/",                  Expected: "."},
+		{Name: "/css/style.css",     Expected: "css/style.css"},
+		{Name: "css/style.css",      Expected: "css/style.css"},
+		{Name: "/css/",              Expected: "css"},
+		{Name: "//css//./style.css", Expected: "css/style.css"},
+		{Name: "/../../etc/passwd",  Expected: "etc/passwd"},
+		{Name: "/css/../index.html", Expected: "index.html"},
+	}
+
+	for testNumber, test := range tests {
+
+		if expected, actual := test.Expected, adapter.CleanName(test.Name); expected != actual {
+			t.Errorf("For test #%d, the actual cleaned name was not what was expected.", testNumber)
+			t.Logf("NAME:     %q", test.Name)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}
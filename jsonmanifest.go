@@ -0,0 +1,105 @@
+package strfs
+
+import (
+	"encoding/json"
+	"io"
+	"io/fs"
+
+	"github.com/reiver/go-erorr"
+)
+
+// LoadJSONManifest reads a JSON manifest (see below) from 'reader', and returns the strfs.FS it describes.
+//
+// 'files' is used to load the content of any manifest entries that refer to another file (with "file").
+// 'files' can be nil if there are no such entries.
+//
+// The manifest is strictly validated. Unknown fields, and duplicate paths, are an error.
+// Errors say which path (in the manifest) the problem is with.
+//
+// A JSON manifest looks like:
+//
+//	{
+//		"index.html":    {"content": "<!DOCTYPE html>\n<html></html>\n", "mode": "0644", "mtime": "2022-12-12T10:30:14Z"},
+//		"img/logo.png":  {"base64": "iVBORw0KGgo="},
+//		"css/style.css": {"file": "style.css"},
+//		"style.css":     {"symlink": "css/style.css"}
+//	}
+func LoadJSONManifest(reader io.Reader, files fs.FS) (FS, error) {
+	if nil == reader {
+		return nil, errNilReader
+	}
+
+	var decoder *json.Decoder = json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+
+	manifest, err := decodeJSONManifest(decoder)
+	if nil != err {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, erorr.Errorf("manifest: unexpected data after the JSON manifest")
+	}
+
+	return buildManifest(manifest, files)
+}
+
+// decodeJSONManifest decodes a JSON manifest.
+//
+// It is decoded one token at a time (rather than all at once into a map) so that a path that is in the manifest more than once
+// is an error — rather than the last one silently replacing the others.
+func decodeJSONManifest(decoder *json.Decoder) (map[string]manifestEntry, error) {
+	token, err := decoder.Token()
+	if nil != err {
+		return nil, erorr.Errorf("manifest: %w", err)
+	}
+	if nil == token {
+		return nil, nil
+	}
+	if json.Delim('{') != token {
+		return nil, erorr.Errorf("manifest: must be a JSON object (mapping paths to manifest entries)")
+	}
+
+	var manifest map[string]manifestEntry = map[string]manifestEntry{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if nil != err {
+			return nil, erorr.Errorf("manifest: %w", err)
+		}
+		name, _ := token.(string)
+
+		if _, found := manifest[name]; found {
+			return nil, erorr.Errorf("manifest: %q: duplicate path", name)
+		}
+
+		var entry manifestEntry
+		if err := decoder.Decode(&entry); nil != err {
+			return nil, erorr.Errorf("manifest: %q: %w", name, err)
+		}
+
+		manifest[name] = entry
+	}
+
+	if _, err := decoder.Token(); nil != err {
+		return nil, erorr.Errorf("manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// SaveJSONManifest writes a JSON manifest (see LoadJSONManifest) describing each regular file in 'fsys' to 'writer'.
+//
+// Content that is valid UTF-8 is put inline (as "content"). Anything else is base64 encoded (as "base64").
+func SaveJSONManifest(writer io.Writer, fsys fs.FS) error {
+	if nil == writer {
+		return errNilWriter
+	}
+
+	manifest, err := createManifest(fsys)
+	if nil != err {
+		return err
+	}
+
+	var encoder *json.Encoder = json.NewEncoder(writer)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(manifest)
+}
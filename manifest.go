@@ -0,0 +1,332 @@
+package strfs
+
+import (
+	"encoding/base64"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/reiver/go-erorr"
+)
+
+// A manifest describes a tree of files declaratively. It maps each (slash-separated) path to a manifest entry.
+//
+// In JSON, a manifest looks like:
+//
+//	{
+//		"index.html":    {"content": "<!DOCTYPE html>\n<html></html>\n", "mode": "0644", "mtime": "2022-12-12T10:30:14Z"},
+//		"img/logo.png":  {"base64": "iVBORw0KGgo="},
+//		"css/style.css": {"file": "style.css"},
+//		"style.css":     {"symlink": "css/style.css"}
+//	}
+//
+// And in YAML, a manifest looks like:
+//
+//	index.html:
+//	  content: |
+//	    <!DOCTYPE html>
+//	    <html></html>
+//	  mode: "0644"
+//	  mtime: 2022-12-12T10:30:14Z
+//	img/logo.png:
+//	  base64: iVBORw0KGgo=
+//	css/style.css:
+//	  file: style.css
+//	style.css:
+//	  symlink: css/style.css
+//
+// Each manifest entry must have exactly one of:
+//
+//	• "content" — the content of the file, as an (inline) string,
+//	• "base64"  — the content of the file, base64 encoded,
+//	• "file"    — the path of another file (in the fs.FS given to the loader) to get the content from, or
+//	• "symlink" — the target of a symbolic link, as a path relative to the directory the symbolic link is in (the same as with "ln -s").
+//
+// A manifest entry (that is NOT a symbolic link) can also have:
+//
+//	• "mode"  — the permission-bits of the file, as an octal string (such as "0644"), and
+//	• "mtime" — the modification-time of the file, in RFC 3339 format.
+//
+// A strfs.FS cannot hold symbolic links, so they are resolved when the manifest is loaded.
+// A symbolic link to a file becomes a copy of that file (sharing its content — which is NOT copied).
+// A symbolic link to a directory becomes a copy of everything in that directory.
+// The target of a symbolic link must be in the manifest. Symbolic links that loop are an error.
+//
+// (So, saving a manifest writes what a symbolic link resolved to, rather than the symbolic link.)
+type manifestEntry struct {
+	Content *string `json:"content,omitempty" yaml:"content,omitempty"`
+	Base64  *string `json:"base64,omitempty"  yaml:"base64,omitempty"`
+	File    *string `json:"file,omitempty"    yaml:"file,omitempty"`
+	Mode    string  `json:"mode,omitempty"    yaml:"mode,omitempty"`
+	MTime   string  `json:"mtime,omitempty"   yaml:"mtime,omitempty"`
+	Symlink *string `json:"symlink,omitempty" yaml:"symlink,omitempty"`
+}
+
+// buildManifest turns a (decoded) manifest into a strfs.FS.
+//
+// 'files' is used to resolve "file" references. It can be nil, if the manifest has none.
+func buildManifest(manifest map[string]manifestEntry, files fs.FS) (FS, error) {
+	var names []string
+	for name := range manifest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fsys FS = make(FS, len(manifest))
+
+	for _, name := range names {
+		if !fs.ValidPath(name) || "." == name {
+			return nil, erorr.Errorf("manifest: %q: invalid path (paths must be slash-separated, and must not be rooted, or have \".\" or \"..\" elements)", name)
+		}
+		for parent := path.Dir(name); "." != parent; parent = path.Dir(parent) {
+			if entry, found := manifest[parent]; found {
+				if nil != entry.Symlink {
+					return nil, erorr.Errorf("manifest: %q: its parent %q is a symlink (and not a directory)", name, parent)
+				}
+				return nil, erorr.Errorf("manifest: %q: its parent %q is a file (and not a directory)", name, parent)
+			}
+		}
+
+		var entry manifestEntry = manifest[name]
+		if nil != entry.Symlink {
+			if err := checkManifestSymlink(entry); nil != err {
+				return nil, erorr.Errorf("manifest: %q: %w", name, err)
+			}
+			continue
+		}
+
+		regularfile, err := buildManifestEntry(entry, files)
+		if nil != err {
+			return nil, erorr.Errorf("manifest: %q: %w", name, err)
+		}
+
+		fsys[name] = regularfile
+	}
+
+	// The symlinks are resolved after all the (regular) files are built, because they can point to any of them.
+	for _, name := range names {
+		if nil == manifest[name].Symlink {
+			continue
+		}
+
+		if err := linkManifestSymlink(manifest, names, fsys, name, name, map[string]struct{}{}); nil != err {
+			return nil, erorr.Errorf("manifest: %q: %w", name, err)
+		}
+	}
+
+	return fsys, nil
+}
+
+func buildManifestEntry(entry manifestEntry, files fs.FS) (RegularFile, error) {
+	var regularfile RegularFile
+
+	if sources := countManifestSources(entry); 1 != sources {
+		return regularfile, erorr.Errorf("must have exactly one of \"content\", \"base64\", \"file\", or \"symlink\" (but actually has %d)", sources)
+	}
+
+	switch {
+	case nil != entry.Content:
+		regularfile.FileContent = CreateContent(*entry.Content)
+	case nil != entry.Base64:
+		data, err := base64.StdEncoding.DecodeString(*entry.Base64)
+		if nil != err {
+			return regularfile, erorr.Errorf("base64: %w", err)
+		}
+		regularfile.FileContent = CreateContent(string(data))
+	case nil != entry.File:
+		if nil == files {
+			return regularfile, erorr.Errorf("file: cannot load %q — no fs.FS was given to load it from", *entry.File)
+		}
+		data, err := fs.ReadFile(files, *entry.File)
+		if nil != err {
+			return regularfile, erorr.Errorf("file: %w", err)
+		}
+		regularfile.FileContent = CreateContent(string(data))
+	}
+
+	if "" != entry.Mode {
+		mode, err := parseFileMode(entry.Mode)
+		if nil != err {
+			return regularfile, erorr.Errorf("mode: %w", err)
+		}
+		regularfile.FileMode = mode
+	}
+
+	if "" != entry.MTime {
+		mtime, err := time.Parse(time.RFC3339Nano, entry.MTime)
+		if nil != err {
+			return regularfile, erorr.Errorf("mtime: %q is not valid (expected RFC 3339, such as \"2022-12-12T10:30:14Z\")", entry.MTime)
+		}
+		regularfile.FileModTime = mtime
+	}
+
+	return regularfile, nil
+}
+
+// checkManifestSymlink checks that a symlink manifest entry does not have anything that a symlink cannot have.
+func checkManifestSymlink(entry manifestEntry) error {
+	if sources := countManifestSources(entry); 1 != sources {
+		return erorr.Errorf("must have exactly one of \"content\", \"base64\", \"file\", or \"symlink\" (but actually has %d)", sources)
+	}
+	if "" != entry.Mode || "" != entry.MTime {
+		return erorr.Errorf("symlink: cannot have \"mode\" or \"mtime\" (it gets them from what it points to)")
+	}
+
+	return nil
+}
+
+// countManifestSources returns how many of "content", "base64", "file", and "symlink" a manifest entry has.
+func countManifestSources(entry manifestEntry) int {
+	var sources int
+	for _, source := range []*string{entry.Content, entry.Base64, entry.File, entry.Symlink} {
+		if nil != source {
+			sources++
+		}
+	}
+
+	return sources
+}
+
+// createManifest turns each regular file in 'fsys' into a manifest entry.
+//
+// Content that is valid UTF-8 is put inline (as "content"). Anything else is base64 encoded (as "base64").
+func createManifest(fsys fs.FS) (map[string]manifestEntry, error) {
+	var manifest map[string]manifestEntry = map[string]manifestEntry{}
+
+	err := exportWalk(fsys, func(name string, fileinfo fs.FileInfo, data []byte) error {
+		var entry manifestEntry
+
+		if utf8.Valid(data) {
+			var content string = string(data)
+			entry.Content = &content
+		} else {
+			var encoded string = base64.StdEncoding.EncodeToString(data)
+			entry.Base64 = &encoded
+		}
+
+		entry.Mode = formatFileMode(fileinfo.Mode())
+
+		if modtime := fileinfo.ModTime(); !modtime.IsZero() {
+			entry.MTime = modtime.Format(time.RFC3339Nano)
+		}
+
+		manifest[name] = entry
+		return nil
+	})
+	if nil != err {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// linkManifestSymlink makes 'name' be (a copy of) what the symlink manifest entry 'link' points to.
+//
+// If the symlink points to a directory, then everything in that directory is also put under 'name'
+// (following any symlinks that are in that directory).
+//
+// 'following' has the symlinks that are already being followed, to catch symlinks that loop.
+func linkManifestSymlink(manifest map[string]manifestEntry, names []string, fsys FS, name string, link string, following map[string]struct{}) error {
+	if _, found := following[link]; found {
+		return erorr.Errorf("symlink: %q loops back to itself", link)
+	}
+	following[link] = struct{}{}
+	defer delete(following, link)
+
+	target, err := resolveManifestPath(manifest, link)
+	if nil != err {
+		return err
+	}
+
+	// (The target cannot be a symlink, because resolveManifestPath follows all of them.)
+	if _, found := manifest[target]; found {
+		fsys[name] = fsys[target]
+		return nil
+	}
+
+	var prefix string
+	if "." != target {
+		prefix = target + "/"
+	}
+
+	var found bool
+	for _, other := range names {
+		if !strings.HasPrefix(other, prefix) {
+			continue
+		}
+		found = true
+
+		var linked string = name + "/" + other[len(prefix):]
+
+		if nil != manifest[other].Symlink {
+			if err := linkManifestSymlink(manifest, names, fsys, linked, other, following); nil != err {
+				return err
+			}
+			continue
+		}
+
+		fsys[linked] = fsys[other]
+	}
+	if !found {
+		return erorr.Errorf("symlink: %q does not exist", *manifest[link].Symlink)
+	}
+
+	return nil
+}
+
+// maxManifestSymlinks is the most symlinks that are followed to resolve a single path (the same as Linux's limit).
+const maxManifestSymlinks = 40
+
+// manifestSymlinkTarget returns the path (in the manifest) that the symlink 'link' (whose target is 'target') points to.
+func manifestSymlinkTarget(link string, target string) (string, error) {
+	if "" == target {
+		return "", erorr.Errorf("symlink: cannot be empty")
+	}
+	if path.IsAbs(target) {
+		return "", erorr.Errorf("symlink: %q must be a relative path", target)
+	}
+
+	var joined string = path.Join(path.Dir(link), target)
+	if ".." == joined || strings.HasPrefix(joined, "../") {
+		return "", erorr.Errorf("symlink: %q points outside of the manifest", target)
+	}
+
+	return joined, nil
+}
+
+// resolveManifestPath returns the path that 'name' is, after following all the symlinks in it.
+func resolveManifestPath(manifest map[string]manifestEntry, name string) (string, error) {
+	for followed := 0; ; followed++ {
+		var symlink bool
+
+		for index := 1; index <= len(name); index++ {
+			if index < len(name) && '/' != name[index] {
+				continue
+			}
+
+			entry, found := manifest[name[:index]]
+			if !found || nil == entry.Symlink {
+				continue
+			}
+			if maxManifestSymlinks <= followed {
+				return "", erorr.Errorf("symlink: too many levels of symlinks (more than %d)", maxManifestSymlinks)
+			}
+
+			target, err := manifestSymlinkTarget(name[:index], *entry.Symlink)
+			if nil != err {
+				return "", err
+			}
+
+			name = path.Join(target, name[index:])
+			symlink = true
+			break
+		}
+
+		if !symlink {
+			return name, nil
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"bytes"
+	"strings"
+	"testing/fstest"
+	"time"
+
+	"testing"
+)
+
+func TestLoadJSONManifest(t *testing.T) {
+
+	const manifest string = `{
+		"file1.txt":            {"content": "once\n", "mode": "0644", "mtime": "2022-12-12T10:30:14Z"},
+		"gmni/file3.gmni":      {"base64": "b25jZSB0d2ljZSB0aHJpY2UK", "mode": "0444"},
+		"gmni/fngr/file4.fngr": {"file": "plans/file4.fngr"}
+	}`
+
+	var files fstest.MapFS = fstest.MapFS{
+		"plans/file4.fngr": &fstest.MapFile{Data: []byte("Hello world! 😈"+"\n")},
+	}
+
+	actual, err := strfs.LoadJSONManifest(strings.NewReader(manifest), files)
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	var expected strfs.FS = strfs.FS{
+		"file1.txt": strfs.RegularFile{
+			FileContent: strfs.CreateContent("once"+"\n"),
+			FileModTime: time.Date(2022, 12, 12, 10, 30, 14, 0, time.UTC),
+			FileMode:    0644,
+		},
+		"gmni/file3.gmni": strfs.RegularFile{
+			FileContent: strfs.CreateContent("once twice thrice"+"\n"),
+			FileMode:    0444,
+		},
+		"gmni/fngr/file4.fngr": strfs.RegularFile{
+			FileContent: strfs.CreateContent("Hello world! 😈"+"\n"),
+		},
+	}
+
+	sameFS(t, expected, actual, true)
+}
+
+func TestLoadYAMLManifest(t *testing.T) {
+
+	const manifest string =
+		"file1.txt:"+"\n"+
+		"  content: |"+"\n"+
+		"    once"+"\n"+
+		"  mode: \"0644\""+"\n"+
+		"  mtime: 2022-12-12T10:30:14Z"+"\n"+
+		"gmni/file3.gmni:"+"\n"+
+		"  base64: b25jZSB0d2ljZSB0aHJpY2UK"+"\n"
+
+	actual, err := strfs.LoadYAMLManifest(strings.NewReader(manifest), nil)
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	var expected strfs.FS = strfs.FS{
+		"file1.txt": strfs.RegularFile{
+			FileContent: strfs.CreateContent("once"+"\n"),
+			FileModTime: time.Date(2022, 12, 12, 10, 30, 14, 0, time.UTC),
+			FileMode:    0644,
+		},
+		"gmni/file3.gmni": strfs.RegularFile{
+			FileContent: strfs.CreateContent("once twice thrice"+"\n"),
+		},
+	}
+
+	sameFS(t, expected, actual, true)
+}
+
+func TestLoadJSONManifest_symlink(t *testing.T) {
+
+	const manifest string = `{
+		"file1.txt":            {"content": "once\n", "mode": "0644"},
+		"gmni/file3.gmni":      {"content": "thrice\n"},
+		"gmni/fngr/file4.fngr": {"content": "fource\n"},
+		"gmni/fngr/once.txt":   {"symlink": "../../file1.txt"},
+		"latest.gmni":          {"symlink": "gmni/file3.gmni"},
+		"current":              {"symlink": "gmni"},
+		"finger.fngr":          {"symlink": "current/fngr/file4.fngr"},
+		"first.txt":            {"symlink": "current/fngr/once.txt"}
+	}`
+
+	actual, err := strfs.LoadJSONManifest(strings.NewReader(manifest), nil)
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	var once strfs.RegularFile = strfs.RegularFile{FileContent: strfs.CreateContent("once"+"\n"), FileMode: 0644}
+	var thrice strfs.RegularFile = strfs.RegularFile{FileContent: strfs.CreateContent("thrice"+"\n")}
+	var fource strfs.RegularFile = strfs.RegularFile{FileContent: strfs.CreateContent("fource"+"\n")}
+
+	var expected strfs.FS = strfs.FS{
+		"file1.txt":               once,
+		"gmni/file3.gmni":         thrice,
+		"gmni/fngr/file4.fngr":    fource,
+		"gmni/fngr/once.txt":      once,
+		"latest.gmni":             thrice,
+		"current/file3.gmni":      thrice,
+		"current/fngr/file4.fngr": fource,
+		"current/fngr/once.txt":   once,
+		"finger.fngr":             fource,
+		"first.txt":               once,
+	}
+
+	sameFS(t, expected, actual, true)
+}
+
+func TestLoadJSONManifest_error(t *testing.T) {
+
+	tests := []struct{
+		Manifest string
+		Expected string
+	}{
+		{
+			Manifest: `{"file1.txt": {"content": "once", "colour": "red"}}`,
+			Expected: `unknown field "colour"`,
+		},
+		{
+			Manifest: `{"file1.txt": {"content": "once", "base64": "b25jZQ=="}}`,
+			Expected: `"file1.txt": must have exactly one of`,
+		},
+		{
+			Manifest: `{"file1.txt": {}}`,
+			Expected: `"file1.txt": must have exactly one of`,
+		},
+		{
+			Manifest: `{"gmni/file3.gmni": {"base64": "!!!"}}`,
+			Expected: `"gmni/file3.gmni": base64:`,
+		},
+		{
+			Manifest: `{"file1.txt": {"content": "once", "mode": "0999"}}`,
+			Expected: `"file1.txt": mode:`,
+		},
+		{
+			Manifest: `{"file1.txt": {"content": "once", "mtime": "yesterday"}}`,
+			Expected: `"file1.txt": mtime:`,
+		},
+		{
+			Manifest: `{"link.txt": {"symlink": "file1.txt"}}`,
+			Expected: `"link.txt": symlink: "file1.txt" does not exist`,
+		},
+		{
+			Manifest: `{"file1.txt": {"content": "once"}, "link.txt": {"symlink": "file1.txt", "mode": "0644"}}`,
+			Expected: `"link.txt": symlink: cannot have "mode"`,
+		},
+		{
+			Manifest: `{"file1.txt": {"content": "once"}, "link.txt": {"symlink": "file1.txt", "content": "once"}}`,
+			Expected: `"link.txt": must have exactly one of`,
+		},
+		{
+			Manifest: `{"file1.txt": {"content": "once"}, "link.txt": {"symlink": "/file1.txt"}}`,
+			Expected: `"link.txt": symlink: "/file1.txt" must be a relative path`,
+		},
+		{
+			Manifest: `{"gmni/link.txt": {"symlink": "../../file1.txt"}}`,
+			Expected: `"gmni/link.txt": symlink: "../../file1.txt" points outside of the manifest`,
+		},
+		{
+			Manifest: `{"link1.txt": {"symlink": "link2.txt"}, "link2.txt": {"symlink": "link1.txt"}}`,
+			Expected: `"link1.txt": symlink: too many levels of symlinks`,
+		},
+		{
+			Manifest: `{"gmni/file3.gmni": {"content": "thrice"}, "gmni/loop": {"symlink": "."}}`,
+			Expected: `"gmni/loop": symlink: "gmni/loop" loops back to itself`,
+		},
+		{
+			Manifest: `{"file1.txt": {"content": "once"}, "link": {"symlink": "file1.txt"}, "link/file2.txt": {"content": "twice"}}`,
+			Expected: `"link/file2.txt": its parent "link" is a symlink`,
+		},
+		{
+			Manifest: `{"file1.txt": {"content": "once"}, "file2.txt": {"content": "twice"}, "file1.txt": {"content": "thrice"}}`,
+			Expected: `"file1.txt": duplicate path`,
+		},
+		{
+			Manifest: `["file1.txt"]`,
+			Expected: `must be a JSON object`,
+		},
+		{
+			Manifest: `{"../file1.txt": {"content": "once"}}`,
+			Expected: `"../file1.txt": invalid path`,
+		},
+		{
+			Manifest: `{"gmni": {"content": "once"}, "gmni/file3.gmni": {"content": "thrice"}}`,
+			Expected: `"gmni/file3.gmni": its parent "gmni" is a file`,
+		},
+		{
+			Manifest: `{"file4.fngr": {"file": "plans/file4.fngr"}}`,
+			Expected: `"file4.fngr": file:`,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		_, err := strfs.LoadJSONManifest(strings.NewReader(test.Manifest), nil)
+		if nil == err {
+			t.Errorf("For test #%d, expected an error but did not actually get one.", testNumber)
+			t.Logf("MANIFEST: %s", test.Manifest)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.Expected) {
+			t.Errorf("For test #%d, the actual error is not what was expected.", testNumber)
+			t.Logf("EXPECTED ERROR TO CONTAIN: %s", test.Expected)
+			t.Logf("ACTUAL   ERROR:            %s", err)
+			t.Logf("MANIFEST: %s", test.Manifest)
+			continue
+		}
+	}
+}
+
+func TestSaveJSONManifest_SaveYAMLManifest(t *testing.T) {
+
+	var expected strfs.FS = exampleFS()
+	expected["binary.bin"] = strfs.RegularFile{
+		FileContent: strfs.CreateContent("\xFF\xFE\x00\x01"),
+		FileModTime: time.Date(2022, 12, 12, 10, 30, 14, 0, time.UTC),
+		FileMode:    0600,
+	}
+
+	{
+		var buffer bytes.Buffer
+		if err := strfs.SaveJSONManifest(&buffer, expected); nil != err {
+			t.Errorf("Did not expect an error but actually got one.")
+			t.Logf("ERROR: (%T) %s", err, err)
+			return
+		}
+
+		actual, err := strfs.LoadJSONManifest(&buffer, nil)
+		if nil != err {
+			t.Errorf("Did not expect an error but actually got one.")
+			t.Logf("ERROR: (%T) %s", err, err)
+			return
+		}
+
+		if !sameFS(t, expected, actual, true) {
+			return
+		}
+	}
+
+	{
+		var buffer bytes.Buffer
+		if err := strfs.SaveYAMLManifest(&buffer, expected); nil != err {
+			t.Errorf("Did not expect an error but actually got one.")
+			t.Logf("ERROR: (%T) %s", err, err)
+			return
+		}
+
+		actual, err := strfs.LoadYAMLManifest(&buffer, nil)
+		if nil != err {
+			t.Errorf("Did not expect an error but actually got one.")
+			t.Logf("ERROR: (%T) %s", err, err)
+			return
+		}
+
+		if !sameFS(t, expected, actual, true) {
+			return
+		}
+	}
+}
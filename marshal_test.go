@@ -0,0 +1,367 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"io/fs"
+	"time"
+
+	"testing"
+)
+
+func TestContent_marshal(t *testing.T) {
+
+	tests := []struct{
+		Content      strfs.Content
+		ExpectedJSON string
+	}{
+		{
+			Content:      strfs.EmptyContent(),
+			ExpectedJSON: `null`,
+		},
+		{
+			Content:      strfs.CreateContent(""),
+			ExpectedJSON: `""`,
+		},
+		{
+			Content:      strfs.CreateContent("Hello world! 😈"),
+			ExpectedJSON: `"Hello world! 😈"`,
+		},
+		{
+			Content:      strfs.CreateContent("\x89PNG\r\n\x1A\n\xFF"),
+			ExpectedJSON: `{"base64":"iVBORw0KGgr/"}`,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		{
+			actualBytes, err := json.Marshal(test.Content)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			if expected, actual := test.ExpectedJSON, string(actualBytes); expected != actual {
+				t.Errorf("For test #%d, the actual JSON was not what was expected.", testNumber)
+				t.Logf("EXPECTED JSON: %s", expected)
+				t.Logf("ACTUAL   JSON: %s", actual)
+				continue
+			}
+
+			var content strfs.Content
+			if err := json.Unmarshal(actualBytes, &content); nil != err {
+				t.Errorf("For test #%d, did not expect an error when unmarshaling JSON but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			if !sameContent(test.Content, content) {
+				t.Errorf("For test #%d, the content unmarshaled from JSON was not what was expected.", testNumber)
+				t.Logf("EXPECTED CONTENT: %q (empty=%t)", test.Content.String(), strfs.EmptyContent() == test.Content)
+				t.Logf("ACTUAL   CONTENT: %q (empty=%t)", content.String(), strfs.EmptyContent() == content)
+				continue
+			}
+		}
+
+		{
+			var buffer bytes.Buffer
+			if err := gob.NewEncoder(&buffer).Encode(test.Content); nil != err {
+				t.Errorf("For test #%d, did not expect an error when gob-encoding but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			var content strfs.Content
+			if err := gob.NewDecoder(&buffer).Decode(&content); nil != err {
+				t.Errorf("For test #%d, did not expect an error when gob-decoding but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			if !sameContent(test.Content, content) {
+				t.Errorf("For test #%d, the content gob-decoded was not what was expected.", testNumber)
+				t.Logf("EXPECTED CONTENT: %q (empty=%t)", test.Content.String(), strfs.EmptyContent() == test.Content)
+				t.Logf("ACTUAL   CONTENT: %q (empty=%t)", content.String(), strfs.EmptyContent() == content)
+				continue
+			}
+		}
+	}
+}
+
+func TestContent_UnmarshalJSON_error(t *testing.T) {
+
+	tests := []struct{
+		JSON string
+	}{
+		{
+			JSON: `5`,
+		},
+		{
+			JSON: `{}`,
+		},
+		{
+			JSON: `{"base64":"!!!"}`,
+		},
+	}
+
+	for testNumber, test := range tests {
+		var content strfs.Content
+		if err := json.Unmarshal([]byte(test.JSON), &content); nil == err {
+			t.Errorf("For test #%d, expected an error but did not actually get one.", testNumber)
+			t.Logf("JSON: %s", test.JSON)
+			continue
+		}
+	}
+}
+
+func TestContent_MarshalText(t *testing.T) {
+
+	tests := []struct{
+		Content         strfs.Content
+		ExpectedText    string
+		ExpectedContent strfs.Content
+	}{
+		{
+			Content:         strfs.EmptyContent(),
+			ExpectedText:    "",
+			ExpectedContent: strfs.CreateContent(""), // Text cannot say "empty", so this is NOT strfs.EmptyContent().
+		},
+		{
+			Content:         strfs.CreateContent(""),
+			ExpectedText:    "",
+			ExpectedContent: strfs.CreateContent(""),
+		},
+		{
+			Content:         strfs.CreateContent("\x89PNG\r\n\x1A\n\xFF"),
+			ExpectedText:    "\x89PNG\r\n\x1A\n\xFF",
+			ExpectedContent: strfs.CreateContent("\x89PNG\r\n\x1A\n\xFF"),
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		actualBytes, err := test.Content.MarshalText()
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+			t.Logf("ERROR: (%T) %s", err, err)
+			continue
+		}
+
+		if expected, actual := test.ExpectedText, string(actualBytes); expected != actual {
+			t.Errorf("For test #%d, the actual text was not what was expected.", testNumber)
+			t.Logf("EXPECTED TEXT: %q", expected)
+			t.Logf("ACTUAL   TEXT: %q", actual)
+			continue
+		}
+
+		var content strfs.Content
+		if err := content.UnmarshalText(actualBytes); nil != err {
+			t.Errorf("For test #%d, did not expect an error when unmarshaling text but actually got one.", testNumber)
+			t.Logf("ERROR: (%T) %s", err, err)
+			continue
+		}
+
+		if !sameContent(test.ExpectedContent, content) {
+			t.Errorf("For test #%d, the content unmarshaled from text was not what was expected.", testNumber)
+			t.Logf("EXPECTED CONTENT: %q (empty=%t)", test.ExpectedContent.String(), strfs.EmptyContent() == test.ExpectedContent)
+			t.Logf("ACTUAL   CONTENT: %q (empty=%t)", content.String(), strfs.EmptyContent() == content)
+			continue
+		}
+	}
+}
+
+func TestRegularFile_marshal(t *testing.T) {
+
+	tests := []struct{
+		RegularFile  strfs.RegularFile
+		ExpectedJSON string
+		ExpectedText string
+	}{
+		{
+			RegularFile: strfs.RegularFile{
+				FileContent: strfs.CreateContent("<!DOCTYPE html>"+"\n"+"<html><body>Hello world!</body></html>"),
+				FileName:    "helloworld.html",
+				FileModTime: time.Date(2022, 12, 12, 10, 30, 14, 2, time.UTC),
+				FileMode:    0644,
+			},
+			ExpectedJSON: `{"name":"helloworld.html","mtime":"2022-12-12T10:30:14.000000002Z","mode":"0644","content":"\u003c!DOCTYPE html\u003e\n\u003chtml\u003e\u003cbody\u003eHello world!\u003c/body\u003e\u003c/html\u003e"}`,
+			ExpectedText: `strfs.RegularFile name="helloworld.html" mode=0644 mtime=2022-12-12T10:30:14.000000002Z`+"\n"+"<!DOCTYPE html>"+"\n"+"<html><body>Hello world!</body></html>",
+		},
+		{
+			RegularFile: strfs.RegularFile{
+				FileContent: strfs.EmptyContent(),
+				FileName:    "nothing here.txt",
+				FileModTime: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			ExpectedJSON: `{"name":"nothing here.txt","mtime":"1970-01-01T00:00:00Z","mode":"0000","content":null}`,
+			ExpectedText: `strfs.RegularFile name="nothing here.txt" mode=0000 mtime=1970-01-01T00:00:00Z`,
+		},
+		{
+			RegularFile: strfs.RegularFile{
+				FileContent: strfs.CreateContent("\x89PNG\r\n\x1A\n\xFF"),
+				FileName:    "image\n.png",
+				FileModTime: time.Date(2023, 3, 4, 5, 6, 7, 0, time.FixedZone("", -7*60*60)),
+				FileMode:    0755,
+			},
+			ExpectedJSON: `{"name":"image\n.png","mtime":"2023-03-04T05:06:07-07:00","mode":"0755","content":{"base64":"iVBORw0KGgr/"}}`,
+			ExpectedText: `strfs.RegularFile name="image\n.png" mode=0755 mtime=2023-03-04T05:06:07-07:00`+"\n"+"\x89PNG\r\n\x1A\n\xFF",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		{
+			actualBytes, err := json.Marshal(test.RegularFile)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			if expected, actual := test.ExpectedJSON, string(actualBytes); expected != actual {
+				t.Errorf("For test #%d, the actual JSON was not what was expected.", testNumber)
+				t.Logf("EXPECTED JSON: %s", expected)
+				t.Logf("ACTUAL   JSON: %s", actual)
+				continue
+			}
+
+			var regularfile strfs.RegularFile
+			if err := json.Unmarshal(actualBytes, &regularfile); nil != err {
+				t.Errorf("For test #%d, did not expect an error when unmarshaling JSON but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			if !sameRegularFile(t, test.RegularFile, regularfile) {
+				t.Errorf("For test #%d, the regular-file unmarshaled from JSON was not what was expected.", testNumber)
+				continue
+			}
+		}
+
+		{
+			actualBytes, err := test.RegularFile.MarshalText()
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			if expected, actual := test.ExpectedText, string(actualBytes); expected != actual {
+				t.Errorf("For test #%d, the actual text was not what was expected.", testNumber)
+				t.Logf("EXPECTED TEXT: %q", expected)
+				t.Logf("ACTUAL   TEXT: %q", actual)
+				continue
+			}
+
+			var regularfile strfs.RegularFile
+			if err := regularfile.UnmarshalText(actualBytes); nil != err {
+				t.Errorf("For test #%d, did not expect an error when unmarshaling text but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			if !sameRegularFile(t, test.RegularFile, regularfile) {
+				t.Errorf("For test #%d, the regular-file unmarshaled from text was not what was expected.", testNumber)
+				continue
+			}
+		}
+
+		{
+			var buffer bytes.Buffer
+			if err := gob.NewEncoder(&buffer).Encode(test.RegularFile); nil != err {
+				t.Errorf("For test #%d, did not expect an error when gob-encoding but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			var regularfile strfs.RegularFile
+			if err := gob.NewDecoder(&buffer).Decode(&regularfile); nil != err {
+				t.Errorf("For test #%d, did not expect an error when gob-decoding but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			if !sameRegularFile(t, test.RegularFile, regularfile) {
+				t.Errorf("For test #%d, the regular-file gob-decoded was not what was expected.", testNumber)
+				continue
+			}
+		}
+	}
+}
+
+func TestRegularFile_UnmarshalText_error(t *testing.T) {
+
+	tests := []struct{
+		Text string
+	}{
+		{
+			Text: ``,
+		},
+		{
+			Text: `strfs.RegularFile name=helloworld.html mode=0644 mtime=2022-12-12T10:30:14Z`,
+		},
+		{
+			Text: `strfs.RegularFile name="helloworld.html" mode=0999 mtime=2022-12-12T10:30:14Z`,
+		},
+		{
+			Text: `strfs.RegularFile name="helloworld.html" mode=01000 mtime=2022-12-12T10:30:14Z`,
+		},
+		{
+			Text: `strfs.RegularFile name="helloworld.html" mode=0644`,
+		},
+		{
+			Text: `strfs.RegularFile name="helloworld.html" mode=0644 mtime=yesterday`,
+		},
+	}
+
+	for testNumber, test := range tests {
+		var regularfile strfs.RegularFile
+		if err := regularfile.UnmarshalText([]byte(test.Text)); nil == err {
+			t.Errorf("For test #%d, expected an error but did not actually get one.", testNumber)
+			t.Logf("TEXT: %q", test.Text)
+			continue
+		}
+	}
+}
+
+func sameContent(expected strfs.Content, actual strfs.Content) bool {
+	if (strfs.EmptyContent() == expected) != (strfs.EmptyContent() == actual) {
+		return false
+	}
+
+	return expected.String() == actual.String()
+}
+
+func sameRegularFile(t *testing.T, expected strfs.RegularFile, actual strfs.RegularFile) bool {
+	t.Helper()
+
+	var same bool = true
+
+	if expected.FileName != actual.FileName {
+		t.Logf("EXPECTED NAME: %q", expected.FileName)
+		t.Logf("ACTUAL   NAME: %q", actual.FileName)
+		same = false
+	}
+	if !expected.FileModTime.Equal(actual.FileModTime) {
+		t.Logf("EXPECTED MTIME: %s", expected.FileModTime)
+		t.Logf("ACTUAL   MTIME: %s", actual.FileModTime)
+		same = false
+	}
+	if expected.FileMode != actual.FileMode {
+		t.Logf("EXPECTED MODE: %s", fs.FileMode(expected.FileMode))
+		t.Logf("ACTUAL   MODE: %s", fs.FileMode(actual.FileMode))
+		same = false
+	}
+	if !sameContent(expected.FileContent, actual.FileContent) {
+		t.Logf("EXPECTED CONTENT: %q", expected.FileContent.String())
+		t.Logf("ACTUAL   CONTENT: %q", actual.FileContent.String())
+		same = false
+	}
+
+	return same
+}
@@ -0,0 +1,178 @@
+package strfs
+
+import (
+	"io"
+)
+
+// MultiContent is the concatenation of several strfs.Content, as if they were one strfs.Content.
+//
+// The strings that the strfs.Content are wrapping are NOT copied.
+// Size, Read, and Seek all work in terms of the combined bytes.
+//
+// Example usage:
+//
+//	var header strfs.Content = strfs.CreateContent("<!DOCTYPE html>"+"\n"+"<html><body>")
+//	var body   strfs.Content = strfs.CreateContent("Hello world!")
+//	var footer strfs.Content = strfs.CreateContent("</body></html>")
+//
+//	var content strfs.MultiContent = strfs.CreateMultiContent(header, body, footer)
+type MultiContent struct {
+	pieces internalPieces
+	initialized bool
+	closed bool
+}
+
+var (
+	// A trick to make sure strfs.MultiContent fits the io.ReadSeekCloser interface.
+	// This is a compile-time check.
+	_ io.ReadSeekCloser = &MultiContent{}
+
+	// A trick to make sure strfs.MultiContent fits the io.ReaderAt interface.
+	// This is a compile-time check.
+	_ io.ReaderAt = &MultiContent{}
+)
+
+// CreateMultiContent returns a strfs.MultiContent whose content is each of the strfs.Content given to it, one after the other.
+//
+// Any empty strfs.Content (see EmptyContent) given to it is skipped.
+func CreateMultiContent(contents ...Content) MultiContent {
+	var pieces internalPieces
+
+	for _, content := range contents {
+		if EmptyContent() == content {
+			continue
+		}
+
+		content.appendTo(&pieces)
+	}
+
+	return MultiContent{
+		pieces:pieces,
+		initialized:true,
+	}
+}
+
+// Close will stop the Read method from working.
+//
+// Close can safely be called more than once.
+//
+// Close makes strfs.MultiContent fit the io.Closer interface.
+func (receiver *MultiContent) Close() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.closed = true
+	return nil
+}
+
+// Closed returns whether a strfs.MultiContent is closed or not.
+func (receiver *MultiContent) Closed() bool {
+	if nil == receiver {
+		return true
+	}
+	if !receiver.initialized {
+		return true
+	}
+
+	return receiver.closed
+}
+
+// Content returns a strfs.Content of the combined content, so that it can be the FileContent of a strfs.RegularFile
+// (and thus be put into a strfs.FS, or a strfs.Tree).
+//
+// The strings that the strfs.Content are wrapping are NOT joined into a single string, and are NOT copied.
+// (Unless the String method of the returned strfs.Content is called.)
+//
+// If the strfs.MultiContent is empty, then the returned strfs.Content is also empty (see EmptyContent).
+//
+// Example usage:
+//
+//	var content strfs.MultiContent = strfs.CreateMultiContent(header, body, footer)
+//
+//	var regularfile strfs.RegularFile = strfs.RegularFile{
+//		FileContent: content.Content(),
+//		FileName:    "index.html",
+//		FileModTime: time.Now(),
+//	}
+func (receiver *MultiContent) Content() Content {
+	if nil == receiver {
+		return EmptyContent()
+	}
+	if !receiver.initialized {
+		return EmptyContent()
+	}
+
+	return createPiecesContent(receiver.pieces)
+}
+
+// Read reads up to len(p) bytes of the combined content into 'p'.
+// Read returns the number of bytes actually read, and any errors it encountered.
+//
+// Read makes strfs.MultiContent fit the io.Reader interface.
+func (receiver *MultiContent) Read(p []byte) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		return 0, errClosed
+	}
+
+	return receiver.pieces.Read(p)
+}
+
+// ReadAt reads len(p) bytes of the combined content, starting at 'offset', into 'p'.
+//
+// ReadAt makes strfs.MultiContent fit the io.ReaderAt interface.
+func (receiver *MultiContent) ReadAt(p []byte, offset int64) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		return 0, errClosed
+	}
+
+	return receiver.pieces.ReadAt(p, offset)
+}
+
+// Seek sets the offset for the next Read.
+// The offset is in terms of the combined content.
+//
+// Seek makes strfs.MultiContent fit the io.Seeker interface.
+func (receiver *MultiContent) Seek(offset int64, whence int) (int64, error) {
+	if nil == receiver {
+		var nada int64
+		return nada, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		var nada int64
+		return nada, errClosed
+	}
+
+	return receiver.pieces.Seek(offset, whence)
+}
+
+// Size returns the combined size of all the content as the number of bytes.
+func (receiver *MultiContent) Size() int64 {
+	if nil == receiver {
+		return 0
+	}
+
+	return receiver.pieces.Size()
+}
+
+// String returns all the content concatenated into a single string.
+//
+// Note that (unlike Read, ReadAt, and Seek) String DOES copy.
+//
+// String makes *strfs.MultiContent fit the fmt.Stringer interface.
+func (receiver *MultiContent) String() string {
+	if nil == receiver {
+		return ""
+	}
+
+	return receiver.pieces.String()
+}
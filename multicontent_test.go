@@ -0,0 +1,156 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"io"
+	"io/fs"
+	"strings"
+
+	"testing"
+)
+
+func TestCreateMultiContent(t *testing.T) {
+
+	tests := []struct{
+		Contents []string
+	}{
+		{
+			Contents: []string{},
+		},
+		{
+			Contents: []string{""},
+		},
+
+
+
+		{
+			Contents: []string{"once"},
+		},
+		{
+			Contents: []string{"once", " ", "twice"},
+		},
+		{
+			Contents: []string{"once", "", " twice", "", "", " thrice", " fource"},
+		},
+
+
+
+		{
+			Contents: []string{"<!DOCTYPE html>"+"\n"+"<html><body>", "Hello world! 😈", "</body></html>"},
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var contents []strfs.Content
+		for _, s := range test.Contents {
+			contents = append(contents, strfs.CreateContent(s))
+		}
+		contents = append(contents, strfs.EmptyContent())
+
+		var expected string = strings.Join(test.Contents, "")
+
+		var content strfs.MultiContent = strfs.CreateMultiContent(contents...)
+
+		if expected, actual := false, content.Closed(); expected != actual {
+			t.Errorf("For test #%d, did not expect multi-content to be closed but actually was.", testNumber)
+			t.Logf("CONTENTS: %#v", test.Contents)
+			continue
+		}
+
+		if expected, actual := int64(len(expected)), content.Size(); expected != actual {
+			t.Errorf("For test #%d, the actual multi-content-size was not what was expected.", testNumber)
+			t.Logf("EXPECTED MULTI-CONTENT-SIZE: %d", expected)
+			t.Logf("ACTUAL   MULTI-CONTENT-SIZE: %d", actual)
+			t.Logf("CONTENTS: %#v", test.Contents)
+			continue
+		}
+
+		if expected, actual := expected, content.String(); expected != actual {
+			t.Errorf("For test #%d, the actual multi-content was not what was expected.", testNumber)
+			t.Logf("EXPECTED MULTI-CONTENT: %q", expected)
+			t.Logf("ACTUAL   MULTI-CONTENT: %q", actual)
+			continue
+		}
+
+		for offset := int64(0); offset <= content.Size(); offset++ {
+			_, err := content.Seek(offset, io.SeekStart)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				t.Logf("OFFSET: %d", offset)
+				break
+			}
+
+			actualBytes, err := io.ReadAll(&content)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				t.Logf("OFFSET: %d", offset)
+				break
+			}
+
+			if expected, actual := expected[offset:], string(actualBytes); expected != actual {
+				t.Errorf("For test #%d, the actual multi-content read after seeking was not what was expected.", testNumber)
+				t.Logf("EXPECTED MULTI-CONTENT: %q", expected)
+				t.Logf("ACTUAL   MULTI-CONTENT: %q", actual)
+				t.Logf("OFFSET: %d", offset)
+				break
+			}
+		}
+
+		{
+			err := content.Close()
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			var b [1]byte
+			_, err = content.Read(b[:])
+			if nil == err {
+				t.Errorf("For test #%d, expected an error but did not actually get one.", testNumber)
+				continue
+			}
+		}
+	}
+}
+
+func TestMultiContent_Content(t *testing.T) {
+
+	var body strfs.NormalizedContent = strfs.NormalizeContent(strfs.CreateContent("Hello world!"+"\r\n"), strfs.NormalizeCRLFToLF)
+
+	var inner strfs.MultiContent = strfs.CreateMultiContent(
+		strfs.CreateContent("<html><body>"),
+		body.Content(),
+	)
+
+	var outer strfs.MultiContent = strfs.CreateMultiContent(
+		strfs.CreateContent("<!DOCTYPE html>"+"\n"),
+		inner.Content(),
+		strfs.EmptyContent(),
+		strfs.CreateContent("</body></html>"),
+	)
+
+	var fsys strfs.FS = strfs.FS{
+		"index.html": strfs.RegularFile{FileContent: outer.Content(), FileMode: 0644},
+	}
+
+	data, err := fs.ReadFile(fsys, "index.html")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if expected, actual := "<!DOCTYPE html>"+"\n"+"<html><body>Hello world!"+"\n"+"</body></html>", string(data); expected != actual {
+		t.Errorf("The actual file content was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	var empty strfs.MultiContent
+	if strfs.EmptyContent() != empty.Content() {
+		t.Errorf("Expected the content of an empty strfs.MultiContent to be empty.")
+	}
+}
@@ -0,0 +1,258 @@
+package strfs
+
+import (
+	"io"
+	"strings"
+)
+
+// Normalization says how NormalizeContent should transform the content it is given.
+//
+// Normalizations can be combined with the bitwise-or operator. For example:
+//
+//	strfs.NormalizeCRLFToLF | strfs.NormalizeStripBOM
+type Normalization uint
+
+const (
+	// NormalizeCRLFToLF turns every "\r\n" into "\n".
+	//
+	// A "\r" that is NOT followed by a "\n" is left alone.
+	NormalizeCRLFToLF Normalization = 1 << iota
+
+	// NormalizeLFToCRLF turns every "\n" that is NOT already preceded by a "\r" into "\r\n".
+	//
+	// Combined with NormalizeCRLFToLF, the result is the same as NormalizeLFToCRLF by itself.
+	NormalizeLFToCRLF
+
+	// NormalizeStripBOM removes a leading UTF-8 byte-order-mark, if there is one.
+	NormalizeStripBOM
+
+	// NormalizeAddBOM adds a leading UTF-8 byte-order-mark, if there isn't one already.
+	//
+	// Combined with NormalizeStripBOM, the result is the same as NormalizeAddBOM by itself.
+	NormalizeAddBOM
+)
+
+const bom = "\xEF\xBB\xBF"
+
+// NormalizedContent is a view of a strfs.Content with its line-endings and/or its byte-order-mark normalized.
+//
+// The original string is NOT mutated (or copied).
+// Size, Read, and Seek all work in terms of the normalized bytes.
+//
+// Example usage:
+//
+//	var content strfs.Content = strfs.CreateContent("\uFEFF"+"# Hello world!"+"\r\n"+"Welcome to my document."+"\n")
+//
+//	var normalized strfs.NormalizedContent = strfs.NormalizeContent(content, strfs.NormalizeCRLFToLF | strfs.NormalizeStripBOM)
+//
+//	// normalized.String() == "# Hello world!"+"\n"+"Welcome to my document."+"\n"
+type NormalizedContent struct {
+	pieces internalPieces
+	normalization Normalization
+	initialized bool
+	closed bool
+}
+
+var (
+	// A trick to make sure strfs.NormalizedContent fits the io.ReadSeekCloser interface.
+	// This is a compile-time check.
+	_ io.ReadSeekCloser = &NormalizedContent{}
+
+	// A trick to make sure strfs.NormalizedContent fits the io.ReaderAt interface.
+	// This is a compile-time check.
+	_ io.ReaderAt = &NormalizedContent{}
+)
+
+// NormalizeContent returns a strfs.NormalizedContent that is a normalized view of the strfs.Content given to it.
+//
+// If 'content' is empty (see EmptyContent), then the returned strfs.NormalizedContent is also empty (and thus closed).
+func NormalizeContent(content Content, normalization Normalization) NormalizedContent {
+	if EmptyContent() == content {
+		return NormalizedContent{}
+	}
+
+	var value string = content.String()
+
+	var pieces internalPieces
+	{
+		var hasBOM bool = strings.HasPrefix(value, bom)
+
+		switch {
+		case 0 != normalization & NormalizeAddBOM:
+			if !hasBOM {
+				pieces.append(bom)
+			}
+		case 0 != normalization & NormalizeStripBOM:
+			if hasBOM {
+				value = value[len(bom):]
+			}
+		}
+	}
+
+	switch {
+	case 0 != normalization & NormalizeLFToCRLF:
+		for {
+			index := strings.IndexByte(value, '\n')
+			if index < 0 {
+				break
+			}
+
+			if 0 < index && '\r' == value[index-1] {
+				pieces.append(value[:index+1])
+			} else {
+				pieces.append(value[:index])
+				pieces.append("\r\n")
+			}
+			value = value[index+1:]
+		}
+	case 0 != normalization & NormalizeCRLFToLF:
+		for {
+			index := strings.Index(value, "\r\n")
+			if index < 0 {
+				break
+			}
+
+			pieces.append(value[:index])
+			value = value[index+1:]
+		}
+	}
+	pieces.append(value)
+
+	return NormalizedContent{
+		pieces:pieces,
+		normalization:normalization,
+		initialized:true,
+	}
+}
+
+// Close will stop the Read method from working.
+//
+// Close can safely be called more than once.
+//
+// Close makes strfs.NormalizedContent fit the io.Closer interface.
+func (receiver *NormalizedContent) Close() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.closed = true
+	return nil
+}
+
+// Closed returns whether a strfs.NormalizedContent is closed or not.
+func (receiver *NormalizedContent) Closed() bool {
+	if nil == receiver {
+		return true
+	}
+	if !receiver.initialized {
+		return true
+	}
+
+	return receiver.closed
+}
+
+// Content returns a strfs.Content of the normalized content, so that it can be the FileContent of a strfs.RegularFile
+// (and thus be put into a strfs.FS, or a strfs.Tree).
+//
+// The normalized content is NOT joined into a single string, and the original string is NOT copied.
+// (Unless the String method of the returned strfs.Content is called.)
+//
+// If the strfs.NormalizedContent is empty, then the returned strfs.Content is also empty (see EmptyContent).
+//
+// Example usage:
+//
+//	var normalized strfs.NormalizedContent = strfs.NormalizeContent(content, strfs.NormalizeCRLFToLF)
+//
+//	var regularfile strfs.RegularFile = strfs.RegularFile{
+//		FileContent: normalized.Content(),
+//		FileName:    "message.md",
+//		FileModTime: time.Now(),
+//	}
+func (receiver *NormalizedContent) Content() Content {
+	if nil == receiver {
+		return EmptyContent()
+	}
+	if !receiver.initialized {
+		return EmptyContent()
+	}
+
+	return createPiecesContent(receiver.pieces)
+}
+
+// Normalization returns the normalization that was given to NormalizeContent.
+func (receiver *NormalizedContent) Normalization() Normalization {
+	if nil == receiver {
+		return 0
+	}
+
+	return receiver.normalization
+}
+
+// Read reads up to len(p) bytes of the normalized content into 'p'.
+// Read returns the number of bytes actually read, and any errors it encountered.
+//
+// Read makes strfs.NormalizedContent fit the io.Reader interface.
+func (receiver *NormalizedContent) Read(p []byte) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		return 0, errClosed
+	}
+
+	return receiver.pieces.Read(p)
+}
+
+// ReadAt reads len(p) bytes of the normalized content, starting at 'offset', into 'p'.
+//
+// ReadAt makes strfs.NormalizedContent fit the io.ReaderAt interface.
+func (receiver *NormalizedContent) ReadAt(p []byte, offset int64) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		return 0, errClosed
+	}
+
+	return receiver.pieces.ReadAt(p, offset)
+}
+
+// Seek sets the offset for the next Read.
+// The offset is in terms of the normalized content.
+//
+// Seek makes strfs.NormalizedContent fit the io.Seeker interface.
+func (receiver *NormalizedContent) Seek(offset int64, whence int) (int64, error) {
+	if nil == receiver {
+		var nada int64
+		return nada, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		var nada int64
+		return nada, errClosed
+	}
+
+	return receiver.pieces.Seek(offset, whence)
+}
+
+// Size returns the size of the normalized content as the number of bytes.
+func (receiver *NormalizedContent) Size() int64 {
+	if nil == receiver {
+		return 0
+	}
+
+	return receiver.pieces.Size()
+}
+
+// String returns the normalized content.
+//
+// String makes *strfs.NormalizedContent fit the fmt.Stringer interface.
+func (receiver *NormalizedContent) String() string {
+	if nil == receiver {
+		return ""
+	}
+
+	return receiver.pieces.String()
+}
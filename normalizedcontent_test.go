@@ -0,0 +1,231 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"io"
+	"io/fs"
+
+	"testing"
+)
+
+func TestNormalizeContent(t *testing.T) {
+
+	tests := []struct{
+		Content       string
+		Normalization strfs.Normalization
+		Expected      string
+	}{
+		{
+			Content:       "",
+			Normalization: strfs.NormalizeCRLFToLF,
+			Expected:      "",
+		},
+		{
+			Content:       "once twice thrice fource",
+			Normalization: 0,
+			Expected:      "once twice thrice fource",
+		},
+
+
+
+		{
+			Content:       "# Hello world!"+"\r\r"+"Welcome to my document."+"\n",
+			Normalization: strfs.NormalizeCRLFToLF,
+			Expected:      "# Hello world!"+"\r\r"+"Welcome to my document."+"\n",
+		},
+		{
+			Content:       "once"+"\r\n"+"twice"+"\n"+"thrice"+"\r\n"+"\r\n"+"fource"+"\r\n",
+			Normalization: strfs.NormalizeCRLFToLF,
+			Expected:      "once"+"\n"+"twice"+"\n"+"thrice"+"\n"+"\n"+"fource"+"\n",
+		},
+		{
+			Content:       "\r\n"+"once"+"\r"+"\r\n",
+			Normalization: strfs.NormalizeCRLFToLF,
+			Expected:      "\n"+"once"+"\r"+"\n",
+		},
+
+
+
+		{
+			Content:       "once"+"\r\n"+"twice"+"\n"+"thrice"+"\n"+"\n"+"fource",
+			Normalization: strfs.NormalizeLFToCRLF,
+			Expected:      "once"+"\r\n"+"twice"+"\r\n"+"thrice"+"\r\n"+"\r\n"+"fource",
+		},
+		{
+			Content:       "\n"+"once"+"\r",
+			Normalization: strfs.NormalizeLFToCRLF,
+			Expected:      "\r\n"+"once"+"\r",
+		},
+		{
+			Content:       "once"+"\r\n"+"twice"+"\n",
+			Normalization: strfs.NormalizeLFToCRLF | strfs.NormalizeCRLFToLF,
+			Expected:      "once"+"\r\n"+"twice"+"\r\n",
+		},
+
+
+
+		{
+			Content:       "\uFEFF"+"Hello world! 😈",
+			Normalization: strfs.NormalizeStripBOM,
+			Expected:      "Hello world! 😈",
+		},
+		{
+			Content:       "Hello world! 😈",
+			Normalization: strfs.NormalizeStripBOM,
+			Expected:      "Hello world! 😈",
+		},
+		{
+			Content:       "Hello world! 😈",
+			Normalization: strfs.NormalizeAddBOM,
+			Expected:      "\uFEFF"+"Hello world! 😈",
+		},
+		{
+			Content:       "\uFEFF"+"Hello world! 😈",
+			Normalization: strfs.NormalizeAddBOM | strfs.NormalizeStripBOM,
+			Expected:      "\uFEFF"+"Hello world! 😈",
+		},
+		{
+			Content:       "\uFEFF"+"\r\n"+"۰	۱	۲"+"\r\n",
+			Normalization: strfs.NormalizeStripBOM | strfs.NormalizeCRLFToLF,
+			Expected:      "\n"+"۰	۱	۲"+"\n",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var content strfs.Content = strfs.CreateContent(test.Content)
+
+		var normalized strfs.NormalizedContent = strfs.NormalizeContent(content, test.Normalization)
+
+		if expected, actual := int64(len(test.Expected)), normalized.Size(); expected != actual {
+			t.Errorf("For test #%d, the actual normalized-content-size was not what was expected.", testNumber)
+			t.Logf("EXPECTED NORMALIZED-CONTENT-SIZE: %d", expected)
+			t.Logf("ACTUAL   NORMALIZED-CONTENT-SIZE: %d", actual)
+			t.Logf("CONTENT: %q", test.Content)
+			continue
+		}
+
+		if expected, actual := test.Expected, normalized.String(); expected != actual {
+			t.Errorf("For test #%d, the actual normalized-content was not what was expected.", testNumber)
+			t.Logf("EXPECTED NORMALIZED-CONTENT: %q", expected)
+			t.Logf("ACTUAL   NORMALIZED-CONTENT: %q", actual)
+			t.Logf("CONTENT: %q", test.Content)
+			continue
+		}
+
+		{
+			actualBytes, err := io.ReadAll(&normalized)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				t.Logf("CONTENT: %q", test.Content)
+				continue
+			}
+
+			if expected, actual := test.Expected, string(actualBytes); expected != actual {
+				t.Errorf("For test #%d, the actual normalized-content read was not what was expected.", testNumber)
+				t.Logf("EXPECTED NORMALIZED-CONTENT: %q", expected)
+				t.Logf("ACTUAL   NORMALIZED-CONTENT: %q", actual)
+				t.Logf("CONTENT: %q", test.Content)
+				continue
+			}
+		}
+
+		for offset := int64(0); offset <= normalized.Size(); offset++ {
+			n, err := normalized.Seek(-offset, io.SeekEnd)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				t.Logf("OFFSET: %d", offset)
+				t.Logf("CONTENT: %q", test.Content)
+				break
+			}
+			if expected, actual := normalized.Size()-offset, n; expected != actual {
+				t.Errorf("For test #%d, the actual seek position was not what was expected.", testNumber)
+				t.Logf("EXPECTED POSITION: %d", expected)
+				t.Logf("ACTUAL   POSITION: %d", actual)
+				t.Logf("CONTENT: %q", test.Content)
+				break
+			}
+
+			actualBytes, err := io.ReadAll(&normalized)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				t.Logf("OFFSET: %d", offset)
+				t.Logf("CONTENT: %q", test.Content)
+				break
+			}
+
+			if expected, actual := test.Expected[len(test.Expected)-int(offset):], string(actualBytes); expected != actual {
+				t.Errorf("For test #%d, the actual normalized-content read after seeking was not what was expected.", testNumber)
+				t.Logf("EXPECTED NORMALIZED-CONTENT: %q", expected)
+				t.Logf("ACTUAL   NORMALIZED-CONTENT: %q", actual)
+				t.Logf("OFFSET: %d", offset)
+				t.Logf("CONTENT: %q", test.Content)
+				break
+			}
+		}
+
+		if expected, actual := test.Content, content.String(); expected != actual {
+			t.Errorf("For test #%d, did not expect the original content to be changed but actually was.", testNumber)
+			t.Logf("EXPECTED CONTENT: %q", expected)
+			t.Logf("ACTUAL   CONTENT: %q", actual)
+			continue
+		}
+	}
+}
+
+func TestNormalizedContent_Content(t *testing.T) {
+
+	var normalized strfs.NormalizedContent = strfs.NormalizeContent(
+		strfs.CreateContent("\uFEFF"+"once"+"\r\n"+"twice"+"\r\n"),
+		strfs.NormalizeCRLFToLF | strfs.NormalizeStripBOM,
+	)
+
+	var fsys strfs.FS = strfs.FS{
+		"lines.txt": strfs.RegularFile{FileContent: normalized.Content(), FileMode: 0644},
+	}
+
+	const expected string = "once"+"\n"+"twice"+"\n"
+
+	for i := 0; i < 2; i++ {
+		data, err := fs.ReadFile(fsys, "lines.txt")
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		if actual := string(data); expected != actual {
+			t.Errorf("For read #%d, the actual file content was not what was expected.", i)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+		}
+	}
+
+	fileinfo, err := fs.Stat(fsys, "lines.txt")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if expected, actual := int64(len(expected)), fileinfo.Size(); expected != actual {
+		t.Errorf("The actual size was not what was expected: expected %d, actually %d", expected, actual)
+	}
+
+	var content strfs.Content = normalized.Content()
+
+	if line, err := content.Line(2); nil != err || "twice" != line {
+		t.Errorf("The actual line was not what was expected.")
+		t.Logf("ACTUAL: %q", line)
+		t.Logf("ERROR: %v", err)
+	}
+	if actual := content.String(); expected != actual {
+		t.Errorf("The actual string was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	var empty strfs.NormalizedContent
+	if strfs.EmptyContent() != empty.Content() {
+		t.Errorf("Expected the content of an empty strfs.NormalizedContent to be empty.")
+	}
+}
@@ -0,0 +1,184 @@
+package strfs
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// internalPieces is a read-seeker over a sequence of strings, as if they were one string.
+//
+// Because slicing a Go string does NOT copy it, internalPieces lets a (transformed) view of a string
+// be read without copying the original string.
+type internalPieces struct {
+	parts   []string
+	offsets []int64
+	size    int64
+	offset  int64
+}
+
+var (
+	// A trick to make sure internalPieces fits the io.ReadSeeker interface.
+	// This is a compile-time check.
+	_ io.ReadSeeker = &internalPieces{}
+
+	// A trick to make sure internalPieces fits the io.ReaderAt interface.
+	// This is a compile-time check.
+	_ io.ReaderAt = &internalPieces{}
+)
+
+// createPieces returns an internalPieces whose content is all the parts given to it, one after the other.
+//
+// Empty parts are dropped.
+func createPieces(parts ...string) internalPieces {
+	var pieces internalPieces
+
+	for _, part := range parts {
+		pieces.append(part)
+	}
+
+	return pieces
+}
+
+func (receiver *internalPieces) append(part string) {
+	if nil == receiver {
+		return
+	}
+	if "" == part {
+		return
+	}
+
+	receiver.parts = append(receiver.parts, part)
+	receiver.offsets = append(receiver.offsets, receiver.size)
+	receiver.size += int64(len(part))
+}
+
+// index returns the index of the part that contains the byte at 'offset'.
+func (receiver *internalPieces) index(offset int64) int {
+	if nil == receiver {
+		return 0
+	}
+
+	return sort.Search(len(receiver.offsets), func(i int) bool {
+		return offset < receiver.offsets[i]+int64(len(receiver.parts[i]))
+	})
+}
+
+func (receiver *internalPieces) Read(p []byte) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	n, err := receiver.ReadAt(p, receiver.offset)
+	receiver.offset += int64(n)
+	if io.EOF == err && 0 < n {
+		err = nil
+	}
+
+	return n, err
+}
+
+func (receiver *internalPieces) ReadAt(p []byte, offset int64) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+	if offset < 0 {
+		return 0, errNegativeOffset
+	}
+	if receiver.size <= offset {
+		return 0, io.EOF
+	}
+
+	var n int
+	for i := receiver.index(offset); n < len(p) && i < len(receiver.parts); i++ {
+		var part string = receiver.parts[i]
+		var start int64 = offset + int64(n) - receiver.offsets[i]
+
+		n += copy(p[n:], part[start:])
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (receiver *internalPieces) Seek(offset int64, whence int) (int64, error) {
+	if nil == receiver {
+		var nada int64
+		return nada, errNilReceiver
+	}
+
+	var absolute int64
+	switch whence {
+	case io.SeekStart:
+		absolute = offset
+	case io.SeekCurrent:
+		absolute = receiver.offset + offset
+	case io.SeekEnd:
+		absolute = receiver.size + offset
+	default:
+		var nada int64
+		return nada, errInvalidWhence
+	}
+
+	if absolute < 0 {
+		var nada int64
+		return nada, errNegativeOffset
+	}
+
+	receiver.offset = absolute
+	return absolute, nil
+}
+
+func (receiver *internalPieces) Size() int64 {
+	if nil == receiver {
+		return 0
+	}
+
+	return receiver.size
+}
+
+// slice returns an internalPieces whose content is the bytes from 'start' up to (but NOT including) 'end'.
+//
+// The parts are sliced (rather than copied), so the returned internalPieces shares its bytes with the receiver.
+func (receiver *internalPieces) slice(start int64, end int64) internalPieces {
+	var pieces internalPieces
+	if nil == receiver {
+		return pieces
+	}
+
+	for i := receiver.index(start); i < len(receiver.parts) && receiver.offsets[i] < end; i++ {
+		var part string = receiver.parts[i]
+		var offset int64 = receiver.offsets[i]
+
+		var from int64 = 0
+		if offset < start {
+			from = start - offset
+		}
+		var to int64 = int64(len(part))
+		if end < offset+to {
+			to = end - offset
+		}
+
+		pieces.append(part[from:to])
+	}
+
+	return pieces
+}
+
+// String flattens all the parts into a single string.
+func (receiver *internalPieces) String() string {
+	if nil == receiver {
+		return ""
+	}
+
+	switch len(receiver.parts) {
+	case 0:
+		return ""
+	case 1:
+		return receiver.parts[0]
+	default:
+		return strings.Join(receiver.parts, "")
+	}
+}
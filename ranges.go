@@ -0,0 +1,341 @@
+package strfs
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ByteRange is a range of bytes — 'Length' bytes, starting at byte 'Start'.
+type ByteRange struct {
+	Start  int64
+	Length int64
+}
+
+// ContentRange returns the value of a Content-Range header for the byte-range, where 'size' is the size of the whole content.
+//
+// For example:
+//
+//	var byterange strfs.ByteRange = strfs.ByteRange{Start:5, Length:4}
+//
+//	// byterange.ContentRange(26) == "bytes 5-8/26"
+func (receiver ByteRange) ContentRange(size int64) string {
+	return "bytes " + strconv.FormatInt(receiver.Start, 10) + "-" + strconv.FormatInt(receiver.Start+receiver.Length-1, 10) + "/" + strconv.FormatInt(size, 10)
+}
+
+// maxRanges is the most ranges that a Range-style spec can have.
+//
+// (Without a limit, a spec such as "bytes=0-,0-,0-,…" would make a response many times bigger than the content.)
+const maxRanges = 100
+
+// internalRangeSpec is a single range of a Range-style spec.
+//
+// For "500-999", 'first' is 500 and 'last' is 999.
+// For "9500-", 'first' is 9500 and 'last' is -1.
+// For the suffix range "-500", 'first' is -1 and 'last' is 500 (the number of bytes at the end).
+type internalRangeSpec struct {
+	first int64
+	last  int64
+}
+
+// Ranges is a parsed Range-style spec (such as "bytes=0-499,-500,9500-").
+//
+// See ParseRanges.
+type Ranges struct {
+	specs []internalRangeSpec
+}
+
+// ParseRanges parses a Range-style spec (the same as the value of an HTTP Range header) — for example:
+//
+//	"bytes=0-499"       // the first 500 bytes
+//	"bytes=9500-"       // all the bytes from byte 9500 on
+//	"bytes=-500"        // the last 500 bytes (a suffix range)
+//	"bytes=0-0,-1"      // the first byte, and the last byte
+//
+// ParseRanges only checks the syntax of the spec (and that it has no more than 100 ranges).
+// Use Resolve to check the ranges against the size of a strfs.Content, and turn them into strfs.ByteRange.
+func ParseRanges(spec string) (Ranges, error) {
+	const prefix = "bytes="
+
+	if !strings.HasPrefix(spec, prefix) {
+		return Ranges{}, errInvalidRange
+	}
+
+	var specs []internalRangeSpec
+	for _, part := range strings.Split(spec[len(prefix):], ",") {
+		part = strings.TrimSpace(part)
+		if "" == part {
+			continue
+		}
+
+		index := strings.IndexByte(part, '-')
+		if index < 0 {
+			return Ranges{}, errInvalidRange
+		}
+
+		var first string = strings.TrimSpace(part[:index])
+		var last string = strings.TrimSpace(part[index+1:])
+
+		var rangespec internalRangeSpec = internalRangeSpec{first:-1, last:-1}
+		switch {
+		case "" == first && "" == last:
+			return Ranges{}, errInvalidRange
+		case "" == first:
+			length, err := parseRangePosition(last)
+			if nil != err {
+				return Ranges{}, err
+			}
+			rangespec.last = length
+		default:
+			start, err := parseRangePosition(first)
+			if nil != err {
+				return Ranges{}, err
+			}
+			rangespec.first = start
+
+			if "" != last {
+				end, err := parseRangePosition(last)
+				if nil != err {
+					return Ranges{}, err
+				}
+				if end < start {
+					return Ranges{}, errInvalidRange
+				}
+				rangespec.last = end
+			}
+		}
+
+		specs = append(specs, rangespec)
+		if maxRanges < len(specs) {
+			return Ranges{}, errTooManyRanges
+		}
+	}
+
+	if len(specs) < 1 {
+		return Ranges{}, errInvalidRange
+	}
+
+	return Ranges{
+		specs:specs,
+	}, nil
+}
+
+// parseRangePosition parses a byte-position (or suffix-length) of a Range-style spec — which must be (only) ASCII digits.
+func parseRangePosition(s string) (int64, error) {
+	if "" == s {
+		return 0, errInvalidRange
+	}
+	for _, r := range s {
+		if r < '0' || '9' < r {
+			return 0, errInvalidRange
+		}
+	}
+
+	position, err := strconv.ParseInt(s, 10, 64)
+	if nil != err {
+		return 0, errInvalidRange
+	}
+
+	return position, nil
+}
+
+// Resolve checks the ranges against 'size' (for example, the Size of a strfs.Content), and returns them as strfs.ByteRange.
+//
+// A range that goes past the end is cut short at the end.
+// A suffix range that is longer than 'size' is the whole content.
+// A range that starts at or past the end (or a suffix range of zero bytes) cannot be satisfied, and is left out.
+//
+// If none of the ranges can be satisfied, Resolve returns an error.
+//
+// Overlapping ranges are NOT merged — the strfs.ByteRange are in the same order as in the spec.
+// But, if the ranges add up to more than 'size' (for example, "bytes=0-,0-"), then Resolve returns an error.
+// (The same as net/http does, so that a response cannot be made many times bigger than the content.)
+func (receiver Ranges) Resolve(size int64) ([]ByteRange, error) {
+	var byteranges []ByteRange
+
+	for _, rangespec := range receiver.specs {
+		var byterange ByteRange
+
+		switch {
+		case rangespec.first < 0:
+			var length int64 = rangespec.last
+			if size < length {
+				length = size
+			}
+			byterange = ByteRange{Start:size-length, Length:length}
+		default:
+			if size <= rangespec.first {
+				continue
+			}
+			var end int64 = size - 1
+			if 0 <= rangespec.last && rangespec.last < end {
+				end = rangespec.last
+			}
+			byterange = ByteRange{Start:rangespec.first, Length:end-rangespec.first+1}
+		}
+
+		if byterange.Length < 1 {
+			continue
+		}
+
+		byteranges = append(byteranges, byterange)
+	}
+
+	if len(byteranges) < 1 {
+		return nil, errRangeNotSatisfiable
+	}
+	if err := checkRangesTotal(byteranges, size); nil != err {
+		return nil, err
+	}
+
+	return byteranges, nil
+}
+
+// checkRangesTotal returns an error if there are more than maxRanges of 'byteranges', or if they add up to more than 'size'.
+func checkRangesTotal(byteranges []ByteRange, size int64) error {
+	if maxRanges < len(byteranges) {
+		return errTooManyRanges
+	}
+
+	var total int64
+	for _, byterange := range byteranges {
+		// (Checked this way, rather than with total+Length, so that the total cannot overflow.)
+		if size-total < byterange.Length {
+			return errRangesTooLong
+		}
+		total += byterange.Length
+	}
+
+	return nil
+}
+
+// String returns the spec that the strfs.Ranges was parsed from (normalized) — for example, "bytes=0-499,-500,9500-".
+//
+// String makes strfs.Ranges fit the fmt.Stringer interface.
+func (receiver Ranges) String() string {
+	var builder strings.Builder
+
+	builder.WriteString("bytes=")
+	for index, rangespec := range receiver.specs {
+		if 0 < index {
+			builder.WriteString(",")
+		}
+
+		if 0 <= rangespec.first {
+			builder.WriteString(strconv.FormatInt(rangespec.first, 10))
+		}
+		builder.WriteString("-")
+		if 0 <= rangespec.last {
+			builder.WriteString(strconv.FormatInt(rangespec.last, 10))
+		}
+	}
+
+	return builder.String()
+}
+
+// RangeReader returns an io.Reader for the bytes of 'content' in 'byterange'.
+//
+// The string that 'content' is wrapping is NOT copied (see CreateSectionContent).
+//
+// Example usage:
+//
+//	ranges, err := strfs.ParseRanges("bytes=-500")
+//
+//	// ...
+//
+//	byteranges, err := ranges.Resolve(content.Size())
+//
+//	// ...
+//
+//	reader, err := strfs.RangeReader(content, byteranges[0])
+func RangeReader(content Content, byterange ByteRange) (io.Reader, error) {
+	section, err := rangeContent(content, byterange)
+	if nil != err {
+		return nil, err
+	}
+
+	return &section, nil
+}
+
+// rangeContent returns the bytes of 'content' in 'byterange', as a strfs.Content.
+func rangeContent(content Content, byterange ByteRange) (Content, error) {
+	if EmptyContent() == content {
+		return EmptyContent(), errEmptyContent
+	}
+	if byterange.Start < 0 {
+		return EmptyContent(), errNegativeOffset
+	}
+	if byterange.Length < 0 {
+		return EmptyContent(), errNegativeLength
+	}
+	// (Checked this way, rather than with Start+Length, so that a huge length cannot overflow.)
+	if content.Size() < byterange.Start || content.Size()-byterange.Start < byterange.Length {
+		return EmptyContent(), errOffsetOutOfBounds
+	}
+
+	return CreateSectionContent(content, byterange.Start, byterange.Length)
+}
+
+// CreateMultipartByteranges returns a multipart/byteranges body (the same as an HTTP server would respond with for a Range request for more than one range),
+// with a part for each of 'byteranges' of 'content'.
+//
+// Each part has a Content-Range header, and (if 'contentType' is not "") a Content-Type header.
+// The parts are separated with 'boundary'.
+// (A random boundary can be made with, for example, multipart.NewWriter(io.Discard).Boundary().)
+//
+// The Content-Type of the whole body should be "multipart/byteranges; boundary=" followed by 'boundary'.
+//
+// There cannot be more than 100 of 'byteranges', and they cannot add up to more than the size of 'content' (see Resolve).
+//
+// Only the headers of the parts are new strings — the bytes of 'content' are NOT copied.
+// And because it returns a strfs.MultiContent, its Size can be used for a Content-Length header.
+//
+// Example usage:
+//
+//	var boundary string = multipart.NewWriter(io.Discard).Boundary()
+//
+//	body, err := strfs.CreateMultipartByteranges(content, byteranges, "text/plain; charset=utf-8", boundary)
+//
+//	// ...
+//
+//	responsewriter.Header().Set("Content-Type", "multipart/byteranges; boundary="+boundary)
+//	responsewriter.Header().Set("Content-Length", strconv.FormatInt(body.Size(), 10))
+//	responsewriter.WriteHeader(http.StatusPartialContent)
+//	io.Copy(responsewriter, &body)
+func CreateMultipartByteranges(content Content, byteranges []ByteRange, contentType string, boundary string) (MultiContent, error) {
+	if err := checkRangesTotal(byteranges, content.Size()); nil != err {
+		return MultiContent{}, err
+	}
+
+	var contents []Content
+
+	for index, byterange := range byteranges {
+		section, err := rangeContent(content, byterange)
+		if nil != err {
+			return MultiContent{}, err
+		}
+
+		var header strings.Builder
+		if 0 < index {
+			header.WriteString("\r\n")
+		}
+		header.WriteString("--")
+		header.WriteString(boundary)
+		header.WriteString("\r\n")
+		if "" != contentType {
+			header.WriteString("Content-Type: ")
+			header.WriteString(contentType)
+			header.WriteString("\r\n")
+		}
+		header.WriteString("Content-Range: ")
+		header.WriteString(byterange.ContentRange(content.Size()))
+		header.WriteString("\r\n")
+		header.WriteString("\r\n")
+
+		contents = append(contents, CreateContent(header.String()), section)
+	}
+
+	contents = append(contents, CreateContent("\r\n--"+boundary+"--\r\n"))
+
+	return CreateMultiContent(contents...), nil
+}
@@ -0,0 +1,267 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"io"
+	"math"
+	"mime/multipart"
+	"reflect"
+	"strings"
+
+	"testing"
+)
+
+func TestParseRanges(t *testing.T) {
+
+	tests := []struct{
+		Spec           string
+		Size           int64
+		ExpectedString string
+		Expected       []strfs.ByteRange
+	}{
+		{
+			Spec:           "bytes=0-4",
+			Size:           26,
+			ExpectedString: "bytes=0-4",
+			Expected:       []strfs.ByteRange{{Start:0, Length:5}},
+		},
+		{
+			Spec:           "bytes=20-",
+			Size:           26,
+			ExpectedString: "bytes=20-",
+			Expected:       []strfs.ByteRange{{Start:20, Length:6}},
+		},
+		{
+			Spec:           "bytes=-3",
+			Size:           26,
+			ExpectedString: "bytes=-3",
+			Expected:       []strfs.ByteRange{{Start:23, Length:3}},
+		},
+		{
+			Spec:           "bytes=-100",
+			Size:           26,
+			ExpectedString: "bytes=-100",
+			Expected:       []strfs.ByteRange{{Start:0, Length:26}},
+		},
+		{
+			Spec:           "bytes=20-100",
+			Size:           26,
+			ExpectedString: "bytes=20-100",
+			Expected:       []strfs.ByteRange{{Start:20, Length:6}},
+		},
+		{
+			Spec:           "bytes=0-0, -1 ,, 5-8",
+			Size:           26,
+			ExpectedString: "bytes=0-0,-1,5-8",
+			Expected:       []strfs.ByteRange{{Start:0, Length:1}, {Start:25, Length:1}, {Start:5, Length:4}},
+		},
+		{
+			Spec:           "bytes=30-40,2-3,-0",
+			Size:           26,
+			ExpectedString: "bytes=30-40,2-3,-0",
+			Expected:       []strfs.ByteRange{{Start:2, Length:2}},
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		ranges, err := strfs.ParseRanges(test.Spec)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+			t.Logf("SPEC: %q", test.Spec)
+			t.Logf("ERROR: (%T) %s", err, err)
+			continue
+		}
+
+		if expected, actual := test.ExpectedString, ranges.String(); expected != actual {
+			t.Errorf("For test #%d, the actual string was not what was expected.", testNumber)
+			t.Logf("SPEC: %q", test.Spec)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+
+		byteranges, err := ranges.Resolve(test.Size)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+			t.Logf("SPEC: %q", test.Spec)
+			t.Logf("ERROR: (%T) %s", err, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, byteranges; !reflect.DeepEqual(expected, actual) {
+			t.Errorf("For test #%d, the actual byte-ranges were not what was expected.", testNumber)
+			t.Logf("SPEC: %q", test.Spec)
+			t.Logf("EXPECTED: %#v", expected)
+			t.Logf("ACTUAL:   %#v", actual)
+			continue
+		}
+	}
+}
+
+func TestParseRanges_invalid(t *testing.T) {
+
+	tests := []struct{
+		Spec string
+	}{
+		{Spec: ""},
+		{Spec: "0-4"},
+		{Spec: "items=0-4"},
+		{Spec: "bytes="},
+		{Spec: "bytes=,"},
+		{Spec: "bytes=-"},
+		{Spec: "bytes=4"},
+		{Spec: "bytes=5-4"},
+		{Spec: "bytes=a-b"},
+		{Spec: "bytes=+1-2"},
+		{Spec: "bytes=0-4,x"},
+		{Spec: "bytes=99999999999999999999-"},
+		{Spec: "bytes=0-0" + strings.Repeat(",0-0", 100)},
+	}
+
+	for testNumber, test := range tests {
+
+		_, err := strfs.ParseRanges(test.Spec)
+		if nil == err {
+			t.Errorf("For test #%d, expected an error but did not actually get one.", testNumber)
+			t.Logf("SPEC: %q", test.Spec)
+			continue
+		}
+	}
+}
+
+func TestRanges_Resolve_notSatisfiable(t *testing.T) {
+
+	tests := []struct{
+		Spec string
+		Size int64
+	}{
+		{Spec: "bytes=26-",   Size: 26},
+		{Spec: "bytes=30-40", Size: 26},
+		{Spec: "bytes=-0",    Size: 26},
+		{Spec: "bytes=0-",    Size: 0},
+		{Spec: "bytes=-5",    Size: 0},
+		{Spec: "bytes=0-,0-", Size: 26},
+		{Spec: "bytes=0-19,10-", Size: 26},
+	}
+
+	for testNumber, test := range tests {
+
+		ranges, err := strfs.ParseRanges(test.Spec)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		if _, err := ranges.Resolve(test.Size); nil == err {
+			t.Errorf("For test #%d, expected an error but did not actually get one.", testNumber)
+			t.Logf("SPEC: %q", test.Spec)
+			t.Logf("SIZE: %d", test.Size)
+			continue
+		}
+	}
+}
+
+func TestRangeReader(t *testing.T) {
+
+	var content strfs.Content = strfs.CreateContent("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+	reader, err := strfs.RangeReader(content, strfs.ByteRange{Start:5, Length:4})
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	data, err := io.ReadAll(reader)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if expected, actual := "FGHI", string(data); expected != actual {
+		t.Errorf("The actual data was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	if _, err := strfs.RangeReader(content, strfs.ByteRange{Start:20, Length:7}); nil == err {
+		t.Errorf("Expected an error for a byte-range past the end, but did not actually get one.")
+	}
+	if _, err := strfs.RangeReader(content, strfs.ByteRange{Start:2, Length:math.MaxInt64}); nil == err {
+		t.Errorf("Expected an error for a byte-range whose end overflows, but did not actually get one.")
+	}
+	if _, err := strfs.RangeReader(content, strfs.ByteRange{Start:math.MaxInt64, Length:math.MaxInt64}); nil == err {
+		t.Errorf("Expected an error for a byte-range whose start is past the end, but did not actually get one.")
+	}
+	if _, err := strfs.RangeReader(strfs.EmptyContent(), strfs.ByteRange{Start:0, Length:0}); nil == err {
+		t.Errorf("Expected an error for an empty content, but did not actually get one.")
+	}
+}
+
+func TestCreateMultipartByteranges(t *testing.T) {
+
+	var content strfs.Content = strfs.CreateContent("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+	var byteranges []strfs.ByteRange = []strfs.ByteRange{
+		{Start:0,  Length:3},
+		{Start:23, Length:3},
+	}
+
+	body, err := strfs.CreateMultipartByteranges(content, byteranges, "text/plain", "BOUNDARY")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var expected string =
+		"--BOUNDARY"+"\r\n"+
+		"Content-Type: text/plain"+"\r\n"+
+		"Content-Range: bytes 0-2/26"+"\r\n"+
+		"\r\n"+
+		"ABC"+"\r\n"+
+		"--BOUNDARY"+"\r\n"+
+		"Content-Type: text/plain"+"\r\n"+
+		"Content-Range: bytes 23-25/26"+"\r\n"+
+		"\r\n"+
+		"XYZ"+"\r\n"+
+		"--BOUNDARY--"+"\r\n"
+
+	if actual := body.String(); expected != actual {
+		t.Errorf("The actual body was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+	if expected, actual := int64(len(expected)), body.Size(); expected != actual {
+		t.Errorf("The actual size was not what was expected: expected %d, actually %d", expected, actual)
+	}
+
+	var reader *multipart.Reader = multipart.NewReader(&body, "BOUNDARY")
+	for index, expected := range []struct{
+		ContentRange string
+		Data         string
+	}{
+		{ContentRange: "bytes 0-2/26",   Data: "ABC"},
+		{ContentRange: "bytes 23-25/26", Data: "XYZ"},
+	} {
+		part, err := reader.NextPart()
+		if nil != err {
+			t.Fatalf("For part #%d, did not expect an error but actually got one: (%T) %s", index, err, err)
+		}
+
+		if actual := part.Header.Get("Content-Range"); expected.ContentRange != actual {
+			t.Errorf("For part #%d, the actual Content-Range was not what was expected: expected %q, actually %q", index, expected.ContentRange, actual)
+		}
+
+		data, err := io.ReadAll(part)
+		if nil != err {
+			t.Fatalf("For part #%d, did not expect an error but actually got one: (%T) %s", index, err, err)
+		}
+		if actual := string(data); expected.Data != actual {
+			t.Errorf("For part #%d, the actual data was not what was expected: expected %q, actually %q", index, expected.Data, actual)
+		}
+	}
+	if _, err := reader.NextPart(); io.EOF != err {
+		t.Errorf("Expected io.EOF after the last part, but actually got: %v", err)
+	}
+	if _, err := strfs.CreateMultipartByteranges(content, []strfs.ByteRange{{Start:0, Length:26}, {Start:0, Length:26}}, "text/plain", "BOUNDARY"); nil == err {
+		t.Errorf("Expected an error for byte-ranges that add up to more than the whole content, but did not actually get one.")
+	}
+}
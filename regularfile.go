@@ -1,13 +1,22 @@
 package strfs
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/fs"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/reiver/go-erorr"
 )
 
 // RegularFile lets you turn a string into a [fs.File] that also implements [io.Seeker].
 //
+// FileMode holds the permission bits of the file (such as 0644).
+// Any type bits in FileMode are ignored — a strfs.RegularFile is always a regular file.
+//
 // Example usage:
 //
 //	var content strfs.Content = strfs.CreateContent("<!DOCTYPE html>"+"\n"+"<html><body>Hello world!</body></html>")
@@ -17,11 +26,11 @@ import (
 //		FileName:    "helloworld.html",
 //		FileModTime: time.Date(2022, 12, 12, 10, 30, 14, 2, time.UTC),
 //	}
-//
 type RegularFile struct {
 	FileContent Content
 	FileName string
 	FileModTime time.Time
+	FileMode fs.FileMode
 }
 
 var _ fs.File = &RegularFile{}
@@ -35,8 +44,27 @@ var (
 	// A trick to make sure strfs.RegularFile fits the fs.DirEntry interface.
 	// This is a compile-time check.
 	_ fs.DirEntry = &RegularFile{}
+
+	// A trick to make sure strfs.RegularFile fits the json.Marshaler interface.
+	// This is a compile-time check.
+	_ json.Marshaler = RegularFile{}
+
+	// A trick to make sure strfs.RegularFile fits the json.Unmarshaler interface.
+	// This is a compile-time check.
+	_ json.Unmarshaler = &RegularFile{}
 )
 
+// internalRegularFileJSON is what a strfs.RegularFile looks like in JSON.
+type internalRegularFileJSON struct {
+	Name    string    `json:"name"`
+	ModTime time.Time `json:"mtime"`
+	Mode    string    `json:"mode"`
+	Content Content   `json:"content"`
+}
+
+// regularFileTextPrefix is how the text encoding of a strfs.RegularFile starts.
+const regularFileTextPrefix = "strfs.RegularFile name="
+
 // Close will stop the Read method from working.
 //
 // Close can safely be called more than once.
@@ -64,6 +92,20 @@ func (receiver *RegularFile) Closed() bool {
         return receiver.FileContent.Closed()
 }
 
+// GobDecode makes *strfs.RegularFile fit the gob.GobDecoder interface.
+//
+// See GobEncode.
+func (receiver *RegularFile) GobDecode(data []byte) error {
+	return receiver.UnmarshalText(data)
+}
+
+// GobEncode makes strfs.RegularFile fit the gob.GobEncoder interface.
+//
+// The gob encoding of a strfs.RegularFile is the same as its text encoding (see MarshalText).
+func (receiver RegularFile) GobEncode() ([]byte, error) {
+	return receiver.MarshalText()
+}
+
 func (receiver *RegularFile) Info() (fs.FileInfo, error) {
 	if nil == receiver {
 		return nil, errNilReceiver
@@ -74,10 +116,10 @@ func (receiver *RegularFile) Info() (fs.FileInfo, error) {
 	}
 
 	return internalFileInfo{
-		sys:     receiver.FileContent.String(),
+		sys:     receiver.FileContent.sys(),
 		name:    receiver.Name(),
 		size:    receiver.FileContent.Size(),
-		mode:    receiver.Type(),
+		mode:    receiver.Type() | receiver.FileMode.Perm(),
 		modtime: receiver.FileModTime,
 	}, nil
 }
@@ -90,6 +132,54 @@ func (receiver *RegularFile) IsDir() bool {
 	return receiver.FileContent.IsDir()
 }
 
+// MarshalJSON makes strfs.RegularFile fit the json.Marshaler interface.
+//
+// A strfs.RegularFile is marshaled as a JSON object, such as:
+//
+//	{"name":"helloworld.txt","mtime":"2022-12-12T10:30:14.000000002Z","mode":"0644","content":"Hello world!"}
+//
+// (See strfs.Content's MarshalJSON for how the content is marshaled.)
+//
+// Whether it is closed, and where it has been read up to, are NOT kept.
+func (receiver RegularFile) MarshalJSON() ([]byte, error) {
+	return json.Marshal(internalRegularFileJSON{
+		Name:    receiver.FileName,
+		ModTime: receiver.FileModTime,
+		Mode:    formatFileMode(receiver.FileMode),
+		Content: receiver.FileContent,
+	})
+}
+
+// MarshalText makes strfs.RegularFile fit the encoding.TextMarshaler interface.
+//
+// The text encoding of a strfs.RegularFile is a header line, followed by a newline and the content. For example:
+//
+//	strfs.RegularFile name="helloworld.html" mode=0644 mtime=2022-12-12T10:30:14.000000002Z
+//	<!DOCTYPE html>
+//
+// If the FileContent is empty (see EmptyContent), then there is just the header line (without a newline).
+//
+// The content is included as is (and is NOT escaped), so the text encoding keeps the content exactly.
+//
+// Whether it is closed, and where it has been read up to, are NOT kept.
+func (receiver RegularFile) MarshalText() ([]byte, error) {
+	var builder strings.Builder
+
+	builder.WriteString(regularFileTextPrefix)
+	builder.WriteString(strconv.Quote(receiver.FileName))
+	builder.WriteString(" mode=")
+	builder.WriteString(formatFileMode(receiver.FileMode))
+	builder.WriteString(" mtime=")
+	builder.WriteString(receiver.FileModTime.Format(time.RFC3339Nano))
+
+	if EmptyContent() != receiver.FileContent {
+		builder.WriteString("\n")
+		builder.WriteString(receiver.FileContent.String())
+	}
+
+	return []byte(builder.String()), nil
+}
+
 func (receiver *RegularFile) Name() string {
 	if nil == receiver {
 		return ""
@@ -170,3 +260,107 @@ func (RegularFile) Type() fs.FileMode {
 	const modeRegularFile = 0
 	return modeRegularFile
 }
+
+// UnmarshalJSON makes *strfs.RegularFile fit the json.Unmarshaler interface.
+//
+// See MarshalJSON.
+func (receiver *RegularFile) UnmarshalJSON(data []byte) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	var decoded internalRegularFileJSON
+	if err := json.Unmarshal(data, &decoded); nil != err {
+		return err
+	}
+
+	mode, err := parseFileMode(decoded.Mode)
+	if nil != err {
+		return err
+	}
+
+	*receiver = RegularFile{
+		FileContent: decoded.Content,
+		FileName:    decoded.Name,
+		FileModTime: decoded.ModTime,
+		FileMode:    mode,
+	}
+	return nil
+}
+
+// UnmarshalText makes *strfs.RegularFile fit the encoding.TextUnmarshaler interface.
+//
+// See MarshalText.
+func (receiver *RegularFile) UnmarshalText(text []byte) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	var header string = string(text)
+	var content Content = EmptyContent()
+	if index := strings.IndexByte(header, '\n'); 0 <= index {
+		content = CreateContent(header[index+1:])
+		header = header[:index]
+	}
+
+	if !strings.HasPrefix(header, regularFileTextPrefix) {
+		return erorr.Errorf("strfs.RegularFile text must start with %q", regularFileTextPrefix)
+	}
+	header = header[len(regularFileTextPrefix):]
+
+	quoted, err := strconv.QuotedPrefix(header)
+	if nil != err {
+		return erorr.Errorf("strfs.RegularFile text has an invalid name: %w", err)
+	}
+	name, err := strconv.Unquote(quoted)
+	if nil != err {
+		return erorr.Errorf("strfs.RegularFile text has an invalid name: %w", err)
+	}
+	header = header[len(quoted):]
+
+	if !strings.HasPrefix(header, " mode=") {
+		return erorr.Errorf("strfs.RegularFile text is missing its mode")
+	}
+	header = header[len(" mode="):]
+
+	index := strings.IndexByte(header, ' ')
+	if index < 0 {
+		return erorr.Errorf("strfs.RegularFile text is missing its mtime")
+	}
+	mode, err := parseFileMode(header[:index])
+	if nil != err {
+		return err
+	}
+	header = header[index:]
+
+	if !strings.HasPrefix(header, " mtime=") {
+		return erorr.Errorf("strfs.RegularFile text is missing its mtime")
+	}
+	mtime, err := time.Parse(time.RFC3339Nano, header[len(" mtime="):])
+	if nil != err {
+		return erorr.Errorf("strfs.RegularFile text has an invalid mtime: %w", err)
+	}
+
+	*receiver = RegularFile{
+		FileContent: content,
+		FileName:    name,
+		FileModTime: mtime,
+		FileMode:    mode,
+	}
+	return nil
+}
+
+// formatFileMode writes the permission-bits of 'mode' in octal (such as "0644").
+func formatFileMode(mode fs.FileMode) string {
+	return fmt.Sprintf("%04o", uint32(mode.Perm()))
+}
+
+// parseFileMode parses permission-bits written in octal (such as "0644").
+func parseFileMode(value string) (fs.FileMode, error) {
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if nil != err || fs.FileMode(mode) != fs.FileMode(mode).Perm() {
+		return 0, erorr.Errorf("invalid file mode %q (expected octal permission-bits, such as \"0644\")", value)
+	}
+
+	return fs.FileMode(mode), nil
+}
@@ -75,7 +75,6 @@ func TestRegularFile(t *testing.T) {
 				t.Logf("REGULARFILE-MODTIME: %v", test.FileModTime)
 				t.Logf("REGULARFILE-CONTENT: %q", test.FileContent)
 				continue
-				continue
 			}
 		}
 		{
@@ -0,0 +1,450 @@
+package strfs
+
+import (
+	"io"
+	"strings"
+)
+
+// RopeContent is content that is made up of many fragments (strings) that are NOT joined into a single string.
+//
+// RopeContent is for very large (virtual) files, where putting everything into a single Go string
+// would (at least) double the peak memory usage.
+//
+// Internally, RopeContent is a balanced tree of fragments. ReadAt, Seek, Insert, and Delete are all O(log n)
+// (where n is the number of fragments), plus the cost of copying the bytes actually read.
+// The fragments themselves are NOT copied.
+//
+// String joins the fragments into a single string, but only when it is called (and it remembers the result until the next change).
+//
+// A RopeContent should NOT be copied after it is first used.
+//
+// Example usage:
+//
+//	var rope strfs.RopeContent = strfs.CreateRopeContent("<!DOCTYPE html>"+"\n", "<html><body>", "</body></html>")
+//
+//	err := rope.Insert(int64(len("<!DOCTYPE html>"+"\n"+"<html><body>")), "Hello world!")
+//
+//	// rope.String() == "<!DOCTYPE html>"+"\n"+"<html><body>Hello world!</body></html>"
+type RopeContent struct {
+	root *internalRopeNode
+	offset int64
+	seed uint64
+	flattened string
+	flattenedOK bool
+	initialized bool
+	closed bool
+}
+
+var (
+	// A trick to make sure strfs.RopeContent fits the io.ReadSeekCloser interface.
+	// This is a compile-time check.
+	_ io.ReadSeekCloser = &RopeContent{}
+
+	// A trick to make sure strfs.RopeContent fits the io.ReaderAt interface.
+	// This is a compile-time check.
+	_ io.ReaderAt = &RopeContent{}
+)
+
+// CreateRopeContent returns a strfs.RopeContent whose content is the fragments given to it, one after the other.
+func CreateRopeContent(fragments ...string) RopeContent {
+	var rope RopeContent = RopeContent{
+		initialized:true,
+	}
+
+	for _, fragment := range fragments {
+		rope.appendFragment(fragment)
+	}
+
+	return rope
+}
+
+// Append adds 'fragment' to the end of the content.
+//
+// Append does NOT change the current read offset.
+func (receiver *RopeContent) Append(fragment string) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if receiver.Closed() {
+		return errClosed
+	}
+
+	receiver.appendFragment(fragment)
+	return nil
+}
+
+func (receiver *RopeContent) appendFragment(fragment string) {
+	if "" == fragment {
+		return
+	}
+
+	receiver.root = ropeMerge(receiver.root, receiver.createNode(fragment))
+	receiver.flattenedOK = false
+}
+
+// Close will stop the Read method from working.
+//
+// Close can safely be called more than once.
+//
+// Close makes strfs.RopeContent fit the io.Closer interface.
+func (receiver *RopeContent) Close() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.closed = true
+	return nil
+}
+
+// Closed returns whether a strfs.RopeContent is closed or not.
+func (receiver *RopeContent) Closed() bool {
+	if nil == receiver {
+		return true
+	}
+	if !receiver.initialized {
+		return true
+	}
+
+	return receiver.closed
+}
+
+// Content returns a strfs.Content of the content, so that it can be the FileContent of a strfs.RegularFile
+// (and thus be put into a strfs.FS, or a strfs.Tree).
+//
+// The fragments are NOT joined into a single string, and are NOT copied.
+// (Unless the String method of the returned strfs.Content is called.)
+//
+// The returned strfs.Content is a snapshot — later changes to the strfs.RopeContent (such as with Insert, or Delete) do NOT change it.
+//
+// If the strfs.RopeContent is empty, then the returned strfs.Content is also empty (see EmptyContent).
+//
+// Example usage:
+//
+//	var rope strfs.RopeContent = strfs.CreateRopeContent(fragments...)
+//
+//	var regularfile strfs.RegularFile = strfs.RegularFile{
+//		FileContent: rope.Content(),
+//		FileName:    "huge.log",
+//		FileModTime: time.Now(),
+//	}
+func (receiver *RopeContent) Content() Content {
+	if nil == receiver {
+		return EmptyContent()
+	}
+	if !receiver.initialized {
+		return EmptyContent()
+	}
+
+	var pieces internalPieces
+	receiver.root.appendTo(&pieces)
+
+	return createPiecesContent(pieces)
+}
+
+func (receiver *RopeContent) createNode(fragment string) *internalRopeNode {
+	// splitmix64, to (deterministically) get a well-distributed priority for the node.
+	receiver.seed += 0x9E3779B97F4A7C15
+	var priority uint64 = receiver.seed
+	priority = (priority ^ (priority >> 30)) * 0xBF58476D1CE4E5B9
+	priority = (priority ^ (priority >> 27)) * 0x94D049BB133111EB
+	priority =  priority ^ (priority >> 31)
+
+	return &internalRopeNode{
+		fragment:fragment,
+		priority:priority,
+		size:int64(len(fragment)),
+	}
+}
+
+// Delete removes 'length' bytes from the content, starting at byte 'offset'.
+//
+// Delete does NOT change the current read offset.
+func (receiver *RopeContent) Delete(offset int64, length int64) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if receiver.Closed() {
+		return errClosed
+	}
+	if offset < 0 {
+		return errNegativeOffset
+	}
+	if length < 0 {
+		return errNegativeLength
+	}
+	if receiver.Size() < offset || receiver.Size() - offset < length {
+		return errOffsetOutOfBounds
+	}
+	if 0 == length {
+		return nil
+	}
+
+	left, right := receiver.split(receiver.root, offset)
+	_, right = receiver.split(right, length)
+	receiver.root = ropeMerge(left, right)
+	receiver.flattenedOK = false
+	return nil
+}
+
+// Insert adds 'fragment' to the content, so that it starts at byte 'offset'.
+//
+// Insert does NOT change the current read offset.
+func (receiver *RopeContent) Insert(offset int64, fragment string) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if receiver.Closed() {
+		return errClosed
+	}
+	if offset < 0 {
+		return errNegativeOffset
+	}
+	if receiver.Size() < offset {
+		return errOffsetOutOfBounds
+	}
+	if "" == fragment {
+		return nil
+	}
+
+	left, right := receiver.split(receiver.root, offset)
+	receiver.root = ropeMerge(ropeMerge(left, receiver.createNode(fragment)), right)
+	receiver.flattenedOK = false
+	return nil
+}
+
+// Read reads up to len(p) bytes into 'p'.
+// Read returns the number of bytes actually read, and any errors it encountered.
+//
+// Read makes strfs.RopeContent fit the io.Reader interface.
+func (receiver *RopeContent) Read(p []byte) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		return 0, errClosed
+	}
+
+	n, err := receiver.ReadAt(p, receiver.offset)
+	receiver.offset += int64(n)
+	if io.EOF == err && 0 < n {
+		err = nil
+	}
+
+	return n, err
+}
+
+// ReadAt reads len(p) bytes, starting at byte 'offset', into 'p'.
+//
+// ReadAt makes strfs.RopeContent fit the io.ReaderAt interface.
+func (receiver *RopeContent) ReadAt(p []byte, offset int64) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		return 0, errClosed
+	}
+	if offset < 0 {
+		return 0, errNegativeOffset
+	}
+	if receiver.Size() <= offset {
+		return 0, io.EOF
+	}
+
+	var n int = receiver.root.readAt(p, offset)
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Seek sets the offset for the next Read.
+//
+// Seek makes strfs.RopeContent fit the io.Seeker interface.
+func (receiver *RopeContent) Seek(offset int64, whence int) (int64, error) {
+	if nil == receiver {
+		var nada int64
+		return nada, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		var nada int64
+		return nada, errClosed
+	}
+
+	var absolute int64
+	switch whence {
+	case io.SeekStart:
+		absolute = offset
+	case io.SeekCurrent:
+		absolute = receiver.offset + offset
+	case io.SeekEnd:
+		absolute = receiver.Size() + offset
+	default:
+		var nada int64
+		return nada, errInvalidWhence
+	}
+
+	if absolute < 0 {
+		var nada int64
+		return nada, errNegativeOffset
+	}
+
+	receiver.offset = absolute
+	return absolute, nil
+}
+
+// Size returns the size of the content as the number of bytes.
+func (receiver *RopeContent) Size() int64 {
+	if nil == receiver {
+		return 0
+	}
+
+	return receiver.root.totalSize()
+}
+
+// split splits 'node' into two trees, where the first tree has the first 'offset' bytes, and the second tree has the rest.
+//
+// If 'offset' falls in the middle of a fragment, then the fragment is split in two (without copying it).
+func (receiver *RopeContent) split(node *internalRopeNode, offset int64) (*internalRopeNode, *internalRopeNode) {
+	if nil == node {
+		return nil, nil
+	}
+
+	var leftSize int64 = node.left.totalSize()
+	var fragmentSize int64 = int64(len(node.fragment))
+
+	switch {
+	case offset <= leftSize:
+		left, right := receiver.split(node.left, offset)
+		node.left = right
+		node.update()
+		return left, node
+	case leftSize + fragmentSize <= offset:
+		left, right := receiver.split(node.right, offset - leftSize - fragmentSize)
+		node.right = left
+		node.update()
+		return node, right
+	default:
+		var cut int64 = offset - leftSize
+
+		var after *internalRopeNode = receiver.createNode(node.fragment[cut:])
+		var right *internalRopeNode = node.right
+
+		node.fragment = node.fragment[:cut]
+		node.right = nil
+		node.update()
+
+		return node, ropeMerge(after, right)
+	}
+}
+
+// String returns the content as a single string.
+//
+// The fragments are only joined when String is called, and the result is remembered until the content changes.
+//
+// String makes *strfs.RopeContent fit the fmt.Stringer interface.
+func (receiver *RopeContent) String() string {
+	if nil == receiver {
+		return ""
+	}
+
+	if !receiver.flattenedOK {
+		var builder strings.Builder
+		builder.Grow(int(receiver.Size()))
+		receiver.root.writeTo(&builder)
+
+		receiver.flattened = builder.String()
+		receiver.flattenedOK = true
+	}
+
+	return receiver.flattened
+}
+
+type internalRopeNode struct {
+	fragment string
+	priority uint64
+	size int64
+	left *internalRopeNode
+	right *internalRopeNode
+}
+
+// ropeMerge joins two trees (where everything in 'left' comes before everything in 'right') into one tree.
+func ropeMerge(left *internalRopeNode, right *internalRopeNode) *internalRopeNode {
+	switch {
+	case nil == left:
+		return right
+	case nil == right:
+		return left
+	case right.priority < left.priority:
+		left.right = ropeMerge(left.right, right)
+		left.update()
+		return left
+	default:
+		right.left = ropeMerge(left, right.left)
+		right.update()
+		return right
+	}
+}
+
+// appendTo appends the fragments of the tree, in order, to 'pieces'.
+func (receiver *internalRopeNode) appendTo(pieces *internalPieces) {
+	if nil == receiver {
+		return
+	}
+
+	receiver.left.appendTo(pieces)
+	pieces.append(receiver.fragment)
+	receiver.right.appendTo(pieces)
+}
+
+func (receiver *internalRopeNode) readAt(p []byte, offset int64) int {
+	if nil == receiver || 0 == len(p) {
+		return 0
+	}
+
+	var n int
+
+	var leftSize int64 = receiver.left.totalSize()
+	if offset < leftSize {
+		n += receiver.left.readAt(p, offset)
+		offset = leftSize
+	}
+	offset -= leftSize
+
+	var fragmentSize int64 = int64(len(receiver.fragment))
+	if n < len(p) && offset < fragmentSize {
+		n += copy(p[n:], receiver.fragment[offset:])
+		offset = fragmentSize
+	}
+	offset -= fragmentSize
+
+	if n < len(p) {
+		n += receiver.right.readAt(p[n:], offset)
+	}
+
+	return n
+}
+
+func (receiver *internalRopeNode) totalSize() int64 {
+	if nil == receiver {
+		return 0
+	}
+
+	return receiver.size
+}
+
+func (receiver *internalRopeNode) update() {
+	receiver.size = receiver.left.totalSize() + int64(len(receiver.fragment)) + receiver.right.totalSize()
+}
+
+func (receiver *internalRopeNode) writeTo(builder *strings.Builder) {
+	if nil == receiver {
+		return
+	}
+
+	receiver.left.writeTo(builder)
+	builder.WriteString(receiver.fragment)
+	receiver.right.writeTo(builder)
+}
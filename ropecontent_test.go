@@ -0,0 +1,150 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"io"
+	"io/fs"
+	"math/rand"
+	"strings"
+
+	"testing"
+)
+
+func TestRopeContent(t *testing.T) {
+
+	var randomness *rand.Rand = rand.New(rand.NewSource(1974))
+
+	var rope strfs.RopeContent = strfs.CreateRopeContent("once", " twice", " thrice", " fource")
+	var expected string = "once twice thrice fource"
+
+	for iteration := 0; iteration < 2000; iteration++ {
+
+		var fragment string = strings.Repeat(string(rune('a' + randomness.Intn(26))), 1 + randomness.Intn(8))
+
+		switch randomness.Intn(3) {
+		case 0:
+			err := rope.Append(fragment)
+			if nil != err {
+				t.Fatalf("For iteration #%d, did not expect an error but actually got one: (%T) %s", iteration, err, err)
+			}
+			expected += fragment
+		case 1:
+			var offset int = randomness.Intn(len(expected)+1)
+
+			err := rope.Insert(int64(offset), fragment)
+			if nil != err {
+				t.Fatalf("For iteration #%d, did not expect an error but actually got one: (%T) %s", iteration, err, err)
+			}
+			expected = expected[:offset] + fragment + expected[offset:]
+		case 2:
+			var offset int = randomness.Intn(len(expected)+1)
+			var length int = randomness.Intn(len(expected)-offset+1)
+
+			err := rope.Delete(int64(offset), int64(length))
+			if nil != err {
+				t.Fatalf("For iteration #%d, did not expect an error but actually got one: (%T) %s", iteration, err, err)
+			}
+			expected = expected[:offset] + expected[offset+length:]
+		}
+
+		if expected, actual := int64(len(expected)), rope.Size(); expected != actual {
+			t.Errorf("For iteration #%d, the actual rope-content-size was not what was expected.", iteration)
+			t.Logf("EXPECTED ROPE-CONTENT-SIZE: %d", expected)
+			t.Logf("ACTUAL   ROPE-CONTENT-SIZE: %d", actual)
+			return
+		}
+
+		{
+			var offset int = randomness.Intn(len(expected)+1)
+
+			_, err := rope.Seek(int64(offset), io.SeekStart)
+			if nil != err {
+				t.Fatalf("For iteration #%d, did not expect an error but actually got one: (%T) %s", iteration, err, err)
+			}
+
+			actualBytes, err := io.ReadAll(&rope)
+			if nil != err {
+				t.Fatalf("For iteration #%d, did not expect an error but actually got one: (%T) %s", iteration, err, err)
+			}
+
+			if expected, actual := expected[offset:], string(actualBytes); expected != actual {
+				t.Errorf("For iteration #%d, the actual rope-content read after seeking was not what was expected.", iteration)
+				t.Logf("EXPECTED ROPE-CONTENT: %q", expected)
+				t.Logf("ACTUAL   ROPE-CONTENT: %q", actual)
+				t.Logf("OFFSET: %d", offset)
+				return
+			}
+		}
+
+		if 0 == iteration % 100 {
+			if expected, actual := expected, rope.String(); expected != actual {
+				t.Errorf("For iteration #%d, the actual rope-content was not what was expected.", iteration)
+				t.Logf("EXPECTED ROPE-CONTENT: %q", expected)
+				t.Logf("ACTUAL   ROPE-CONTENT: %q", actual)
+				return
+			}
+		}
+	}
+}
+
+func TestRopeContent_empty(t *testing.T) {
+
+	var rope strfs.RopeContent
+
+	if expected, actual := true, rope.Closed(); expected != actual {
+		t.Errorf("Expected rope-content to be closed but actually wasn't.")
+		return
+	}
+
+	err := rope.Append("once")
+	if nil == err {
+		t.Errorf("Expected an error but did not actually get one.")
+		return
+	}
+}
+
+func TestRopeContent_Content(t *testing.T) {
+
+	var rope strfs.RopeContent = strfs.CreateRopeContent("<!DOCTYPE html>"+"\n", "<html><body>", "</body></html>")
+	if err := rope.Insert(int64(len("<!DOCTYPE html>"+"\n"+"<html><body>")), "Hello world!"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	tree, err := strfs.CreateTree(strfs.FS{
+		"index.html": strfs.RegularFile{FileContent: rope.Content(), FileMode: 0644},
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	const expected string = "<!DOCTYPE html>"+"\n"+"<html><body>Hello world!</body></html>"
+
+	// Changing the rope afterwards does NOT change the file.
+	if err := rope.Delete(0, int64(len("<!DOCTYPE html>"+"\n"))); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	data, err := fs.ReadFile(tree, "index.html")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if actual := string(data); expected != actual {
+		t.Errorf("The actual file content was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	var content strfs.Content = rope.Content()
+	if expected, actual := "<html><body>Hello world!</body></html>", content.String(); expected != actual {
+		t.Errorf("The actual content after the delete was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	var empty strfs.RopeContent
+	if strfs.EmptyContent() != empty.Content() {
+		t.Errorf("Expected the content of an empty strfs.RopeContent to be empty.")
+	}
+}
@@ -0,0 +1,206 @@
+package strfs
+
+import (
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// SearchOptions says what to search for (and where) — see SearchFS, and the Search method of strfs.SearchIndex.
+type SearchOptions struct {
+	// Pattern is what to search for.
+	// It is literal text, unless Regexp is true (in which case it is a regular expression, with the syntax of Go's "regexp" package).
+	Pattern string
+
+	// Regexp is whether Pattern is a regular expression.
+	Regexp bool
+
+	// IgnoreCase is whether upper-case and lower-case letters match each other.
+	IgnoreCase bool
+
+	// Include (if not empty) is the globs (with the syntax of path.Match) of the files to search.
+	// A glob can match either the whole (slash-separated) path of a file (such as "css/*.css"), or just its last element (such as "*.css").
+	Include []string
+
+	// Exclude is the globs (with the same syntax as Include) of the files NOT to search.
+	Exclude []string
+
+	// Context is the number of lines before (and after) the line of each match to also return.
+	Context int
+
+	// MaxMatches (if not zero) is the most matches to return.
+	MaxMatches int
+}
+
+// SearchMatch is a single match found by a search.
+type SearchMatch struct {
+	// Name is the path of the file the match is in.
+	Name string
+
+	// Line is the line-number of the line the match starts on (where the first line is 1).
+	Line int
+
+	// Column is the column (in bytes) the match starts at (where the first column is 1).
+	Column int
+
+	// RuneColumn is the column (in runes) the match starts at (where the first column is 1).
+	RuneColumn int
+
+	// Text is the line the match starts on (without the "\n" at the end of it).
+	Text string
+
+	// Match is the text that matched.
+	Match string
+
+	// Before is the lines before Text (up to SearchOptions.Context of them).
+	Before []string
+
+	// After is the lines after Text (up to SearchOptions.Context of them).
+	After []string
+}
+
+// SearchFS searches all the (regular) files in 'fsys' (a strfs.FS, a strfs.Snapshot, a strfs.Tree, or any other fs.FS),
+// and returns every match, sorted by the path of the file, and then by where it is in the file.
+//
+// To search the same file-system many times, a strfs.SearchIndex can be faster (see CreateSearchIndex).
+//
+// Example usage:
+//
+//	matches, err := strfs.SearchFS(fsys, strfs.SearchOptions{
+//		Pattern: "TODO",
+//		Include: []string{"*.go"},
+//		Context: 2,
+//	})
+//
+//	// ...
+//
+//	for _, match := range matches {
+//		fmt.Printf("%s:%d:%d: %s\n", match.Name, match.Line, match.Column, match.Text)
+//	}
+func SearchFS(fsys fs.FS, options SearchOptions) ([]SearchMatch, error) {
+	pattern, err := options.compile()
+	if nil != err {
+		return nil, err
+	}
+
+	var matches []SearchMatch
+
+	err = exportWalk(fsys, func(name string, fileinfo fs.FileInfo, data []byte) error {
+		if !options.selects(name) {
+			return nil
+		}
+
+		matches = searchContent(matches, name, CreateContent(string(data)), pattern, options)
+		if options.full(matches) {
+			return errMaxMatches
+		}
+		return nil
+	})
+	if nil != err && errMaxMatches != err {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// compile returns the regular expression to search with.
+func (receiver SearchOptions) compile() (*regexp.Regexp, error) {
+	if "" == receiver.Pattern {
+		return nil, errEmptyPattern
+	}
+
+	for _, glob := range append(append([]string(nil), receiver.Include...), receiver.Exclude...) {
+		if _, err := path.Match(glob, ""); nil != err {
+			return nil, err
+		}
+	}
+
+	var expression string = receiver.Pattern
+	if !receiver.Regexp {
+		expression = regexp.QuoteMeta(expression)
+	}
+	if receiver.IgnoreCase {
+		expression = "(?i)" + expression
+	}
+
+	return regexp.Compile(expression)
+}
+
+// full returns whether there are already as many matches as the options allow.
+func (receiver SearchOptions) full(matches []SearchMatch) bool {
+	return 0 < receiver.MaxMatches && receiver.MaxMatches <= len(matches)
+}
+
+// selects returns whether the file named 'name' should be searched (see Include and Exclude).
+func (receiver SearchOptions) selects(name string) bool {
+	if 0 < len(receiver.Include) && !matchGlobs(receiver.Include, name) {
+		return false
+	}
+
+	return !matchGlobs(receiver.Exclude, name)
+}
+
+// matchGlobs returns whether any of the globs matches either the whole (slash-separated) path, or just its last element.
+func matchGlobs(globs []string, name string) bool {
+	for _, glob := range globs {
+		if matched, _ := path.Match(glob, name); matched {
+			return true
+		}
+		if matched, _ := path.Match(glob, path.Base(name)); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// searchContent appends the matches of 'pattern' in 'content' (the content of the file named 'name') to 'matches'.
+func searchContent(matches []SearchMatch, name string, content Content, pattern *regexp.Regexp, options SearchOptions) []SearchMatch {
+	var value string = content.String()
+
+	// A "\n" at the very end does not start another line (as far as context lines are concerned).
+	var lineCount int = content.LineCount()
+	if strings.HasSuffix(value, "\n") {
+		lineCount--
+	}
+
+	var limit int = -1
+	if 0 < options.MaxMatches {
+		limit = options.MaxMatches - len(matches)
+		if limit < 1 {
+			return matches
+		}
+	}
+
+	for _, location := range pattern.FindAllStringIndex(value, limit) {
+		linecol, err := content.OffsetToLineCol(int64(location[0]))
+		if nil != err {
+			continue
+		}
+
+		var match SearchMatch = SearchMatch{
+			Name:       name,
+			Line:       linecol.Line,
+			Column:     linecol.Column,
+			RuneColumn: linecol.RuneColumn,
+			Match:      value[location[0]:location[1]],
+		}
+		match.Text, _ = content.Line(linecol.Line)
+
+		for line := linecol.Line - options.Context; line < linecol.Line; line++ {
+			if text, err := content.Line(line); nil == err {
+				match.Before = append(match.Before, text)
+			}
+		}
+		for line := linecol.Line + 1; line <= linecol.Line + options.Context && line <= lineCount; line++ {
+			if text, err := content.Line(line); nil == err {
+				match.After = append(match.After, text)
+			}
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches
+}
@@ -0,0 +1,135 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"reflect"
+
+	"testing"
+)
+
+func searchFS() strfs.FS {
+	return strfs.FS{
+		"file1.txt":            strfs.RegularFile{FileContent: strfs.CreateContent("Hello world!"+"\n")},
+		"gmni/file3.gmni":      strfs.RegularFile{FileContent: strfs.CreateContent("once"+"\n"+"twice"+"\n"+"thrice"+"\n"+"fource"+"\n")},
+		"gmni/fngr/file4.fngr": strfs.RegularFile{FileContent: strfs.CreateContent("Hello world! 😈 hello WORLD!")},
+		"css/style.css":        strfs.RegularFile{FileContent: strfs.CreateContent("body{color:red}"+"\n"+"/* TODO: world */"+"\n")},
+	}
+}
+
+func TestSearchFS(t *testing.T) {
+
+	tests := []struct{
+		Options  strfs.SearchOptions
+		Expected []strfs.SearchMatch
+	}{
+		{
+			Options: strfs.SearchOptions{Pattern: "world"},
+			Expected: []strfs.SearchMatch{
+				{Name:"css/style.css",        Line:2, Column:10, RuneColumn:10, Text:"/* TODO: world */",            Match:"world"},
+				{Name:"file1.txt",            Line:1, Column:7,  RuneColumn:7,  Text:"Hello world!",                 Match:"world"},
+				{Name:"gmni/fngr/file4.fngr", Line:1, Column:7,  RuneColumn:7,  Text:"Hello world! 😈 hello WORLD!", Match:"world"},
+			},
+		},
+		{
+			Options: strfs.SearchOptions{Pattern: "WORLD", IgnoreCase: true, Include: []string{"gmni/*/*"}},
+			Expected: []strfs.SearchMatch{
+				{Name:"gmni/fngr/file4.fngr", Line:1, Column:7,  RuneColumn:7,  Text:"Hello world! 😈 hello WORLD!", Match:"world"},
+				{Name:"gmni/fngr/file4.fngr", Line:1, Column:25, RuneColumn:22, Text:"Hello world! 😈 hello WORLD!", Match:"WORLD"},
+			},
+		},
+		{
+			Options: strfs.SearchOptions{Pattern: "world", Exclude: []string{"*.css", "*.fngr"}},
+			Expected: []strfs.SearchMatch{
+				{Name:"file1.txt", Line:1, Column:7, RuneColumn:7, Text:"Hello world!", Match:"world"},
+			},
+		},
+		{
+			Options: strfs.SearchOptions{Pattern: `t(wi|hri)ce`, Regexp: true, Context: 1},
+			Expected: []strfs.SearchMatch{
+				{Name:"gmni/file3.gmni", Line:2, Column:1, RuneColumn:1, Text:"twice",  Match:"twice",  Before:[]string{"once"},  After:[]string{"thrice"}},
+				{Name:"gmni/file3.gmni", Line:3, Column:1, RuneColumn:1, Text:"thrice", Match:"thrice", Before:[]string{"twice"}, After:[]string{"fource"}},
+			},
+		},
+		{
+			Options: strfs.SearchOptions{Pattern: "fource", Context: 2},
+			Expected: []strfs.SearchMatch{
+				{Name:"gmni/file3.gmni", Line:4, Column:1, RuneColumn:1, Text:"fource", Match:"fource", Before:[]string{"twice", "thrice"}},
+			},
+		},
+		{
+			Options: strfs.SearchOptions{Pattern: "o", MaxMatches: 2},
+			Expected: []strfs.SearchMatch{
+				{Name:"css/style.css", Line:1, Column:2, RuneColumn:2, Text:"body{color:red}", Match:"o"},
+				{Name:"css/style.css", Line:1, Column:7, RuneColumn:7, Text:"body{color:red}", Match:"o"},
+			},
+		},
+		{
+			Options: strfs.SearchOptions{Pattern: "(.*)", Regexp: false},
+			Expected: nil,
+		},
+		{
+			Options: strfs.SearchOptions{Pattern: `(?i)hello\s+world`, Regexp: true, Include: []string{"*.fngr"}},
+			Expected: []strfs.SearchMatch{
+				{Name:"gmni/fngr/file4.fngr", Line:1, Column:1,  RuneColumn:1,  Text:"Hello world! 😈 hello WORLD!", Match:"Hello world"},
+				{Name:"gmni/fngr/file4.fngr", Line:1, Column:19, RuneColumn:16, Text:"Hello world! 😈 hello WORLD!", Match:"hello WORLD"},
+			},
+		},
+	}
+
+	index, err := strfs.CreateSearchIndex(searchFS())
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	for testNumber, test := range tests {
+
+		actual, err := strfs.SearchFS(searchFS(), test.Options)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		if expected := test.Expected; !reflect.DeepEqual(expected, actual) {
+			t.Errorf("For test #%d, the actual matches were not what was expected.", testNumber)
+			t.Logf("OPTIONS: %#v", test.Options)
+			t.Logf("EXPECTED: %#v", expected)
+			t.Logf("ACTUAL:   %#v", actual)
+			continue
+		}
+
+		indexed, err := index.Search(test.Options)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, indexed; !reflect.DeepEqual(expected, actual) {
+			t.Errorf("For test #%d, the actual matches from the index were not what was expected.", testNumber)
+			t.Logf("OPTIONS: %#v", test.Options)
+			t.Logf("EXPECTED: %#v", expected)
+			t.Logf("ACTUAL:   %#v", actual)
+			continue
+		}
+	}
+}
+
+func TestSearchFS_error(t *testing.T) {
+
+	tests := []struct{
+		Options strfs.SearchOptions
+	}{
+		{Options: strfs.SearchOptions{}},
+		{Options: strfs.SearchOptions{Pattern: "(", Regexp: true}},
+		{Options: strfs.SearchOptions{Pattern: "x", Include: []string{"["}}},
+	}
+
+	for testNumber, test := range tests {
+
+		if _, err := strfs.SearchFS(searchFS(), test.Options); nil == err {
+			t.Errorf("For test #%d, expected an error but did not actually get one.", testNumber)
+			t.Logf("OPTIONS: %#v", test.Options)
+			continue
+		}
+	}
+}
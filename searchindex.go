@@ -0,0 +1,222 @@
+package strfs
+
+import (
+	"io/fs"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"unicode/utf8"
+)
+
+// SearchIndex is an in-memory trigram index of the (regular) files of a file-system, for searching them many times
+// (faster than SearchFS, which reads every file each time).
+//
+// For each trigram (i.e., each sequence of 3 bytes) the index knows which files have it.
+// A search only looks in the files that have all the trigrams that any match would have to have
+// (which, for a regular expression, are the trigrams of the literal text that it must match).
+// If a pattern does not have any such trigrams (for example, it is shorter than 3 bytes), then every file is searched.
+//
+// The contents of the files are kept in the index — changes to the file-system after the index was created are NOT seen by it.
+//
+// A strfs.SearchIndex is safe to use from more than one goroutine at the same time.
+//
+// Example usage:
+//
+//	index, err := strfs.CreateSearchIndex(fsys)
+//
+//	// ...
+//
+//	matches, err := index.Search(strfs.SearchOptions{
+//		Pattern: `func \w+Handler`,
+//		Regexp:  true,
+//	})
+type SearchIndex struct {
+	names    []string
+	contents []Content
+	trigrams map[uint32][]int
+}
+
+// CreateSearchIndex reads all the (regular) files in 'fsys', and returns a strfs.SearchIndex of them.
+func CreateSearchIndex(fsys fs.FS) (*SearchIndex, error) {
+	var index SearchIndex = SearchIndex{
+		trigrams:map[uint32][]int{},
+	}
+
+	err := exportWalk(fsys, func(name string, fileinfo fs.FileInfo, data []byte) error {
+		var number int = len(index.names)
+
+		index.names = append(index.names, name)
+		index.contents = append(index.contents, CreateContent(string(data)))
+
+		var seen map[uint32]struct{} = map[uint32]struct{}{}
+		for offset := 0; offset+3 <= len(data); offset++ {
+			var trigram uint32 = makeTrigram(data[offset], data[offset+1], data[offset+2])
+			if _, found := seen[trigram]; found {
+				continue
+			}
+			seen[trigram] = struct{}{}
+
+			index.trigrams[trigram] = append(index.trigrams[trigram], number)
+		}
+
+		return nil
+	})
+	if nil != err {
+		return nil, err
+	}
+
+	return &index, nil
+}
+
+// Search returns every match in the files of the index, sorted by the path of the file, and then by where it is in the file.
+//
+// The matches are the same as SearchFS would return for the file-system the index was created from.
+func (receiver *SearchIndex) Search(options SearchOptions) ([]SearchMatch, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+
+	pattern, err := options.compile()
+	if nil != err {
+		return nil, err
+	}
+
+	var matches []SearchMatch
+	for _, number := range receiver.candidates(pattern) {
+		if options.full(matches) {
+			break
+		}
+
+		var name string = receiver.names[number]
+		if !options.selects(name) {
+			continue
+		}
+
+		matches = searchContent(matches, name, receiver.contents[number], pattern, options)
+	}
+
+	return matches, nil
+}
+
+// candidates returns (the numbers of) the files that could have a match of 'pattern' — i.e., that have all of its required trigrams.
+func (receiver *SearchIndex) candidates(pattern *regexp.Regexp) []int {
+	var candidates []int
+	var filtered bool
+
+	for _, trigram := range requiredTrigrams(pattern) {
+		var files []int = receiver.trigrams[trigram]
+
+		if !filtered {
+			candidates = files
+			filtered = true
+		} else {
+			candidates = intersectSorted(candidates, files)
+		}
+
+		if len(candidates) < 1 {
+			return nil
+		}
+	}
+
+	if !filtered {
+		candidates = make([]int, len(receiver.names))
+		for number := range candidates {
+			candidates[number] = number
+		}
+	}
+
+	return candidates
+}
+
+// intersectSorted returns the numbers that are in both 'a' and 'b' (which must both be sorted).
+func intersectSorted(a []int, b []int) []int {
+	var result []int
+
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0] < b[0]:
+			a = a[1:]
+		case b[0] < a[0]:
+			b = b[1:]
+		default:
+			result = append(result, a[0])
+			a = a[1:]
+			b = b[1:]
+		}
+	}
+
+	return result
+}
+
+// makeTrigram packs 3 bytes into a trigram.
+func makeTrigram(b0 byte, b1 byte, b2 byte) uint32 {
+	return uint32(b0)<<16 | uint32(b1)<<8 | uint32(b2)
+}
+
+// requiredTrigrams returns trigrams that every match of 'pattern' must have.
+//
+// (This does not have to return all of them — returning fewer just means more files get searched.)
+func requiredTrigrams(pattern *regexp.Regexp) []uint32 {
+	expression, err := syntax.Parse(pattern.String(), syntax.Perl)
+	if nil != err {
+		return nil
+	}
+
+	var trigrams []uint32
+	var seen map[uint32]struct{} = map[uint32]struct{}{}
+	for _, literal := range requiredLiterals(expression.Simplify()) {
+		for offset := 0; offset+3 <= len(literal); offset++ {
+			var trigram uint32 = makeTrigram(literal[offset], literal[offset+1], literal[offset+2])
+			if _, found := seen[trigram]; found {
+				continue
+			}
+			seen[trigram] = struct{}{}
+
+			trigrams = append(trigrams, trigram)
+		}
+	}
+
+	sort.Slice(trigrams, func(i, j int) bool { return trigrams[i] < trigrams[j] })
+
+	return trigrams
+}
+
+// requiredLiterals returns (case-sensitive) literal text that every match of 'expression' must have.
+//
+// Literal text that is matched case-insensitively (i.e., with "(?i)") is left out,
+// because the index does not know what other case the text could be in the files.
+func requiredLiterals(expression *syntax.Regexp) []string {
+	switch expression.Op {
+	case syntax.OpLiteral:
+		if 0 != expression.Flags&syntax.FoldCase {
+			return nil
+		}
+
+		var buffer []byte
+		for _, r := range expression.Rune {
+			var encoded [utf8.UTFMax]byte
+			var size int = utf8.EncodeRune(encoded[:], r)
+			buffer = append(buffer, encoded[:size]...)
+		}
+		return []string{string(buffer)}
+
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiterals(expression.Sub[0])
+
+	case syntax.OpRepeat:
+		if expression.Min < 1 {
+			return nil
+		}
+		return requiredLiterals(expression.Sub[0])
+
+	case syntax.OpConcat:
+		var literals []string
+		for _, sub := range expression.Sub {
+			literals = append(literals, requiredLiterals(sub)...)
+		}
+		return literals
+
+	default:
+		return nil
+	}
+}
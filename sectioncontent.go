@@ -0,0 +1,56 @@
+package strfs
+
+// CreateSectionContent returns a strfs.Content that is a window into the strfs.Content given to it.
+// The window starts at byte 'offset' and is (up to) 'length' bytes long.
+//
+// (This is similar to what io.NewSectionReader does for an io.ReaderAt.)
+//
+// Because slicing a Go string does NOT copy it, the returned strfs.Content shares its bytes with 'content'.
+// (That is so even if 'content' is a view of something that is NOT a single string — such as the Content of a strfs.MultiContent —
+// in which case it is NOT joined into a single string.)
+//
+// If the window goes past the end of 'content', then the window is cut short at the end of 'content'.
+//
+// Because it returns a strfs.Content, the section can be used as the FileContent of a strfs.RegularFile.
+//
+// Example usage:
+//
+//	var content strfs.Content = strfs.CreateContent("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+//
+//	section, err := strfs.CreateSectionContent(content, 5, 4)
+//
+//	// section.String() == "FGHI"
+//
+//	var regularfile strfs.RegularFile = strfs.RegularFile{
+//		FileContent: section,
+//		FileName:    "fghi.txt",
+//		FileModTime: time.Now(),
+//	}
+func CreateSectionContent(content Content, offset int64, length int64) (Content, error) {
+	if EmptyContent() == content {
+		return EmptyContent(), errEmptyContent
+	}
+	if offset < 0 {
+		return EmptyContent(), errNegativeOffset
+	}
+	if length < 0 {
+		return EmptyContent(), errNegativeLength
+	}
+
+	var size int64 = content.Size()
+
+	if size < offset {
+		return EmptyContent(), errOffsetOutOfBounds
+	}
+
+	var end int64 = offset + length
+	if size < end || end < offset {
+		end = size
+	}
+
+	if nil != content.source {
+		return createPiecesContent(content.source.pieces.slice(offset, end)), nil
+	}
+
+	return CreateContent(content.value[offset:end]), nil
+}
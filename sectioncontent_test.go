@@ -0,0 +1,232 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"io"
+	"runtime"
+	"strings"
+
+	"testing"
+)
+
+func TestCreateSectionContent(t *testing.T) {
+
+	tests := []struct{
+		Content  string
+		Offset   int64
+		Length   int64
+		Expected string
+	}{
+		{
+			Content:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+			Offset:   0,
+			Length:   0,
+			Expected: "",
+		},
+		{
+			Content:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+			Offset:   0,
+			Length:   5,
+			Expected: "ABCDE",
+		},
+		{
+			Content:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+			Offset:   5,
+			Length:   4,
+			Expected: "FGHI",
+		},
+		{
+			Content:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+			Offset:   20,
+			Length:   100,
+			Expected: "UVWXYZ",
+		},
+		{
+			Content:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+			Offset:   26,
+			Length:   1,
+			Expected: "",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		section, err := strfs.CreateSectionContent(strfs.CreateContent(test.Content), test.Offset, test.Length)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+			t.Logf("ERROR: (%T) %s", err, err)
+			continue
+		}
+
+		if expected, actual := int64(len(test.Expected)), section.Size(); expected != actual {
+			t.Errorf("For test #%d, the actual section-content-size was not what was expected.", testNumber)
+			t.Logf("EXPECTED SECTION-CONTENT-SIZE: %d", expected)
+			t.Logf("ACTUAL   SECTION-CONTENT-SIZE: %d", actual)
+			continue
+		}
+
+		actualBytes, err := io.ReadAll(&section)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+			t.Logf("ERROR: (%T) %s", err, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, string(actualBytes); expected != actual {
+			t.Errorf("For test #%d, the actual section-content was not what was expected.", testNumber)
+			t.Logf("EXPECTED SECTION-CONTENT: %q", expected)
+			t.Logf("ACTUAL   SECTION-CONTENT: %q", actual)
+			continue
+		}
+	}
+}
+
+func TestCreateSectionContent_multiContent(t *testing.T) {
+
+	var multicontent strfs.MultiContent = strfs.CreateMultiContent(
+		strfs.CreateContent("ABCDEFGHIJ"),
+		strfs.CreateContent("KLMNOPQRST"),
+		strfs.CreateContent("UVWXYZ"),
+	)
+
+	tests := []struct{
+		Offset   int64
+		Length   int64
+		Expected string
+	}{
+		{
+			Offset:   0,
+			Length:   0,
+			Expected: "",
+		},
+		{
+			Offset:   2,
+			Length:   3,
+			Expected: "CDE",
+		},
+		{
+			Offset:   8,
+			Length:   4,
+			Expected: "IJKL",
+		},
+		{
+			Offset:   10,
+			Length:   10,
+			Expected: "KLMNOPQRST",
+		},
+		{
+			Offset:   5,
+			Length:   100,
+			Expected: "FGHIJKLMNOPQRSTUVWXYZ",
+		},
+		{
+			Offset:   26,
+			Length:   1,
+			Expected: "",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		section, err := strfs.CreateSectionContent(multicontent.Content(), test.Offset, test.Length)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+			t.Logf("ERROR: (%T) %s", err, err)
+			continue
+		}
+
+		if expected, actual := int64(len(test.Expected)), section.Size(); expected != actual {
+			t.Errorf("For test #%d, the actual section-content-size was not what was expected.", testNumber)
+			t.Logf("EXPECTED SECTION-CONTENT-SIZE: %d", expected)
+			t.Logf("ACTUAL   SECTION-CONTENT-SIZE: %d", actual)
+			continue
+		}
+
+		actualBytes, err := io.ReadAll(&section)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+			t.Logf("ERROR: (%T) %s", err, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, string(actualBytes); expected != actual {
+			t.Errorf("For test #%d, the actual section-content was not what was expected.", testNumber)
+			t.Logf("EXPECTED SECTION-CONTENT: %q", expected)
+			t.Logf("ACTUAL   SECTION-CONTENT: %q", actual)
+			continue
+		}
+	}
+}
+
+func TestCreateSectionContent_multiContentNotJoined(t *testing.T) {
+
+	const partSize = 8 << 20
+
+	var multicontent strfs.MultiContent = strfs.CreateMultiContent(
+		strfs.CreateContent(strings.Repeat("A", partSize)),
+		strfs.CreateContent(strings.Repeat("B", partSize)),
+	)
+	var content strfs.Content = multicontent.Content()
+
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	section, err := strfs.CreateSectionContent(content, partSize-2, 4)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	// If the strfs.MultiContent had been joined into a single string, then (at least) 16 MiB would have been allocated.
+	if allocated := after.TotalAlloc - before.TotalAlloc; partSize <= allocated {
+		t.Errorf("Expected the strfs.MultiContent to NOT be joined into a single string, but %d bytes were allocated.", allocated)
+	}
+
+	if expected, actual := "AABB", section.String(); expected != actual {
+		t.Errorf("The actual section-content was not what was expected.")
+		t.Logf("EXPECTED SECTION-CONTENT: %q", expected)
+		t.Logf("ACTUAL   SECTION-CONTENT: %q", actual)
+	}
+}
+
+func TestCreateSectionContent_error(t *testing.T) {
+
+	tests := []struct{
+		Content strfs.Content
+		Offset  int64
+		Length  int64
+	}{
+		{
+			Content: strfs.EmptyContent(),
+			Offset:  0,
+			Length:  0,
+		},
+		{
+			Content: strfs.CreateContent("ABCDEFGHIJKLMNOPQRSTUVWXYZ"),
+			Offset:  -1,
+			Length:  1,
+		},
+		{
+			Content: strfs.CreateContent("ABCDEFGHIJKLMNOPQRSTUVWXYZ"),
+			Offset:  1,
+			Length:  -1,
+		},
+		{
+			Content: strfs.CreateContent("ABCDEFGHIJKLMNOPQRSTUVWXYZ"),
+			Offset:  27,
+			Length:  1,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		_, err := strfs.CreateSectionContent(test.Content, test.Offset, test.Length)
+		if nil == err {
+			t.Errorf("For test #%d, expected an error but did not actually get one.", testNumber)
+			continue
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package strfs
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// Snapshot is an immutable version of a strfs.Tree, at the time it was taken (see the Snapshot method of strfs.Tree).
+//
+// Changes made to the strfs.Tree after the snapshot was taken do NOT change the snapshot.
+// A strfs.Snapshot is also a [fs.FS], so it can be read like any other file-system.
+//
+// Taking a snapshot is cheap — the snapshot and the strfs.Tree share memory (rather than the files being copied),
+// and only the parts of the tree that later change are copied (when they change).
+//
+// Fork returns a new strfs.Tree that starts off as the snapshot, and can be changed without changing the snapshot
+// (or the strfs.Tree the snapshot was taken from). Fork is also cheap.
+//
+// The zero value of a strfs.Snapshot is an empty snapshot.
+// A strfs.Snapshot is safe to use from more than one goroutine at the same time.
+//
+// Example usage:
+//
+//	var before strfs.Snapshot = tree.Snapshot()
+//
+//	err := tree.WriteFile("index.html", "<!DOCTYPE html>", 0644)
+//
+//	// ...
+//
+//	// roll back
+//	tree = before.Fork()
+type Snapshot struct {
+	root *internalTreapNode
+}
+
+// A trick to make sure strfs.Snapshot fits the fs.FS interface.
+// This is a compile-time check.
+var _ fs.FS = Snapshot{}
+
+// Fork returns a new (mutable) strfs.Tree whose files are the files of the snapshot.
+func (receiver Snapshot) Fork() *Tree {
+	return &Tree{
+		root:receiver.root,
+	}
+}
+
+// Open opens the file (or directory) at 'name'.
+//
+// Open makes strfs.Snapshot fit the fs.FS interface.
+func (receiver Snapshot) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrInvalid}
+	}
+
+	if regularfile, found := treapGet(receiver.root, name); found {
+		return openRegularFile(name, regularfile), nil
+	}
+
+	entries, found := receiver.readDir(name)
+	if !found {
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrNotExist}
+	}
+
+	return &internalDirectory{
+		name:name,
+		entries:entries,
+	}, nil
+}
+
+// readDir returns the entries of the directory at 'name', sorted by name.
+// It also returns whether the directory exists.
+//
+// Because the files are sorted by path, only the files in the directory are visited.
+func (receiver Snapshot) readDir(name string) ([]fs.DirEntry, bool) {
+	var prefix string
+	if "." != name {
+		prefix = name + "/"
+	}
+
+	var entries []fs.DirEntry
+	var found bool = "." == name
+	var lastdirname string
+
+	treapAscend(receiver.root, prefix, func(filepath string, regularfile RegularFile) bool {
+		if !strings.HasPrefix(filepath, prefix) {
+			return false
+		}
+		found = true
+
+		var rest string = filepath[len(prefix):]
+		if index := strings.IndexByte(rest, '/'); 0 <= index {
+			// All the files in a sub-directory come one after the other, so only the last one needs to be remembered.
+			var dirname string = rest[:index]
+			if dirname != lastdirname {
+				lastdirname = dirname
+				entries = append(entries, internalDirEntry{name:dirname})
+			}
+			return true
+		}
+
+		entries = append(entries, openRegularFile(filepath, regularfile))
+		return true
+	})
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return entries, found
+}
@@ -0,0 +1,181 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfstest"
+
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"sort"
+
+	"testing"
+)
+
+func TestTree_Snapshot(t *testing.T) {
+
+	var tree strfs.Tree
+
+	if err := tree.WriteFile("index.html", "version 1", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := tree.WriteFile("css/style.css", "body{color:red}", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var before strfs.Snapshot = tree.Snapshot()
+
+	if err := tree.WriteFile("index.html", "version 2", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := tree.Remove("css/style.css"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := tree.WriteFile("about.html", "about", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if err := strfstest.TestFS(before, "index.html", "css/style.css"); nil != err {
+		t.Errorf("Did not expect the snapshot to be changed by later writes to the tree.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+	expectFileContent(t, before, "index.html", "version 1")
+
+	if err := strfstest.TestFS(&tree, "index.html", "about.html"); nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+	expectFileContent(t, &tree, "index.html", "version 2")
+
+	var fork *strfs.Tree = before.Fork()
+	if err := fork.WriteFile("index.html", "version 3", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	expectFileContent(t, fork, "index.html", "version 3")
+	expectFileContent(t, fork, "css/style.css", "body{color:red}")
+	expectFileContent(t, before, "index.html", "version 1")
+	expectFileContent(t, &tree, "index.html", "version 2")
+}
+
+func TestSnapshot_empty(t *testing.T) {
+
+	var snapshot strfs.Snapshot
+
+	if err := strfstest.TestFS(snapshot); nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+
+	var fork *strfs.Tree = snapshot.Fork()
+	if err := fork.WriteFile("file.txt", "once", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if err := strfstest.TestFS(snapshot); nil != err {
+		t.Errorf("Did not expect the snapshot to be changed by writes to its fork.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+}
+
+// TestTree_Snapshot_random makes lots of random changes to a strfs.Tree, taking a snapshot after each one,
+// and then checks that every snapshot still has the files it had when it was taken.
+func TestTree_Snapshot_random(t *testing.T) {
+
+	var randomness *rand.Rand = rand.New(rand.NewSource(3))
+
+	var tree strfs.Tree
+	var model map[string]string = map[string]string{}
+
+	var snapshots []strfs.Snapshot
+	var models []map[string]string
+
+	for i := 0; i < 300; i++ {
+		var name string = fmt.Sprintf("dir%d/file%d.txt", randomness.Intn(4), randomness.Intn(20))
+
+		switch randomness.Intn(3) {
+		case 0:
+			if _, found := model[name]; !found {
+				continue
+			}
+			if err := tree.Remove(name); nil != err {
+				t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+			}
+			delete(model, name)
+		default:
+			var content string = fmt.Sprintf("content %d", i)
+			if err := tree.WriteFile(name, content, 0644); nil != err {
+				t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+			}
+			model[name] = content
+		}
+
+		var clone map[string]string = map[string]string{}
+		for name, content := range model {
+			clone[name] = content
+		}
+
+		snapshots = append(snapshots, tree.Snapshot())
+		models = append(models, clone)
+	}
+
+	for snapshotNumber, snapshot := range snapshots {
+		var expected map[string]string = models[snapshotNumber]
+
+		var actual map[string]string = map[string]string{}
+		err := fs.WalkDir(snapshot, ".", func(name string, entry fs.DirEntry, err error) error {
+			if nil != err {
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+
+			data, err := fs.ReadFile(snapshot, name)
+			if nil != err {
+				return err
+			}
+			actual[name] = string(data)
+			return nil
+		})
+		if nil != err {
+			t.Errorf("For snapshot #%d, did not expect an error but actually got one.", snapshotNumber)
+			t.Logf("ERROR: (%T) %s", err, err)
+			continue
+		}
+
+		if expected, actual := fmt.Sprint(sortedFiles(expected)), fmt.Sprint(sortedFiles(actual)); expected != actual {
+			t.Errorf("For snapshot #%d, the actual files were not what was expected.", snapshotNumber)
+			t.Logf("EXPECTED: %s", expected)
+			t.Logf("ACTUAL:   %s", actual)
+			continue
+		}
+	}
+}
+
+func expectFileContent(t *testing.T, fsys fs.FS, name string, expected string) {
+	t.Helper()
+
+	data, err := fs.ReadFile(fsys, name)
+	if nil != err {
+		t.Errorf("Did not expect an error reading %q but actually got one.", name)
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	if actual := string(data); expected != actual {
+		t.Errorf("The actual content of %q was not what was expected.", name)
+		t.Logf("EXPECTED CONTENT: %q", expected)
+		t.Logf("ACTUAL   CONTENT: %q", actual)
+	}
+}
+
+func sortedFiles(files map[string]string) []string {
+	var list []string
+	for name, content := range files {
+		list = append(list, name+"="+content)
+	}
+	sort.Strings(list)
+
+	return list
+}
@@ -0,0 +1,24 @@
+package strfs9p
+
+import (
+	"github.com/reiver/go-erorr"
+)
+
+const (
+	errAuthNotRequired   = erorr.Error("authentication not required")
+	errBadMessage        = erorr.Error("malformed message")
+	errBadOffset         = erorr.Error("bad offset in directory read")
+	errDirectoryNotEmpty = erorr.Error("directory not empty")
+	errFidInUse          = erorr.Error("fid already in use")
+	errFidNotOpen        = erorr.Error("fid not open")
+	errFidOpen           = erorr.Error("fid already open")
+	errFileTooBig        = erorr.Error("file too big")
+	errIsDirectory       = erorr.Error("is a directory")
+	errMessageTooBig     = erorr.Error("message too big")
+	errNotDirectory      = erorr.Error("not a directory")
+	errNotSupported      = erorr.Error("operation not supported")
+	errReadOnly          = erorr.Error("read-only file-system")
+	errTooManyNames      = erorr.Error("too many names in walk")
+	errUnknownFid        = erorr.Error("unknown fid")
+	errUnknownMessage    = erorr.Error("unknown message type")
+)
@@ -0,0 +1,295 @@
+package strfs9p
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"codeberg.org/reiver/go-strfs"
+)
+
+// internalFid is what a fid (the client's handle for a file, or directory) refers to.
+type internalFid struct {
+	// name is the path of the file (or directory) — "." for the root.
+	name string
+
+	open bool
+	readable bool
+	writable bool
+	removeOnClunk bool
+
+	// directory, and entries, are set for an open directory.
+	directory bool
+	entries []fs.FileInfo
+
+	// dirIndex and dirOffset are where the next (9P2000) read of an open directory continues from.
+	dirIndex int
+	dirOffset uint64
+
+	// data is the content of an open file — and perm its permission-bits.
+	// If dirty, then data has been written to (and not yet written back to the strfs.Tree).
+	data []byte
+	perm fs.FileMode
+	dirty bool
+}
+
+// childName returns the path of 'name' in the directory 'parent'.
+func childName(parent string, name string) string {
+	if "." == parent {
+		return name
+	}
+
+	return parent + "/" + name
+}
+
+// resize returns 'data' truncated (or extended with zeros) to be 'size' bytes long.
+//
+// A file cannot be made bigger than maxFileSize.
+func resize(data []byte, size uint64) ([]byte, error) {
+	if maxFileSize < size {
+		return nil, errFileTooBig
+	}
+
+	if size <= uint64(len(data)) {
+		return data[:size], nil
+	}
+	return append(data, make([]byte, size-uint64(len(data)))...), nil
+}
+
+// validName returns whether 'name' can be the name of a file in a directory.
+func validName(name string) bool {
+	return "" != name && "." != name && ".." != name && !strings.Contains(name, "/")
+}
+
+// change changes the file that 'f' refers to — its size (truncating, or extending it), permission-bits, and/or modification-time.
+// (Anything that is nil is not changed.)
+//
+// Only the modification-time of a directory can be changed — which is ignored, since a directory does not have one.
+func (receiver *internalSession) change(f *internalFid, size *uint64, mode *fs.FileMode, mtime *time.Time) error {
+	if nil == receiver.tree {
+		return errReadOnly
+	}
+
+	fileinfo, err := fs.Stat(receiver.fsys, f.name)
+	if nil != err {
+		return err
+	}
+	if fileinfo.IsDir() {
+		switch {
+		case nil != size:
+			return errIsDirectory
+		case nil != mode:
+			return errNotSupported
+		}
+		return nil
+	}
+
+	if nil != size {
+		if f.open && f.writable {
+			data, err := resize(f.data, *size)
+			if nil != err {
+				return err
+			}
+			f.data = data
+			f.dirty = true
+		} else {
+			data, err := fs.ReadFile(receiver.fsys, f.name)
+			if nil != err {
+				return err
+			}
+			data, err = resize(data, *size)
+			if nil != err {
+				return err
+			}
+			if err := receiver.tree.WriteFile(f.name, string(data), 0); nil != err {
+				return err
+			}
+		}
+	}
+
+	if nil != mode {
+		if err := receiver.tree.Chmod(f.name, *mode); nil != err {
+			return err
+		}
+		f.perm = mode.Perm()
+	}
+
+	if nil != mtime {
+		var diff strfs.Diff = strfs.Diff{
+			{Name:f.name, Kind:strfs.ChangeModTimeChanged, New:strfs.RegularFile{FileModTime:*mtime}},
+		}
+		if err := receiver.tree.Apply(diff); nil != err {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createFile creates the (empty) file 'name' in the directory that 'f' refers to, and makes 'f' refer to it.
+func (receiver *internalSession) createFile(f *internalFid, name string, perm fs.FileMode) error {
+	if f.open {
+		return errFidOpen
+	}
+	if nil == receiver.tree {
+		return errReadOnly
+	}
+	if !validName(name) {
+		return &fs.PathError{Op:"create", Path:name, Err:fs.ErrInvalid}
+	}
+
+	fileinfo, err := fs.Stat(receiver.fsys, f.name)
+	if nil != err {
+		return err
+	}
+	if !fileinfo.IsDir() {
+		return errNotDirectory
+	}
+
+	var child string = childName(f.name, name)
+	if _, err := fs.Stat(receiver.fsys, child); nil == err {
+		return &fs.PathError{Op:"create", Path:child, Err:fs.ErrExist}
+	}
+
+	if err := receiver.tree.WriteFile(child, "", perm); nil != err {
+		return err
+	}
+
+	f.name = child
+	return nil
+}
+
+// openFid opens the file (or directory) that 'f' refers to.
+func (receiver *internalSession) openFid(f *internalFid, readable bool, writable bool, truncate bool) (internalQid, error) {
+	if f.open {
+		return internalQid{}, errFidOpen
+	}
+
+	fileinfo, err := fs.Stat(receiver.fsys, f.name)
+	if nil != err {
+		return internalQid{}, err
+	}
+
+	if fileinfo.IsDir() {
+		if writable || truncate {
+			return internalQid{}, errIsDirectory
+		}
+
+		entries, err := fs.ReadDir(receiver.fsys, f.name)
+		if nil != err {
+			return internalQid{}, err
+		}
+
+		var fileinfos []fs.FileInfo
+		for _, entry := range entries {
+			fileinfo, err := entry.Info()
+			if nil != err {
+				return internalQid{}, err
+			}
+			fileinfos = append(fileinfos, fileinfo)
+		}
+
+		f.directory = true
+		f.entries = fileinfos
+	} else {
+		if (writable || truncate) && nil == receiver.tree {
+			return internalQid{}, errReadOnly
+		}
+
+		if truncate {
+			f.dirty = true
+		} else {
+			data, err := fs.ReadFile(receiver.fsys, f.name)
+			if nil != err {
+				return internalQid{}, err
+			}
+			f.data = data
+		}
+		f.perm = fileinfo.Mode().Perm()
+	}
+
+	f.open = true
+	f.readable = readable
+	f.writable = writable
+	return qidOf(f.name, fileinfo), nil
+}
+
+// release writes back what was written to 'f' (if anything), and removes the file if it was opened with ORCLOSE.
+func (receiver *internalSession) release(f *internalFid) error {
+	if f.removeOnClunk {
+		return receiver.tree.Remove(f.name)
+	}
+
+	return receiver.sync(f)
+}
+
+// releaseAll releases all the fids (for example, because the connection was closed).
+func (receiver *internalSession) releaseAll() {
+	for number, f := range receiver.fids {
+		receiver.release(f)
+		delete(receiver.fids, number)
+	}
+}
+
+// checkRename returns an error if the file (or directory) at 'oldname' could NOT be moved to 'newname'.
+func (receiver *internalSession) checkRename(oldname string, newname string) error {
+	if nil == receiver.tree {
+		return errReadOnly
+	}
+
+	oldinfo, err := fs.Stat(receiver.fsys, oldname)
+	if nil != err {
+		return err
+	}
+	if newinfo, err := fs.Stat(receiver.fsys, newname); nil == err && newinfo.IsDir() != oldinfo.IsDir() {
+		if newinfo.IsDir() {
+			return errIsDirectory
+		}
+		return errNotDirectory
+	}
+	if oldinfo.IsDir() && strings.HasPrefix(newname+"/", oldname+"/") {
+		return fs.ErrInvalid
+	}
+	if "." != path.Dir(newname) {
+		if parentinfo, err := fs.Stat(receiver.fsys, path.Dir(newname)); nil != err || !parentinfo.IsDir() {
+			return &fs.PathError{Op:"rename", Path:newname, Err:fs.ErrNotExist}
+		}
+	}
+
+	return nil
+}
+
+// renameFile moves the file (or directory) at 'oldname' to 'newname', and updates the fids that refer to it (or to something in it).
+func (receiver *internalSession) renameFile(oldname string, newname string) error {
+	if err := receiver.checkRename(oldname, newname); nil != err {
+		return err
+	}
+
+	if err := receiver.tree.Rename(oldname, newname); nil != err {
+		return err
+	}
+
+	for _, f := range receiver.fids {
+		switch {
+		case oldname == f.name:
+			f.name = newname
+		case strings.HasPrefix(f.name, oldname+"/"):
+			f.name = newname + strings.TrimPrefix(f.name, oldname)
+		}
+	}
+	return nil
+}
+
+// sync writes back what was written to 'f' (if anything) to the strfs.Tree.
+func (receiver *internalSession) sync(f *internalFid) error {
+	if !f.dirty {
+		return nil
+	}
+
+	if err := receiver.tree.WriteFile(f.name, string(f.data), f.perm); nil != err {
+		return err
+	}
+	f.dirty = false
+	return nil
+}
@@ -0,0 +1,235 @@
+package strfs9p
+
+import (
+	"encoding/binary"
+)
+
+// The 9P2000 message-types.
+const (
+	msgTversion = 100
+	msgRversion = 101
+	msgTauth    = 102
+	msgTattach  = 104
+	msgRerror   = 107
+	msgTflush   = 108
+	msgTwalk    = 110
+	msgTopen    = 112
+	msgTcreate  = 114
+	msgTread    = 116
+	msgTwrite   = 118
+	msgTclunk   = 120
+	msgTremove  = 122
+	msgTstat    = 124
+	msgTwstat   = 126
+)
+
+// The (extra) 9P2000.L message-types.
+const (
+	msgRlerror    = 7
+	msgTstatfs    = 8
+	msgTlopen     = 12
+	msgTlcreate   = 14
+	msgTrename    = 20
+	msgTgetattr   = 24
+	msgTsetattr   = 26
+	msgTxattrwalk = 30
+	msgTreaddir   = 40
+	msgTfsync     = 50
+	msgTmkdir     = 72
+	msgTrenameat  = 74
+	msgTunlinkat  = 76
+)
+
+const (
+	// headerSize is the size of size[4] type[1] tag[2], which every message starts with.
+	headerSize = 7
+
+	// ioHeaderSize is the size of the header of a Rread (or Twrite) message, before the data.
+	// The most data that can be read (or written) with a single message is the msize minus this.
+	ioHeaderSize = 24
+
+	// maxMessageSize is the largest msize that a strfs9p.Server agrees to.
+	maxMessageSize = 1 << 20
+
+	// minMessageSize is the smallest msize that a strfs9p.Server agrees to.
+	minMessageSize = 256
+
+	// maxFileSize is the biggest a client can make a file (by writing to it, or by changing its length).
+	maxFileSize = 64 << 20
+
+	// maxWalkNames is the most names that a single Twalk can have.
+	maxWalkNames = 16
+
+	// noTouch is what a (32-bit) field in a Twstat stat is set to, to mean it should not be changed.
+	noTouch = ^uint32(0)
+
+	// noFid means "no fid" (for example, in the afid of a Tattach).
+	noFid = ^uint32(0)
+)
+
+// qid-types.
+const (
+	qtDir  = 0x80
+	qtFile = 0x00
+)
+
+// dmDir is the bit in the mode of a 9P2000 stat that means it is a directory.
+const dmDir = 0x80000000
+
+// Open-modes (for Topen and Tcreate).
+const (
+	oRead   = 0
+	oWrite  = 1
+	oRdwr   = 2
+	oExec   = 3
+	oTrunc  = 0x10
+	oRclose = 0x40
+)
+
+// Linux open-flags (for Tlopen and Tlcreate).
+const (
+	lWronly = 01
+	lRdwr   = 02
+	lTrunc  = 01000
+)
+
+// The bits of the 'valid' field of Tsetattr.
+const (
+	setattrMode     = 0x00000001
+	setattrSize     = 0x00000008
+	setattrMtime    = 0x00000020
+	setattrMtimeSet = 0x00000100
+)
+
+// getattrBasic is the 'valid' field of Rgetattr — all the fields up to (and including) 'blocks'.
+const getattrBasic = 0x000007ff
+
+// Linux file-type bits (for the mode of Rgetattr).
+const (
+	sIFDIR = 0040000
+	sIFREG = 0100000
+)
+
+// Linux directory-entry types (for Rreaddir).
+const (
+	dtDir = 4
+	dtReg = 8
+)
+
+// atRemoveDir is the bit in the flags of Tunlinkat that means a directory is being removed.
+const atRemoveDir = 0x200
+
+// v9fsMagic is the file-system type returned in Rstatfs.
+const v9fsMagic = 0x01021997
+
+// internalQid identifies a file (or directory) on the server.
+type internalQid struct {
+	Type    uint8
+	Version uint32
+	Path    uint64
+}
+
+// internalDecoder decodes the (little-endian) fields of a message.
+//
+// If there is not enough data left for a field, then the zero value is returned, and err is set.
+type internalDecoder struct {
+	data []byte
+	err error
+}
+
+func (receiver *internalDecoder) next(n int) []byte {
+	if nil != receiver.err {
+		return nil
+	}
+	if len(receiver.data) < n {
+		receiver.err = errBadMessage
+		return nil
+	}
+
+	var p []byte = receiver.data[:n]
+	receiver.data = receiver.data[n:]
+	return p
+}
+
+func (receiver *internalDecoder) bytes(n int) []byte {
+	return receiver.next(n)
+}
+
+func (receiver *internalDecoder) string() string {
+	var length uint16 = receiver.uint16()
+	return string(receiver.next(int(length)))
+}
+
+func (receiver *internalDecoder) uint8() uint8 {
+	var p []byte = receiver.next(1)
+	if nil == p {
+		return 0
+	}
+	return p[0]
+}
+
+func (receiver *internalDecoder) uint16() uint16 {
+	var p []byte = receiver.next(2)
+	if nil == p {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(p)
+}
+
+func (receiver *internalDecoder) uint32() uint32 {
+	var p []byte = receiver.next(4)
+	if nil == p {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(p)
+}
+
+func (receiver *internalDecoder) uint64() uint64 {
+	var p []byte = receiver.next(8)
+	if nil == p {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(p)
+}
+
+// internalEncoder encodes the (little-endian) fields of a message.
+type internalEncoder struct {
+	data []byte
+}
+
+func (receiver *internalEncoder) bytes(p []byte) {
+	receiver.data = append(receiver.data, p...)
+}
+
+func (receiver *internalEncoder) qid(value internalQid) {
+	receiver.uint8(value.Type)
+	receiver.uint32(value.Version)
+	receiver.uint64(value.Path)
+}
+
+func (receiver *internalEncoder) string(value string) {
+	receiver.uint16(uint16(len(value)))
+	receiver.data = append(receiver.data, value...)
+}
+
+func (receiver *internalEncoder) uint8(value uint8) {
+	receiver.data = append(receiver.data, value)
+}
+
+func (receiver *internalEncoder) uint16(value uint16) {
+	var p [2]byte
+	binary.LittleEndian.PutUint16(p[:], value)
+	receiver.data = append(receiver.data, p[:]...)
+}
+
+func (receiver *internalEncoder) uint32(value uint32) {
+	var p [4]byte
+	binary.LittleEndian.PutUint32(p[:], value)
+	receiver.data = append(receiver.data, p[:]...)
+}
+
+func (receiver *internalEncoder) uint64(value uint64) {
+	var p [8]byte
+	binary.LittleEndian.PutUint64(p[:], value)
+	receiver.data = append(receiver.data, p[:]...)
+}
@@ -0,0 +1,476 @@
+package strfs9p
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// attach handles Tattach — fid[4] afid[4] uname[s] aname[s] (and, for 9P2000.L, n_uname[4]).
+func (receiver *internalSession) attach(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	request.uint32() // afid
+	request.string() // uname
+	request.string() // aname
+	if receiver.dotL {
+		request.uint32() // n_uname
+	}
+	if nil != request.err {
+		return request.err
+	}
+
+	if _, found := receiver.fids[number]; found {
+		return errFidInUse
+	}
+
+	fileinfo, err := fs.Stat(receiver.fsys, ".")
+	if nil != err {
+		return err
+	}
+
+	receiver.fids[number] = &internalFid{name:"."}
+
+	response.qid(qidOf(".", fileinfo))
+	return nil
+}
+
+// auth handles Tauth — which always fails, since a strfs9p.Server does not do authentication.
+func (receiver *internalSession) auth(request *internalDecoder, response *internalEncoder) error {
+	return errAuthNotRequired
+}
+
+// clunk handles Tclunk — fid[4].
+func (receiver *internalSession) clunk(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+	delete(receiver.fids, number)
+
+	return receiver.release(f)
+}
+
+// create handles Tcreate — fid[4] name[s] perm[4] mode[1].
+func (receiver *internalSession) create(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	var name string = request.string()
+	var perm uint32 = request.uint32()
+	var mode uint8 = request.uint8()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+	if 0 != perm & dmDir {
+		// A strfs.Tree cannot have empty directories.
+		return errNotSupported
+	}
+
+	if err := receiver.createFile(f, name, fs.FileMode(perm).Perm()); nil != err {
+		return err
+	}
+
+	return receiver.openMode(f, mode, response)
+}
+
+// flush handles Tflush — oldtag[2].
+//
+// Since each message is handled before the next one is read, there is never anything to flush.
+func (receiver *internalSession) flush(request *internalDecoder, response *internalEncoder) error {
+	request.uint16() // oldtag
+	return request.err
+}
+
+// open handles Topen — fid[4] mode[1].
+func (receiver *internalSession) open(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	var mode uint8 = request.uint8()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+
+	return receiver.openMode(f, mode, response)
+}
+
+// openMode opens 'f' with the (9P2000) open-mode 'mode', and encodes the qid[13] iounit[4] of a Ropen (or Rcreate).
+func (receiver *internalSession) openMode(f *internalFid, mode uint8, response *internalEncoder) error {
+	var readable bool = oWrite != mode & 3
+	var writable bool = oWrite == mode & 3 || oRdwr == mode & 3
+
+	if 0 != mode & oRclose {
+		if nil == receiver.tree {
+			return errReadOnly
+		}
+		f.removeOnClunk = true
+	}
+
+	qid, err := receiver.openFid(f, readable, writable, 0 != mode & oTrunc)
+	if nil != err {
+		f.removeOnClunk = false
+		return err
+	}
+
+	response.qid(qid)
+	response.uint32(receiver.iounit())
+	return nil
+}
+
+// read handles Tread — fid[4] offset[8] count[4].
+//
+// For a directory, the data is the (9P2000) stats of what is in the directory.
+func (receiver *internalSession) read(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	var offset uint64 = request.uint64()
+	var count uint32 = request.uint32()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+	if !f.open || !f.readable {
+		return errFidNotOpen
+	}
+	if receiver.iounit() < count {
+		count = receiver.iounit()
+	}
+
+	var data []byte
+	switch {
+	case f.directory:
+		data, err = receiver.readDirectory(f, offset, count)
+		if nil != err {
+			return err
+		}
+	case offset < uint64(len(f.data)):
+		data = f.data[offset:]
+		if uint32(len(data)) > count {
+			data = data[:count]
+		}
+	}
+
+	response.uint32(uint32(len(data)))
+	response.bytes(data)
+	return nil
+}
+
+// readDirectory returns as many (whole) stats of what is in the directory 'f' as fit in 'count' bytes, starting at 'offset'.
+//
+// 'offset' must be either zero, or where the previous read of the directory ended.
+func (receiver *internalSession) readDirectory(f *internalFid, offset uint64, count uint32) ([]byte, error) {
+	switch {
+	case 0 == offset:
+		f.dirIndex = 0
+		f.dirOffset = 0
+	case f.dirOffset != offset:
+		return nil, errBadOffset
+	}
+
+	var data []byte
+	for f.dirIndex < len(f.entries) {
+		var fileinfo fs.FileInfo = f.entries[f.dirIndex]
+
+		var stat []byte = statOf(childName(f.name, fileinfo.Name()), fileinfo)
+		if uint32(len(data) + len(stat)) > count {
+			break
+		}
+
+		data = append(data, stat...)
+		f.dirIndex++
+		f.dirOffset += uint64(len(stat))
+	}
+
+	return data, nil
+}
+
+// remove handles Tremove — fid[4].
+//
+// The fid is clunked, even if the file could not be removed.
+func (receiver *internalSession) remove(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+	delete(receiver.fids, number)
+
+	if nil == receiver.tree {
+		return errReadOnly
+	}
+
+	fileinfo, err := fs.Stat(receiver.fsys, f.name)
+	if nil != err {
+		return err
+	}
+	if fileinfo.IsDir() {
+		// (A directory in a strfs.Tree always has something in it.)
+		return errDirectoryNotEmpty
+	}
+
+	return receiver.tree.Remove(f.name)
+}
+
+// stat handles Tstat — fid[4].
+func (receiver *internalSession) stat(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+
+	fileinfo, err := fs.Stat(receiver.fsys, f.name)
+	if nil != err {
+		return err
+	}
+
+	var stat []byte = statOf(f.name, fileinfo)
+	response.uint16(uint16(len(stat)))
+	response.bytes(stat)
+	return nil
+}
+
+// version handles Tversion — msize[4] version[s].
+//
+// Both "9P2000" and "9P2000.L" are agreed to.
+// A Tversion also clunks all the fids.
+func (receiver *internalSession) version(request *internalDecoder, response *internalEncoder) error {
+	var msize uint32 = request.uint32()
+	var version string = request.string()
+	if nil != request.err {
+		return request.err
+	}
+
+	if msize < minMessageSize {
+		return errMessageTooBig
+	}
+	if maxMessageSize < msize {
+		msize = maxMessageSize
+	}
+
+	receiver.releaseAll()
+	receiver.msize = msize
+
+	switch {
+	case strings.HasPrefix(version, "9P2000.L"):
+		receiver.dotL = true
+		version = "9P2000.L"
+	case strings.HasPrefix(version, "9P2000"):
+		receiver.dotL = false
+		version = "9P2000"
+	default:
+		version = "unknown"
+	}
+
+	response.uint32(msize)
+	response.string(version)
+	return nil
+}
+
+// walk handles Twalk — fid[4] newfid[4] nwname[2] nwname*(wname[s]).
+//
+// If only some of the names could be walked, then the qids of those are returned (and 'newfid' is NOT made).
+func (receiver *internalSession) walk(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	var newnumber uint32 = request.uint32()
+	var nwname uint16 = request.uint16()
+	if maxWalkNames < nwname {
+		return errTooManyNames
+	}
+	var names []string
+	for i := uint16(0); i < nwname; i++ {
+		names = append(names, request.string())
+	}
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+	if f.open {
+		return errFidOpen
+	}
+	if _, found := receiver.fids[newnumber]; found && number != newnumber {
+		return errFidInUse
+	}
+
+	var name string = f.name
+	var qids []internalQid
+	if 0 < len(names) {
+		fileinfo, err := fs.Stat(receiver.fsys, name)
+		if nil != err {
+			return err
+		}
+
+		for _, wname := range names {
+			if !fileinfo.IsDir() {
+				err = errNotDirectory
+				break
+			}
+
+			var next string
+			switch {
+			case ".." == wname:
+				next = path.Dir(name)
+			case validName(wname):
+				next = childName(name, wname)
+			default:
+				err = &fs.PathError{Op:"walk", Path:wname, Err:fs.ErrNotExist}
+			}
+			if nil != err {
+				break
+			}
+
+			fileinfo, err = fs.Stat(receiver.fsys, next)
+			if nil != err {
+				break
+			}
+
+			name = next
+			qids = append(qids, qidOf(name, fileinfo))
+		}
+
+		if 0 == len(qids) {
+			return err
+		}
+	}
+
+	if len(qids) == len(names) {
+		receiver.fids[newnumber] = &internalFid{name:name}
+	}
+
+	response.uint16(uint16(len(qids)))
+	for _, qid := range qids {
+		response.qid(qid)
+	}
+	return nil
+}
+
+// write handles Twrite — fid[4] offset[8] count[4] data[count].
+func (receiver *internalSession) write(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	var offset uint64 = request.uint64()
+	var count uint32 = request.uint32()
+	var data []byte = request.bytes(int(count))
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+	if !f.open || !f.writable {
+		return errFidNotOpen
+	}
+
+	// (Checked this way, rather than with offset+count, so that a huge offset cannot overflow.)
+	if maxFileSize < offset || maxFileSize-offset < uint64(count) {
+		return errFileTooBig
+	}
+	if end := offset + uint64(count); uint64(len(f.data)) < end {
+		f.data, err = resize(f.data, end)
+		if nil != err {
+			return err
+		}
+	}
+	copy(f.data[offset:], data)
+	f.dirty = true
+
+	response.uint32(count)
+	return nil
+}
+
+// wstat handles Twstat — fid[4] n[2] stat[n].
+//
+// The name (which renames the file within its directory), permission-bits, modification-time, and length can be changed.
+// Everything else in the stat is ignored.
+//
+// If the new name is invalid (or the file cannot be renamed to it), then nothing is changed.
+func (receiver *internalSession) wstat(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	request.uint16() // n
+	request.uint16() // size
+	request.uint16() // type
+	request.uint32() // dev
+	request.bytes(13) // qid
+	var mode uint32 = request.uint32()
+	request.uint32() // atime
+	var mtime uint32 = request.uint32()
+	var length uint64 = request.uint64()
+	var name string = request.string()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+	if nil == receiver.tree {
+		return errReadOnly
+	}
+
+	var size *uint64
+	if ^uint64(0) != length {
+		size = &length
+	}
+	var perm *fs.FileMode
+	if noTouch != mode {
+		var value fs.FileMode = fs.FileMode(mode).Perm()
+		perm = &value
+	}
+	var modtime *time.Time
+	if noTouch != mtime {
+		var value time.Time = time.Unix(int64(mtime), 0)
+		modtime = &value
+	}
+
+	// The new name is checked before anything is changed, so that a Twstat with a bad name changes nothing.
+	var newname string
+	if "" != name && path.Base(f.name) != name {
+		if "." == f.name || !validName(name) {
+			return &fs.PathError{Op:"wstat", Path:name, Err:fs.ErrInvalid}
+		}
+
+		newname = childName(path.Dir(f.name), name)
+		if err := receiver.checkRename(f.name, newname); nil != err {
+			return err
+		}
+	}
+
+	if err := receiver.change(f, size, perm, modtime); nil != err {
+		return err
+	}
+
+	if "" != newname {
+		return receiver.renameFile(f.name, newname)
+	}
+
+	return nil
+}
@@ -0,0 +1,361 @@
+package strfs9p
+
+import (
+	"io/fs"
+	"time"
+)
+
+// fsync handles Tfsync — fid[4] (and, from newer clients, datasync[4]).
+func (receiver *internalSession) fsync(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+
+	return receiver.sync(f)
+}
+
+// getattr handles Tgetattr — fid[4] request_mask[8].
+//
+// The basic attributes are always returned, whatever was requested.
+func (receiver *internalSession) getattr(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	request.uint64() // request_mask
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+
+	fileinfo, err := fs.Stat(receiver.fsys, f.name)
+	if nil != err {
+		return err
+	}
+
+	var mode uint32 = uint32(fileinfo.Mode().Perm())
+	var size uint64 = uint64(fileinfo.Size())
+	if fileinfo.IsDir() {
+		mode |= sIFDIR
+		size = 0
+	} else {
+		mode |= sIFREG
+	}
+
+	var seconds uint64
+	var nanoseconds uint64
+	if mtime := fileinfo.ModTime(); !mtime.IsZero() {
+		seconds = uint64(mtime.Unix())
+		nanoseconds = uint64(mtime.Nanosecond())
+	}
+
+	response.uint64(getattrBasic)
+	response.qid(qidOf(f.name, fileinfo))
+	response.uint32(mode)
+	response.uint32(0) // uid
+	response.uint32(0) // gid
+	response.uint64(1) // nlink
+	response.uint64(0) // rdev
+	response.uint64(size)
+	response.uint64(4096) // blksize
+	response.uint64((size + 511) / 512) // blocks
+	for i := 0; i < 4; i++ {
+		// atime, mtime, ctime, btime
+		response.uint64(seconds)
+		response.uint64(nanoseconds)
+	}
+	response.uint64(0) // gen
+	response.uint64(0) // data_version
+	return nil
+}
+
+// lcreate handles Tlcreate — fid[4] name[s] flags[4] mode[4] gid[4].
+func (receiver *internalSession) lcreate(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	var name string = request.string()
+	var flags uint32 = request.uint32()
+	var mode uint32 = request.uint32()
+	request.uint32() // gid
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+
+	if err := receiver.createFile(f, name, fs.FileMode(mode).Perm()); nil != err {
+		return err
+	}
+
+	return receiver.openFlags(f, flags, response)
+}
+
+// lopen handles Tlopen — fid[4] flags[4].
+func (receiver *internalSession) lopen(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	var flags uint32 = request.uint32()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+
+	return receiver.openFlags(f, flags, response)
+}
+
+// openFlags opens 'f' with the Linux open-flags 'flags', and encodes the qid[13] iounit[4] of a Rlopen (or Rlcreate).
+func (receiver *internalSession) openFlags(f *internalFid, flags uint32, response *internalEncoder) error {
+	var readable bool = lWronly != flags & 3
+	var writable bool = lWronly == flags & 3 || lRdwr == flags & 3
+
+	qid, err := receiver.openFid(f, readable, writable, writable && 0 != flags & lTrunc)
+	if nil != err {
+		return err
+	}
+
+	response.qid(qid)
+	response.uint32(receiver.iounit())
+	return nil
+}
+
+// readdir handles Treaddir — fid[4] offset[8] count[4].
+//
+// The offset of an entry is (one more than) its index in the directory.
+func (receiver *internalSession) readdir(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	var offset uint64 = request.uint64()
+	var count uint32 = request.uint32()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+	if !f.open || !f.directory {
+		return errFidNotOpen
+	}
+	if receiver.iounit() < count {
+		count = receiver.iounit()
+	}
+
+	var entries internalEncoder
+	for index := offset; index < uint64(len(f.entries)); index++ {
+		var fileinfo fs.FileInfo = f.entries[index]
+
+		var entry internalEncoder
+		entry.qid(qidOf(childName(f.name, fileinfo.Name()), fileinfo))
+		entry.uint64(index + 1)
+		if fileinfo.IsDir() {
+			entry.uint8(dtDir)
+		} else {
+			entry.uint8(dtReg)
+		}
+		entry.string(fileinfo.Name())
+
+		if uint32(len(entries.data) + len(entry.data)) > count {
+			break
+		}
+		entries.bytes(entry.data)
+	}
+
+	response.uint32(uint32(len(entries.data)))
+	response.bytes(entries.data)
+	return nil
+}
+
+// rename handles Trename — fid[4] dfid[4] name[s].
+func (receiver *internalSession) rename(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	var dnumber uint32 = request.uint32()
+	var name string = request.string()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+	d, err := receiver.fid(dnumber)
+	if nil != err {
+		return err
+	}
+	if !validName(name) {
+		return &fs.PathError{Op:"rename", Path:name, Err:fs.ErrInvalid}
+	}
+
+	return receiver.renameFile(f.name, childName(d.name, name))
+}
+
+// renameat handles Trenameat — olddirfid[4] oldname[s] newdirfid[4] newname[s].
+func (receiver *internalSession) renameat(request *internalDecoder, response *internalEncoder) error {
+	var oldnumber uint32 = request.uint32()
+	var oldname string = request.string()
+	var newnumber uint32 = request.uint32()
+	var newname string = request.string()
+	if nil != request.err {
+		return request.err
+	}
+
+	olddir, err := receiver.fid(oldnumber)
+	if nil != err {
+		return err
+	}
+	newdir, err := receiver.fid(newnumber)
+	if nil != err {
+		return err
+	}
+	if !validName(oldname) || !validName(newname) {
+		return fs.ErrInvalid
+	}
+
+	return receiver.renameFile(childName(olddir.name, oldname), childName(newdir.name, newname))
+}
+
+// setattr handles Tsetattr — fid[4] valid[4] mode[4] uid[4] gid[4] size[8] atime_sec[8] atime_nsec[8] mtime_sec[8] mtime_nsec[8].
+//
+// The permission-bits, size, and modification-time can be changed.
+// Everything else is ignored.
+func (receiver *internalSession) setattr(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	var valid uint32 = request.uint32()
+	var mode uint32 = request.uint32()
+	request.uint32() // uid
+	request.uint32() // gid
+	var size uint64 = request.uint64()
+	request.uint64() // atime_sec
+	request.uint64() // atime_nsec
+	var mtimeSeconds uint64 = request.uint64()
+	var mtimeNanoseconds uint64 = request.uint64()
+	if nil != request.err {
+		return request.err
+	}
+
+	f, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+	if 0 == valid & (setattrMode | setattrSize | setattrMtime) {
+		return nil
+	}
+
+	var sizeptr *uint64
+	if 0 != valid & setattrSize {
+		sizeptr = &size
+	}
+	var perm *fs.FileMode
+	if 0 != valid & setattrMode {
+		var value fs.FileMode = fs.FileMode(mode).Perm()
+		perm = &value
+	}
+	var modtime *time.Time
+	if 0 != valid & setattrMtime {
+		var value time.Time = time.Now()
+		if 0 != valid & setattrMtimeSet {
+			value = time.Unix(int64(mtimeSeconds), int64(mtimeNanoseconds))
+		}
+		modtime = &value
+	}
+
+	return receiver.change(f, sizeptr, perm, modtime)
+}
+
+// statfs handles Tstatfs — fid[4].
+func (receiver *internalSession) statfs(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	if nil != request.err {
+		return request.err
+	}
+
+	if _, err := receiver.fid(number); nil != err {
+		return err
+	}
+
+	const blockSize = 4096
+
+	var files uint64
+	var blocks uint64
+	err := fs.WalkDir(receiver.fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if nil != err {
+			return err
+		}
+		files++
+
+		if entry.IsDir() {
+			return nil
+		}
+		fileinfo, err := entry.Info()
+		if nil != err {
+			return err
+		}
+		blocks += (uint64(fileinfo.Size()) + blockSize - 1) / blockSize
+		return nil
+	})
+	if nil != err {
+		return err
+	}
+
+	response.uint32(v9fsMagic) // type
+	response.uint32(blockSize) // bsize
+	response.uint64(blocks)
+	response.uint64(0) // bfree
+	response.uint64(0) // bavail
+	response.uint64(files)
+	response.uint64(0) // ffree
+	response.uint64(0) // fsid
+	response.uint32(255) // namelen
+	return nil
+}
+
+// unlinkat handles Tunlinkat — dirfd[4] name[s] flags[4].
+func (receiver *internalSession) unlinkat(request *internalDecoder, response *internalEncoder) error {
+	var number uint32 = request.uint32()
+	var name string = request.string()
+	var flags uint32 = request.uint32()
+	if nil != request.err {
+		return request.err
+	}
+
+	d, err := receiver.fid(number)
+	if nil != err {
+		return err
+	}
+	if nil == receiver.tree {
+		return errReadOnly
+	}
+	if !validName(name) {
+		return &fs.PathError{Op:"unlinkat", Path:name, Err:fs.ErrInvalid}
+	}
+
+	var child string = childName(d.name, name)
+
+	fileinfo, err := fs.Stat(receiver.fsys, child)
+	if nil != err {
+		return err
+	}
+	switch {
+	case fileinfo.IsDir() && 0 != flags & atRemoveDir:
+		// (A directory in a strfs.Tree always has something in it.)
+		return errDirectoryNotEmpty
+	case fileinfo.IsDir():
+		return errIsDirectory
+	case 0 != flags & atRemoveDir:
+		return errNotDirectory
+	}
+
+	return receiver.tree.Remove(child)
+}
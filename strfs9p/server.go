@@ -0,0 +1,100 @@
+// Package strfs9p lets a strfs file-system be served over the 9P protocol (both 9P2000, and the 9P2000.L variant used by Linux and QEMU virtfs).
+//
+// Any fs.FS (such as a strfs.FS, or a strfs.Snapshot) can be served read-only.
+// A strfs.Tree is served read-write.
+//
+// Example usage:
+//
+//	listener, err := net.Listen("tcp", ":5640")
+//
+//	// ...
+//
+//	err = strfs9p.CreateServer(fsys).Serve(listener)
+//
+// Which (on Linux) could then be mounted with something like:
+//
+//	mount -t 9p -o trans=tcp,port=5640,version=9p2000.L 127.0.0.1 /mnt
+package strfs9p
+
+import (
+	"io"
+	"io/fs"
+	"net"
+
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/internal/adapter"
+)
+
+// Server serves a file-system over 9P.
+//
+// Regular files are served as files (with a qid-type of QTFILE), and directories are served as directories (with a qid-type of QTDIR).
+//
+// If the file-system is a *strfs.Tree, then clients can also create, write, remove, rename, and chmod files.
+// (Data written to a file is written to the strfs.Tree when the file is clunked (i.e., closed), or fsync'ed.)
+// Because a strfs.Tree cannot have empty directories, clients cannot make directories.
+// Clients cannot make a file bigger than 64 MiB.
+//
+// Otherwise, the file-system is served read-only.
+type Server struct {
+	fsys fs.FS
+	tree *strfs.Tree
+}
+
+// CreateServer returns a strfs9p.Server that serves 'fsys'.
+//
+// If 'fsys' is a *strfs.Tree, then it is served read-write; otherwise it is served read-only.
+func CreateServer(fsys fs.FS) Server {
+	tree, _ := fsys.(*strfs.Tree)
+
+	return Server{
+		fsys:fsys,
+		tree:tree,
+	}
+}
+
+// Serve accepts connections from 'listener', and serves each of them (in its own goroutine) with ServeConn.
+//
+// Serve returns when 'listener' returns an error (for example, because it was closed).
+func (receiver Server) Serve(listener net.Listener) error {
+	if nil == listener {
+		return adapter.ErrNilListener
+	}
+	if nil == receiver.fsys {
+		return adapter.ErrNilFS
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if nil != err {
+			return err
+		}
+
+		go receiver.ServeConn(conn)
+	}
+}
+
+// ServeConn serves 9P over a single connection, until the connection is closed (or there is an error).
+//
+// 'conn' does not have to be a net.Conn — for example, one end of a net.Pipe (or any other in-process pipe) works too.
+//
+// ServeConn closes 'conn' before it returns.
+func (receiver Server) ServeConn(conn io.ReadWriteCloser) error {
+	if nil == conn {
+		return adapter.ErrNilConn
+	}
+	defer conn.Close()
+
+	if nil == receiver.fsys {
+		return adapter.ErrNilFS
+	}
+
+	var session internalSession = internalSession{
+		fsys:receiver.fsys,
+		tree:receiver.tree,
+		conn:conn,
+		msize:maxMessageSize,
+		fids:map[uint32]*internalFid{},
+	}
+
+	return session.serve()
+}
@@ -0,0 +1,458 @@
+package strfs9p_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfs9p"
+
+	"encoding/binary"
+	"io"
+	"io/fs"
+	"net"
+	"time"
+
+	"testing"
+)
+
+func exampleFS() strfs.FS {
+	var mtime time.Time = time.Date(2022, 12, 12, 10, 30, 14, 0, time.UTC)
+
+	return strfs.FS{
+		"index.html": strfs.RegularFile{
+			FileContent: strfs.CreateContent("<!DOCTYPE html>"+"\n"+"<html><body>Hello world!</body></html>"),
+			FileModTime: mtime,
+			FileMode:    0644,
+		},
+		"css/style.css": strfs.RegularFile{
+			FileContent: strfs.CreateContent("body{color:red}"),
+			FileModTime: mtime,
+			FileMode:    0644,
+		},
+	}
+}
+
+// client is a (very) minimal 9P client, just for testing.
+type client struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+// dial returns a client connected (with an in-process pipe) to a strfs9p.Server serving 'fsys'.
+func dial(t *testing.T, fsys fs.FS) *client {
+	t.Helper()
+
+	clientConn, serverConn := net.Pipe()
+	go strfs9p.CreateServer(fsys).ServeConn(serverConn)
+	t.Cleanup(func() {
+		clientConn.Close()
+	})
+
+	return &client{t:t, conn:clientConn}
+}
+
+// rpc sends a T-message, and returns the type and body of the R-message that came back.
+func (receiver *client) rpc(messageType uint8, fields ...any) (uint8, []byte) {
+	receiver.t.Helper()
+
+	var message []byte = []byte{0, 0, 0, 0, messageType, 1, 0}
+	for _, field := range fields {
+		switch value := field.(type) {
+		case uint8:
+			message = append(message, value)
+		case uint16:
+			message = appendUint16(message, value)
+		case uint32:
+			message = appendUint32(message, value)
+		case uint64:
+			message = appendUint64(message, value)
+		case string:
+			message = appendUint16(message, uint16(len(value)))
+			message = append(message, value...)
+		case []byte:
+			message = append(message, value...)
+		default:
+			receiver.t.Fatalf("unknown field type %T", field)
+		}
+	}
+	binary.LittleEndian.PutUint32(message, uint32(len(message)))
+
+	receiver.conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := receiver.conn.Write(message); nil != err {
+		receiver.t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(receiver.conn, size[:]); nil != err {
+		receiver.t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	var response []byte = make([]byte, binary.LittleEndian.Uint32(size[:])-4)
+	if _, err := io.ReadFull(receiver.conn, response); nil != err {
+		receiver.t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	return response[0], response[3:]
+}
+
+// expect is like rpc, except that it fails the test if the R-message is not of type 'expected'.
+func (receiver *client) expect(expected uint8, messageType uint8, fields ...any) []byte {
+	receiver.t.Helper()
+
+	actual, body := receiver.rpc(messageType, fields...)
+	if expected != actual {
+		receiver.t.Fatalf("For T-message type %d, the actual R-message type was not what was expected: expected %d, actually %d (body %q)", messageType, expected, actual, body)
+	}
+
+	return body
+}
+
+func appendUint16(p []byte, value uint16) []byte {
+	return append(p, byte(value), byte(value>>8))
+}
+
+func appendUint32(p []byte, value uint32) []byte {
+	return appendUint16(appendUint16(p, uint16(value)), uint16(value>>16))
+}
+
+func appendUint64(p []byte, value uint64) []byte {
+	return appendUint32(appendUint32(p, uint32(value)), uint32(value>>32))
+}
+
+// stats returns the names (and lengths) in a sequence of 9P2000 stats.
+func stats(data []byte) map[string]uint64 {
+	var result map[string]uint64 = map[string]uint64{}
+
+	for 2 <= len(data) {
+		var size int = int(binary.LittleEndian.Uint16(data))
+		var stat []byte = data[2:2+size]
+
+		var length uint64 = binary.LittleEndian.Uint64(stat[2+4+13+4+4+4:])
+		var namelen int = int(binary.LittleEndian.Uint16(stat[2+4+13+4+4+4+8:]))
+		var name string = string(stat[2+4+13+4+4+4+8+2:][:namelen])
+		result[name] = length
+
+		data = data[2+size:]
+	}
+
+	return result
+}
+
+func expectFile(t *testing.T, fsys fs.FS, name string, expected string) {
+	t.Helper()
+
+	data, err := fs.ReadFile(fsys, name)
+	if nil != err {
+		t.Errorf("Did not expect an error reading %q but actually got one.", name)
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	if actual := string(data); expected != actual {
+		t.Errorf("The actual content of %q was not what was expected.", name)
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+}
+
+const (
+	rversion = 101
+	rattach  = 105
+	rerror   = 107
+	rwalk    = 111
+	ropen    = 113
+	rcreate  = 115
+	rread    = 117
+	rwrite   = 119
+	rclunk   = 121
+	rremove  = 123
+	rstat    = 125
+	rwstat   = 127
+
+	rlerror   = 7
+	rstatfs   = 9
+	rlopen    = 13
+	rlcreate  = 15
+	rgetattr  = 25
+	rsetattr  = 27
+	rreaddir  = 41
+	rfsync    = 51
+	rrenameat = 75
+	runlinkat = 77
+)
+
+func TestServer_9P2000(t *testing.T) {
+
+	var c *client = dial(t, exampleFS())
+
+	{
+		var body []byte = c.expect(rversion, 100, uint32(8192), "9P2000")
+		if expected, actual := "9P2000", string(body[6:]); expected != actual {
+			t.Errorf("The actual version was not what was expected: expected %q, actually %q", expected, actual)
+		}
+	}
+
+	{
+		var body []byte = c.expect(rattach, 104, uint32(1), ^uint32(0), "user", "")
+		if expected, actual := uint8(0x80), body[0]; expected != actual {
+			t.Errorf("Expected the qid of the root to be a directory: expected type 0x%x, actually 0x%x", expected, actual)
+		}
+	}
+
+	{
+		var body []byte = c.expect(rwalk, 110, uint32(1), uint32(2), uint16(2), "css", "style.css")
+		if expected, actual := uint16(2), binary.LittleEndian.Uint16(body); expected != actual {
+			t.Fatalf("The actual number of qids was not what was expected: expected %d, actually %d", expected, actual)
+		}
+		if expected, actual := uint8(0x80), body[2]; expected != actual {
+			t.Errorf("Expected the first qid to be a directory: expected type 0x%x, actually 0x%x", expected, actual)
+		}
+		if expected, actual := uint8(0x00), body[2+13]; expected != actual {
+			t.Errorf("Expected the second qid to be a file: expected type 0x%x, actually 0x%x", expected, actual)
+		}
+	}
+
+	c.expect(ropen, 112, uint32(2), uint8(0))
+
+	{
+		var body []byte = c.expect(rread, 116, uint32(2), uint64(5), uint32(100))
+		if expected, actual := "color:red}", string(body[4:]); expected != actual {
+			t.Errorf("The actual data read was not what was expected: expected %q, actually %q", expected, actual)
+		}
+	}
+
+	{
+		var body []byte = c.expect(rstat, 124, uint32(2))
+		if expected, actual := map[string]uint64{"style.css":15}, stats(body[2:]); 1 != len(actual) || expected["style.css"] != actual["style.css"] {
+			t.Errorf("The actual stat was not what was expected: expected %v, actually %v", expected, actual)
+		}
+	}
+
+	c.expect(rclunk, 120, uint32(2))
+
+	c.expect(rwalk, 110, uint32(1), uint32(3), uint16(0))
+	c.expect(ropen, 112, uint32(3), uint8(0))
+	{
+		var body []byte = c.expect(rread, 116, uint32(3), uint64(0), uint32(8192))
+		var actual map[string]uint64 = stats(body[4:])
+		if expected := 2; expected != len(actual) {
+			t.Errorf("The actual number of directory entries was not what was expected: expected %d, actually %d: %v", expected, len(actual), actual)
+		}
+		if expected := uint64(54); expected != actual["index.html"] {
+			t.Errorf("The actual length of index.html was not what was expected: expected %d, actually %d", expected, actual["index.html"])
+		}
+		if _, found := actual["css"]; !found {
+			t.Errorf("Expected the directory entries to include %q: %v", "css", actual)
+		}
+	}
+
+	c.expect(rerror, 110, uint32(1), uint32(4), uint16(1), "missing.txt")
+
+	c.expect(rwalk, 110, uint32(1), uint32(5), uint16(1), "index.html")
+	{
+		var body []byte = c.expect(rerror, 112, uint32(5), uint8(1))
+		if expected, actual := "read-only file-system", string(body[2:]); expected != actual {
+			t.Errorf("The actual error was not what was expected: expected %q, actually %q", expected, actual)
+		}
+	}
+}
+
+func TestServer_9P2000_tree(t *testing.T) {
+
+	tree, err := strfs.CreateTree(exampleFS())
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var c *client = dial(t, tree)
+
+	c.expect(rversion, 100, uint32(8192), "9P2000")
+	c.expect(rattach, 104, uint32(1), ^uint32(0), "user", "")
+
+	c.expect(rwalk, 110, uint32(1), uint32(2), uint16(0))
+	c.expect(rcreate, 114, uint32(2), "new.txt", uint32(0600), uint8(2))
+	c.expect(rwrite, 118, uint32(2), uint64(0), uint32(5), []byte("hello"))
+	c.expect(rwrite, 118, uint32(2), uint64(5), uint32(7), []byte(" world!"))
+
+	// Writes that would overflow the offset, or make the file too big, are errors (rather than crashing the server).
+	c.expect(rerror, 118, uint32(2), ^uint64(0), uint32(1), []byte("x"))
+	c.expect(rerror, 118, uint32(2), uint64(1)<<40, uint32(1), []byte("x"))
+
+	c.expect(rclunk, 120, uint32(2))
+
+	expectFile(t, tree, "new.txt", "hello world!")
+
+	c.expect(rwalk, 110, uint32(1), uint32(3), uint16(1), "new.txt")
+
+	// wstat returns a stat whose fields are all "don't touch", except for the name and length.
+	wstat := func(length uint64, name string) []byte {
+		var stat []byte
+		stat = appendUint16(stat, 0xffff) // type
+		stat = appendUint32(stat, 0xffffffff) // dev
+		stat = append(stat, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff) // qid
+		stat = appendUint32(stat, 0xffffffff) // mode
+		stat = appendUint32(stat, 0xffffffff) // atime
+		stat = appendUint32(stat, 0xffffffff) // mtime
+		stat = appendUint64(stat, length) // length
+		stat = appendUint16(stat, uint16(len(name)))
+		stat = append(stat, name...)
+		stat = append(stat, 0, 0, 0, 0, 0, 0) // uid, gid, muid
+
+		return append(appendUint16(nil, uint16(len(stat))), stat...)
+	}
+
+	// A Twstat with a bad name (or a name that cannot be renamed to) changes nothing — not even the length.
+	for _, name := range []string{"bad/name", "..", "css"} {
+		var stat []byte = wstat(1, name)
+		c.expect(rerror, 126, uint32(3), uint16(len(stat)), stat)
+	}
+	expectFile(t, tree, "new.txt", "hello world!")
+
+	{
+		var stat []byte = wstat(5, "renamed.txt")
+		c.expect(rwstat, 126, uint32(3), uint16(len(stat)), stat)
+	}
+
+	expectFile(t, tree, "renamed.txt", "hello")
+	if _, err := fs.Stat(tree, "new.txt"); nil == err {
+		t.Errorf("Expected the renamed file to not exist at its old name anymore.")
+	}
+
+	c.expect(rremove, 122, uint32(3))
+	if _, err := fs.Stat(tree, "renamed.txt"); nil == err {
+		t.Errorf("Expected the removed file to not exist anymore.")
+	}
+
+	c.expect(rwalk, 110, uint32(1), uint32(4), uint16(0))
+	c.expect(rerror, 114, uint32(4), "dir", uint32(0x80000755), uint8(0))
+}
+
+func TestServer_9P2000L(t *testing.T) {
+
+	tree, err := strfs.CreateTree(exampleFS())
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if nil != err {
+		t.Skipf("Could not listen on the loopback interface: %s", err)
+	}
+	defer listener.Close()
+
+	go strfs9p.CreateServer(tree).Serve(listener)
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	defer conn.Close()
+
+	var c *client = &client{t:t, conn:conn}
+
+	{
+		var body []byte = c.expect(rversion, 100, uint32(65536), "9P2000.L")
+		if expected, actual := "9P2000.L", string(body[6:]); expected != actual {
+			t.Errorf("The actual version was not what was expected: expected %q, actually %q", expected, actual)
+		}
+	}
+
+	c.expect(rattach, 104, uint32(1), ^uint32(0), "user", "", uint32(0))
+
+	{
+		var body []byte = c.expect(rlerror, 110, uint32(1), uint32(2), uint16(1), "missing.txt")
+		if expected, actual := uint32(2), binary.LittleEndian.Uint32(body); expected != actual {
+			t.Errorf("The actual errno was not what was expected: expected %d (ENOENT), actually %d", expected, actual)
+		}
+	}
+
+	c.expect(rwalk, 110, uint32(1), uint32(2), uint16(1), "css")
+	c.expect(rlopen, 12, uint32(2), uint32(0))
+	{
+		var body []byte = c.expect(rreaddir, 40, uint32(2), uint64(0), uint32(8192))
+		var entries []byte = body[4:]
+
+		if expected, actual := uint8(8), entries[13+8]; expected != actual {
+			t.Errorf("The actual directory-entry type was not what was expected: expected %d, actually %d", expected, actual)
+		}
+		var namelen int = int(binary.LittleEndian.Uint16(entries[13+8+1:]))
+		if expected, actual := "style.css", string(entries[13+8+1+2:][:namelen]); expected != actual {
+			t.Errorf("The actual directory-entry name was not what was expected: expected %q, actually %q", expected, actual)
+		}
+
+		var next uint64 = binary.LittleEndian.Uint64(entries[13:])
+		body = c.expect(rreaddir, 40, uint32(2), next, uint32(8192))
+		if expected, actual := uint32(0), binary.LittleEndian.Uint32(body); expected != actual {
+			t.Errorf("Expected no more directory-entries: expected count %d, actually %d", expected, actual)
+		}
+	}
+
+	c.expect(rwalk, 110, uint32(1), uint32(3), uint16(1), "index.html")
+	{
+		var body []byte = c.expect(rgetattr, 24, uint32(3), uint64(0x7ff))
+		if expected, actual := uint32(0100644), binary.LittleEndian.Uint32(body[8+13:]); expected != actual {
+			t.Errorf("The actual mode was not what was expected: expected 0%o, actually 0%o", expected, actual)
+		}
+		if expected, actual := uint64(54), binary.LittleEndian.Uint64(body[8+13+4+4+4+8+8:]); expected != actual {
+			t.Errorf("The actual size was not what was expected: expected %d, actually %d", expected, actual)
+		}
+	}
+
+	c.expect(rsetattr, 26, uint32(3), uint32(0x1|0x8), uint32(0600), uint32(0), uint32(0), uint64(15), uint64(0), uint64(0), uint64(0), uint64(0))
+	expectFile(t, tree, "index.html", "<!DOCTYPE html>")
+	if fileinfo, err := fs.Stat(tree, "index.html"); nil != err || fs.FileMode(0600) != fileinfo.Mode() {
+		t.Errorf("Expected the mode to have been changed to 0600.")
+	}
+
+	c.expect(rwalk, 110, uint32(1), uint32(4), uint16(1), "css")
+	c.expect(rlcreate, 14, uint32(4), "print.css", uint32(02), uint32(0644), uint32(0))
+	c.expect(rwrite, 118, uint32(4), uint64(0), uint32(4), []byte("body"))
+	c.expect(rfsync, 50, uint32(4), uint32(0))
+	expectFile(t, tree, "css/print.css", "body")
+
+	c.expect(rwalk, 110, uint32(1), uint32(5), uint16(0))
+	c.expect(rrenameat, 74, uint32(5), "css", uint32(5), "styles")
+	expectFile(t, tree, "styles/print.css", "body")
+	expectFile(t, tree, "styles/style.css", "body{color:red}")
+
+	c.expect(rwalk, 110, uint32(1), uint32(6), uint16(1), "styles")
+	{
+		var body []byte = c.expect(rlerror, 76, uint32(6), "style.css", uint32(0x200))
+		if expected, actual := uint32(20), binary.LittleEndian.Uint32(body); expected != actual {
+			t.Errorf("The actual errno was not what was expected: expected %d (ENOTDIR), actually %d", expected, actual)
+		}
+	}
+	c.expect(runlinkat, 76, uint32(6), "style.css", uint32(0))
+	if _, err := fs.Stat(tree, "styles/style.css"); nil == err {
+		t.Errorf("Expected the unlinked file to not exist anymore.")
+	}
+
+	{
+		var body []byte = c.expect(rstatfs, 8, uint32(1))
+		if expected, actual := uint32(0x01021997), binary.LittleEndian.Uint32(body); expected != actual {
+			t.Errorf("The actual file-system type was not what was expected: expected 0x%x, actually 0x%x", expected, actual)
+		}
+	}
+}
+
+func TestServer_9P2000L_readOnly(t *testing.T) {
+
+	var c *client = dial(t, exampleFS())
+
+	c.expect(rversion, 100, uint32(8192), "9P2000.L")
+	c.expect(rattach, 104, uint32(1), ^uint32(0), "user", "", uint32(0))
+	c.expect(rwalk, 110, uint32(1), uint32(2), uint16(1), "index.html")
+
+	{
+		var body []byte = c.expect(rlerror, 12, uint32(2), uint32(1))
+		if expected, actual := uint32(30), binary.LittleEndian.Uint32(body); expected != actual {
+			t.Errorf("The actual errno was not what was expected: expected %d (EROFS), actually %d", expected, actual)
+		}
+	}
+
+	c.expect(rlopen, 12, uint32(2), uint32(0))
+	{
+		var body []byte = c.expect(rread, 116, uint32(2), uint64(0), uint32(15))
+		if expected, actual := "<!DOCTYPE html>", string(body[4:]); expected != actual {
+			t.Errorf("The actual data read was not what was expected: expected %q, actually %q", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package strfs9p
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+
+	"codeberg.org/reiver/go-strfs"
+)
+
+// internalHandler handles a T-message (whose body is in 'request'), and encodes the body of the R-message into 'response'.
+//
+// If an error is returned, then a Rerror (for 9P2000) or Rlerror (for 9P2000.L) is sent instead.
+type internalHandler func(receiver *internalSession, request *internalDecoder, response *internalEncoder) error
+
+// handlers9P2000 are the messages understood when the 9P2000 dialect was agreed to (with Tversion).
+var handlers9P2000 map[uint8]internalHandler = map[uint8]internalHandler{
+	msgTversion: (*internalSession).version,
+	msgTauth:    (*internalSession).auth,
+	msgTattach:  (*internalSession).attach,
+	msgTflush:   (*internalSession).flush,
+	msgTwalk:    (*internalSession).walk,
+	msgTopen:    (*internalSession).open,
+	msgTcreate:  (*internalSession).create,
+	msgTread:    (*internalSession).read,
+	msgTwrite:   (*internalSession).write,
+	msgTclunk:   (*internalSession).clunk,
+	msgTremove:  (*internalSession).remove,
+	msgTstat:    (*internalSession).stat,
+	msgTwstat:   (*internalSession).wstat,
+}
+
+// handlers9P2000L are the messages understood when the 9P2000.L dialect was agreed to (with Tversion).
+var handlers9P2000L map[uint8]internalHandler = map[uint8]internalHandler{
+	msgTversion:   (*internalSession).version,
+	msgTauth:      (*internalSession).auth,
+	msgTattach:    (*internalSession).attach,
+	msgTflush:     (*internalSession).flush,
+	msgTwalk:      (*internalSession).walk,
+	msgTread:      (*internalSession).read,
+	msgTwrite:     (*internalSession).write,
+	msgTclunk:     (*internalSession).clunk,
+	msgTremove:    (*internalSession).remove,
+	msgTstatfs:    (*internalSession).statfs,
+	msgTlopen:     (*internalSession).lopen,
+	msgTlcreate:   (*internalSession).lcreate,
+	msgTrename:    (*internalSession).rename,
+	msgTgetattr:   (*internalSession).getattr,
+	msgTsetattr:   (*internalSession).setattr,
+	msgTxattrwalk: (*internalSession).notSupported,
+	msgTreaddir:   (*internalSession).readdir,
+	msgTfsync:     (*internalSession).fsync,
+	msgTmkdir:     (*internalSession).notSupported,
+	msgTrenameat:  (*internalSession).renameat,
+	msgTunlinkat:  (*internalSession).unlinkat,
+}
+
+// internalSession is the state of a single 9P connection.
+type internalSession struct {
+	fsys fs.FS
+	tree *strfs.Tree
+	conn io.ReadWriter
+
+	msize uint32
+	dotL bool
+	fids map[uint32]*internalFid
+}
+
+// errno returns the Linux error-number (for a Rlerror) for 'err'.
+func errno(err error) uint32 {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return 2 // ENOENT
+	case errors.Is(err, errUnknownFid), errors.Is(err, errFidNotOpen):
+		return 9 // EBADF
+	case errors.Is(err, fs.ErrPermission):
+		return 13 // EACCES
+	case errors.Is(err, fs.ErrExist):
+		return 17 // EEXIST
+	case errors.Is(err, errNotDirectory):
+		return 20 // ENOTDIR
+	case errors.Is(err, errIsDirectory):
+		return 21 // EISDIR
+	case errors.Is(err, fs.ErrInvalid), errors.Is(err, errFidInUse), errors.Is(err, errFidOpen), errors.Is(err, errBadOffset), errors.Is(err, errTooManyNames):
+		return 22 // EINVAL
+	case errors.Is(err, errFileTooBig):
+		return 27 // EFBIG
+	case errors.Is(err, errReadOnly):
+		return 30 // EROFS
+	case errors.Is(err, errUnknownMessage):
+		return 38 // ENOSYS
+	case errors.Is(err, errDirectoryNotEmpty):
+		return 39 // ENOTEMPTY
+	case errors.Is(err, errBadMessage):
+		return 71 // EPROTO
+	case errors.Is(err, errNotSupported), errors.Is(err, errAuthNotRequired):
+		return 95 // EOPNOTSUPP
+	default:
+		return 5 // EIO
+	}
+}
+
+// fid returns the fid (that the client already made) numbered 'number'.
+func (receiver *internalSession) fid(number uint32) (*internalFid, error) {
+	f, found := receiver.fids[number]
+	if !found {
+		return nil, errUnknownFid
+	}
+
+	return f, nil
+}
+
+// handle handles a single T-message, and returns the R-message (without its size[4]) to send back.
+func (receiver *internalSession) handle(messageType uint8, tag uint16, body []byte) []byte {
+	var handlers map[uint8]internalHandler = handlers9P2000
+	if receiver.dotL {
+		handlers = handlers9P2000L
+	}
+
+	var response internalEncoder
+	response.uint8(messageType + 1)
+	response.uint16(tag)
+
+	var err error = errUnknownMessage
+	if handler, found := handlers[messageType]; found {
+		err = handler(receiver, &internalDecoder{data:body}, &response)
+	}
+	if nil == err {
+		return response.data
+	}
+
+	var rerror internalEncoder
+	if receiver.dotL {
+		rerror.uint8(msgRlerror)
+		rerror.uint16(tag)
+		rerror.uint32(errno(err))
+	} else {
+		rerror.uint8(msgRerror)
+		rerror.uint16(tag)
+		rerror.string(err.Error())
+	}
+	return rerror.data
+}
+
+// iounit returns the most data that can be read (or written) with a single Tread (or Twrite).
+func (receiver *internalSession) iounit() uint32 {
+	return receiver.msize - ioHeaderSize
+}
+
+// notSupported handles the messages that a strfs9p.Server understands, but does not support.
+func (receiver *internalSession) notSupported(request *internalDecoder, response *internalEncoder) error {
+	return errNotSupported
+}
+
+// serve reads T-messages from the connection, and writes back R-messages, until the connection is closed.
+func (receiver *internalSession) serve() error {
+	defer receiver.releaseAll()
+
+	var sizeBytes [4]byte
+	for {
+		if _, err := io.ReadFull(receiver.conn, sizeBytes[:]); nil != err {
+			if io.EOF == err {
+				return nil
+			}
+			return err
+		}
+
+		var size uint32 = binary.LittleEndian.Uint32(sizeBytes[:])
+		if size < headerSize {
+			return errBadMessage
+		}
+		if receiver.msize < size {
+			return errMessageTooBig
+		}
+
+		var message []byte = make([]byte, size-4)
+		if _, err := io.ReadFull(receiver.conn, message); nil != err {
+			return err
+		}
+
+		var response []byte = receiver.handle(message[0], binary.LittleEndian.Uint16(message[1:3]), message[3:])
+
+		binary.LittleEndian.PutUint32(sizeBytes[:], uint32(4+len(response)))
+		if _, err := receiver.conn.Write(append(sizeBytes[:], response...)); nil != err {
+			return err
+		}
+	}
+}
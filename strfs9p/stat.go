@@ -0,0 +1,71 @@
+package strfs9p
+
+import (
+	"hash/fnv"
+	"io/fs"
+	"path"
+)
+
+// owner is the user (and group) that every file (and directory) is reported as belonging to.
+const owner = "strfs"
+
+// qidOf returns the qid of the file (or directory) at 'name'.
+//
+// The qid-path is a hash of 'name', so that it is the same each time the same file is walked to.
+// The qid-version of a file changes whenever its modification-time (or size) does.
+func qidOf(name string, fileinfo fs.FileInfo) internalQid {
+	var hash = fnv.New64a()
+	hash.Write([]byte(name))
+
+	if fileinfo.IsDir() {
+		return internalQid{
+			Type:qtDir,
+			Path:hash.Sum64(),
+		}
+	}
+
+	return internalQid{
+		Type:qtFile,
+		Version:uint32(fileinfo.ModTime().UnixNano()) ^ uint32(fileinfo.Size()),
+		Path:hash.Sum64(),
+	}
+}
+
+// statOf returns the (9P2000) stat of the file (or directory) at 'name' — including its leading size[2].
+func statOf(name string, fileinfo fs.FileInfo) []byte {
+	var mode uint32 = uint32(fileinfo.Mode().Perm())
+	var length uint64 = uint64(fileinfo.Size())
+	if fileinfo.IsDir() {
+		mode |= dmDir
+		length = 0
+	}
+
+	var base string = path.Base(name)
+	if "." == name {
+		base = "/"
+	}
+
+	var mtime uint32 = uint32(fileinfo.ModTime().Unix())
+	if fileinfo.ModTime().IsZero() {
+		mtime = 0
+	}
+
+	var stat internalEncoder
+	stat.uint16(0) // (the size, which is filled in below)
+	stat.uint16(0) // type
+	stat.uint32(0) // dev
+	stat.qid(qidOf(name, fileinfo))
+	stat.uint32(mode)
+	stat.uint32(mtime) // atime
+	stat.uint32(mtime)
+	stat.uint64(length)
+	stat.string(base)
+	stat.string(owner) // uid
+	stat.string(owner) // gid
+	stat.string(owner) // muid
+
+	var size uint16 = uint16(len(stat.data) - 2)
+	stat.data[0] = byte(size)
+	stat.data[1] = byte(size >> 8)
+	return stat.data
+}
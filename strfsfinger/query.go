@@ -0,0 +1,39 @@
+package strfsfinger
+
+import (
+	"strings"
+)
+
+// internalQuery is a Finger query (RFC 1288) — for example, "alice", "/W alice", "/W", "", or "alice@example.com".
+type internalQuery struct {
+	verbose bool
+	user    string
+	host    string
+}
+
+// parseQuery parses the Finger query 'line' (without the "\r\n" at the end of it).
+//
+// A query that is "/W", or that starts with "/W" followed by a space, is verbose.
+// (So, "/Walice" is NOT a verbose query for "alice" — RFC 1288 requires the space.)
+// Everything after the first "@" (if there is one) is the host — i.e., the query asks for the query to be forwarded.
+func parseQuery(line string) internalQuery {
+	var query internalQuery
+
+	line = strings.TrimSpace(line)
+	switch {
+	case strings.EqualFold("/W", line):
+		query.verbose = true
+		line = ""
+	case 3 <= len(line) && strings.EqualFold("/W", line[:2]) && ' ' == line[2]:
+		query.verbose = true
+		line = strings.TrimLeft(line[3:], " ")
+	}
+
+	query.user = line
+	if index := strings.IndexByte(line, '@'); 0 <= index {
+		query.user = line[:index]
+		query.host = line[index+1:]
+	}
+
+	return query
+}
@@ -0,0 +1,150 @@
+package strfsfinger
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// extension is the file-extension of a (plan) file for a user.
+const extension = ".fngr"
+
+// crlf returns 's' with all its line-endings as "\r\n" (including at the end of it, if 's' is not empty).
+func crlf(s string) string {
+	if "" == s {
+		return s
+	}
+
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+
+	return strings.ReplaceAll(s, "\n", "\r\n")
+}
+
+// validUser returns whether 'user' could be the login-name of a user — i.e., whether it is not empty,
+// and does not have any characters that could escape the directory of users (or that are not printable).
+func validUser(user string) bool {
+	if "" == user || strings.HasPrefix(user, ".") {
+		return false
+	}
+
+	for _, r := range user {
+		if r <= ' ' || 0x7F == r || '/' == r || '\\' == r {
+			return false
+		}
+	}
+
+	return true
+}
+
+// respond returns the response to 'query' (with "\n" line-endings).
+func (receiver Server) respond(query internalQuery) string {
+	if "" != query.host {
+		return "finger: forwarding service denied.\n"
+	}
+
+	if "" == query.user {
+		return receiver.list(query.verbose)
+	}
+
+	return receiver.user(query.user, query.verbose)
+}
+
+// list returns a list of the users — one per line.
+//
+// If 'verbose' is true, then each user is followed by the first line of their file.
+func (receiver Server) list(verbose bool) string {
+	entries, err := fs.ReadDir(receiver.fsys, receiver.directory)
+	if nil != err && !errors.Is(err, fs.ErrNotExist) {
+		return "finger: temporary failure.\n"
+	}
+
+	var users []string
+	for _, entry := range entries {
+		var user string = strings.TrimSuffix(entry.Name(), extension)
+		if entry.IsDir() || user == entry.Name() || !validUser(user) {
+			continue
+		}
+
+		users = append(users, user)
+	}
+
+	var width int
+	for _, user := range users {
+		if width < len(user) {
+			width = len(user)
+		}
+	}
+
+	var builder strings.Builder
+	for _, user := range users {
+		if !verbose {
+			builder.WriteString(user)
+			builder.WriteString("\n")
+			continue
+		}
+
+		data, err := fs.ReadFile(receiver.fsys, path.Join(receiver.directory, user+extension))
+		if nil != err {
+			continue
+		}
+
+		var line string = strings.TrimRight(strings.SplitN(string(data), "\n", 2)[0], "\r")
+
+		builder.WriteString(user)
+		builder.WriteString(strings.Repeat(" ", width-len(user)+2))
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// user returns the file of the user 'user'.
+//
+// If 'verbose' is true, then the file is preceded by the user's login-name, and (if it is known) when the file was last modified.
+func (receiver Server) user(user string, verbose bool) string {
+	// An invalid login-name is NOT echoed back — it could have control characters in it (such as terminal escape sequences).
+	if !validUser(user) {
+		return "finger: no such user.\n"
+	}
+
+	var notFound string = "finger: " + user + ": no such user.\n"
+
+	var name string = path.Join(receiver.directory, user+extension)
+
+	fileinfo, err := fs.Stat(receiver.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) || (nil == err && fileinfo.IsDir()) {
+		return notFound
+	}
+	if nil != err {
+		return "finger: temporary failure.\n"
+	}
+
+	data, err := fs.ReadFile(receiver.fsys, name)
+	if nil != err {
+		return "finger: temporary failure.\n"
+	}
+
+	if !verbose {
+		return string(data)
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Login: ")
+	builder.WriteString(user)
+	builder.WriteString("\n")
+	if modtime := fileinfo.ModTime(); !modtime.IsZero() {
+		builder.WriteString("Last modified: ")
+		builder.WriteString(modtime.UTC().Format(time.RFC1123))
+		builder.WriteString("\n")
+	}
+	builder.WriteString("Plan:\n")
+	builder.Write(data)
+
+	return builder.String()
+}
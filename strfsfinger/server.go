@@ -0,0 +1,124 @@
+// Package strfsfinger lets the files in a strfs file-system be served over the Finger protocol (RFC 1288).
+//
+// Each user is a ".fngr" (plan) file in a directory of the file-system — for example, the user "alice" could be "users/alice.fngr".
+//
+// Example usage:
+//
+//	listener, err := net.Listen("tcp", ":79")
+//
+//	// ...
+//
+//	err = strfsfinger.CreateServer(fsys, "users").Serve(listener)
+package strfsfinger
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"net"
+	"strings"
+	"time"
+
+	"codeberg.org/reiver/go-strfs/internal/adapter"
+)
+
+// maxRequestSize is the maximum size of a Finger query, not including the "\r\n" at the end of it.
+const maxRequestSize = 512
+
+// maxDiscardSize is the most that is read from a client (including a query that is too long).
+const maxDiscardSize = 64 * 1024
+
+// timeout is how long a client has to send its query, and read the response.
+const timeout = 30 * time.Second
+
+// Server serves the ".fngr" files in a directory of a file-system over Finger.
+//
+// A query for a user (for example, "alice") is answered with the content of the user's file (for example, "users/alice.fngr").
+// A verbose query for a user (for example, "/W alice") is answered with the user's login-name, (if it is known) when the file was last modified, and then the content of the file.
+//
+// An empty query is answered with a list of the users (one per line).
+// A verbose empty query (i.e., "/W") is answered with a list of the users, each with the first line of their file.
+//
+// Queries that ask for the query to be forwarded to another host (for example, "alice@example.com") are refused,
+// and so are queries longer than 512 bytes.
+//
+// Line-endings in the response are always "\r\n".
+type Server struct {
+	fsys fs.FS
+	directory string
+}
+
+// CreateServer returns a strfsfinger.Server that serves the ".fngr" files in the directory 'directory' of 'fsys'.
+//
+// Use "." for 'directory' to serve the ".fngr" files at the root of 'fsys'.
+func CreateServer(fsys fs.FS, directory string) Server {
+	return Server{
+		fsys:fsys,
+		directory:adapter.CleanName(directory),
+	}
+}
+
+// Serve accepts connections from 'listener', and serves each of them (in its own goroutine) with ServeConn.
+//
+// Serve returns when 'listener' returns an error (for example, because it was closed).
+func (receiver Server) Serve(listener net.Listener) error {
+	if nil == listener {
+		return adapter.ErrNilListener
+	}
+	if nil == receiver.fsys {
+		return adapter.ErrNilFS
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if nil != err {
+			return err
+		}
+
+		go receiver.ServeConn(conn)
+	}
+}
+
+// ServeConn reads a single Finger query from 'conn', and writes the response to it.
+//
+// ServeConn closes 'conn' before it returns.
+func (receiver Server) ServeConn(conn io.ReadWriteCloser) error {
+	if nil == conn {
+		return adapter.ErrNilConn
+	}
+	defer conn.Close()
+
+	if nil == receiver.fsys {
+		return adapter.ErrNilFS
+	}
+
+	if deadliner, casted := conn.(interface{ SetDeadline(time.Time) error }); casted {
+		deadliner.SetDeadline(time.Now().Add(timeout))
+	}
+
+	var reader *bufio.Reader = bufio.NewReaderSize(io.LimitReader(conn, maxDiscardSize), maxRequestSize+2)
+
+	data, err := reader.ReadSlice('\n')
+	var line string = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+
+	// The rest of a query that is too long is read (and thrown away), so that the client gets the response
+	// (rather than the connection being reset because it was closed with data that had not been read).
+	var tooLong bool = maxRequestSize < len(line)
+	for bufio.ErrBufferFull == err {
+		tooLong = true
+		_, err = reader.ReadSlice('\n')
+	}
+	if nil != err && io.EOF != err {
+		return err
+	}
+
+	var response string
+	if tooLong {
+		response = "finger: query too long.\n"
+	} else {
+		response = receiver.respond(parseQuery(line))
+	}
+
+	_, err = io.WriteString(conn, crlf(response))
+	return err
+}
@@ -0,0 +1,134 @@
+package strfsfinger_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfsfinger"
+
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"testing"
+)
+
+func exampleFS() strfs.FS {
+	var mtime time.Time = time.Date(2022, 12, 12, 10, 30, 14, 0, time.UTC)
+
+	return strfs.FS{
+		"users/alice.fngr": strfs.RegularFile{FileContent: strfs.CreateContent("Hello world! 😈"+"\n"+"Working on strfs."+"\n"), FileModTime: mtime},
+		"users/bob.fngr":   strfs.RegularFile{FileContent: strfs.CreateContent("once twice thrice")},
+		"users/notes.txt":  strfs.RegularFile{FileContent: strfs.CreateContent("not a plan")},
+		"users/team/x.fngr": strfs.RegularFile{FileContent: strfs.CreateContent("not a user")},
+		"secret.fngr":      strfs.RegularFile{FileContent: strfs.CreateContent("do not serve")},
+	}
+}
+
+func TestServer(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	defer listener.Close()
+
+	go strfsfinger.CreateServer(exampleFS(), "users").Serve(listener)
+
+	tests := []struct{
+		Query    string
+		Expected string
+	}{
+		{
+			Query:    "alice\r\n",
+			Expected: "Hello world! 😈\r\nWorking on strfs.\r\n",
+		},
+		{
+			Query:    "bob\r\n",
+			Expected: "once twice thrice\r\n",
+		},
+		{
+			Query:    "/W alice\r\n",
+			Expected: "Login: alice\r\n"+
+				"Last modified: Mon, 12 Dec 2022 10:30:14 UTC\r\n"+
+				"Plan:\r\n"+
+				"Hello world! 😈\r\n"+
+				"Working on strfs.\r\n",
+		},
+		{
+			Query:    "/W bob\r\n",
+			Expected: "Login: bob\r\nPlan:\r\nonce twice thrice\r\n",
+		},
+		{
+			Query:    "\r\n",
+			Expected: "alice\r\nbob\r\n",
+		},
+		{
+			Query:    "/W\r\n",
+			Expected: "alice  Hello world! 😈\r\n"+
+				"bob    once twice thrice\r\n",
+		},
+		{
+			Query:    "carol\r\n",
+			Expected: "finger: carol: no such user.\r\n",
+		},
+		{
+			Query:    "team\r\n",
+			Expected: "finger: team: no such user.\r\n",
+		},
+		{
+			Query:    "../secret\r\n",
+			Expected: "finger: no such user.\r\n",
+		},
+		{
+			Query:    "\x1b[2J\x1b]0;pwned\x07\r\n",
+			Expected: "finger: no such user.\r\n",
+		},
+		{
+			Query:    "/Walice\r\n",
+			Expected: "finger: no such user.\r\n",
+		},
+		{
+			Query:    "/W   bob\r\n",
+			Expected: "Login: bob\r\n"+
+				"Plan:\r\n"+
+				"once twice thrice\r\n",
+		},
+		{
+			Query:    "alice@example.com\r\n",
+			Expected: "finger: forwarding service denied.\r\n",
+		},
+		{
+			Query:    strings.Repeat("x", 513) + "\r\n",
+			Expected: "finger: query too long.\r\n",
+		},
+	}
+
+	for testNumber, test := range tests {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		if _, err := io.WriteString(conn, test.Query); nil != err {
+			conn.Close()
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		data, err := io.ReadAll(conn)
+		conn.Close()
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, string(data); expected != actual {
+			t.Errorf("For test #%d, the actual response was not what was expected.", testNumber)
+			t.Logf("QUERY: %q", test.Query)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package strfsgemini
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// mediaType returns the media-type (i.e., MIME type) of the file named 'name', with the content 'data'.
+//
+// Files with a ".gmi", ".gemini", or ".gmni" extension are "text/gemini".
+// Files with a ".fngr" extension (finger plans) are "text/plain".
+// Otherwise, the media-type is detected from the extension, or else from the content.
+func mediaType(name string, data []byte) string {
+	var extension string = strings.ToLower(path.Ext(name))
+
+	switch extension {
+	case ".gmi", ".gemini", ".gmni":
+		return "text/gemini; charset=utf-8"
+	case ".fngr":
+		return "text/plain; charset=utf-8"
+	}
+
+	if mediatype := mime.TypeByExtension(extension); "" != mediatype {
+		return mediatype
+	}
+
+	return http.DetectContentType(data)
+}
@@ -0,0 +1,114 @@
+package strfsgemini
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"codeberg.org/reiver/go-strfs/internal/adapter"
+	"codeberg.org/reiver/go-strfs/strfslisting"
+)
+
+// internalResponse is a Gemini response — a status-code, a meta (a media-type, an error message, or a redirect URL), and (for a success) a body.
+type internalResponse struct {
+	status int
+	meta   string
+	body   []byte
+}
+
+var (
+	badRequest          = internalResponse{status:59, meta:"Bad request"}
+	notFound            = internalResponse{status:51, meta:"Not found"}
+	proxyRequestRefused = internalResponse{status:53, meta:"Proxy request refused"}
+	temporaryFailure    = internalResponse{status:40, meta:"Temporary failure"}
+)
+
+// WriteTo writes the response header (i.e., "<status> <meta>\r\n"), and then the body, to 'writer'.
+func (receiver internalResponse) WriteTo(writer io.Writer) (int64, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteString(strconv.Itoa(receiver.status))
+	buffer.WriteString(" ")
+	buffer.WriteString(receiver.meta)
+	buffer.WriteString("\r\n")
+	buffer.Write(receiver.body)
+
+	return buffer.WriteTo(writer)
+}
+
+// respond returns the response to the Gemini request 'line' (a URL, without the "\r\n" at the end of it).
+func (receiver Server) respond(line string) internalResponse {
+	if maxRequestSize < len(line) {
+		return badRequest
+	}
+
+	requesturl, err := url.Parse(line)
+	if nil != err || !requesturl.IsAbs() || "" == requesturl.Host {
+		return badRequest
+	}
+	if "gemini" != strings.ToLower(requesturl.Scheme) {
+		return proxyRequestRefused
+	}
+	if nil != requesturl.User || "" != requesturl.Fragment {
+		return badRequest
+	}
+
+	var name string = adapter.CleanName(requesturl.Path)
+
+	fileinfo, err := fs.Stat(receiver.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		return notFound
+	}
+	if nil != err {
+		return temporaryFailure
+	}
+
+	if !fileinfo.IsDir() {
+		return receiver.file(name)
+	}
+
+	if "." != name && !strings.HasSuffix(requesturl.Path, "/") {
+		// (The name is escaped, so that a name such as "a b" or "x?y" is still a valid, relative, URL.)
+		return internalResponse{status:31, meta:(&url.URL{Path:path.Base(requesturl.Path) + "/"}).String()}
+	}
+
+	var index string = path.Join(name, "index.gmi")
+	if _, err := fs.Stat(receiver.fsys, index); nil == err {
+		return receiver.file(index)
+	}
+
+	return receiver.listing(name)
+}
+
+// file returns the response for the file named 'name'.
+func (receiver Server) file(name string) internalResponse {
+	data, err := fs.ReadFile(receiver.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return notFound
+	}
+	if nil != err {
+		return temporaryFailure
+	}
+
+	return internalResponse{status:20, meta:mediaType(name, data), body:data}
+}
+
+// listing returns the response for the directory listing of the directory named 'name'.
+func (receiver Server) listing(name string) internalResponse {
+	listing, err := strfslisting.ReadListing(receiver.fsys, name)
+	if nil != err {
+		return temporaryFailure
+	}
+
+	var buffer bytes.Buffer
+	if err := strfslisting.Gemtext.Render(&buffer, listing); nil != err {
+		return temporaryFailure
+	}
+
+	return internalResponse{status:20, meta:strfslisting.Gemtext.ContentType(), body:buffer.Bytes()}
+}
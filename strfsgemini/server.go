@@ -0,0 +1,142 @@
+// Package strfsgemini lets a strfs file-system be served over the Gemini protocol.
+//
+// Any fs.FS (such as a strfs.FS, a strfs.Snapshot, or a strfs.Tree) can be served.
+//
+// Example usage:
+//
+//	certificate, err := tls.LoadX509KeyPair("cert.pem", "key.pem")
+//
+//	// ...
+//
+//	listener, err := net.Listen("tcp", ":1965")
+//
+//	// ...
+//
+//	err = strfsgemini.CreateServer(fsys, certificate).Serve(listener)
+package strfsgemini
+
+import (
+	"bufio"
+	"crypto/tls"
+	"io"
+	"io/fs"
+	"net"
+	"strings"
+	"time"
+
+	"codeberg.org/reiver/go-strfs/internal/adapter"
+)
+
+// maxRequestSize is the maximum size of a Gemini request, not including the "\r\n" at the end of it.
+const maxRequestSize = 1024
+
+// maxDiscardSize is the most that is read from a client (including a request that is too long).
+const maxDiscardSize = 64 * 1024
+
+// timeout is how long a client has to send its request, and read the response.
+const timeout = 30 * time.Second
+
+// Server serves a file-system over Gemini.
+//
+// A file is served with a media-type from its extension (".gmi", ".gemini", and ".gmni" files are "text/gemini"),
+// or else from its content.
+//
+// A directory with an "index.gmi" file is served as that file.
+// Any other directory is served as a gemtext directory listing (see strfslisting.GemtextRenderer).
+// A directory requested without a "/" at the end of its path is redirected to the path with a "/" at the end of it.
+//
+// The status-codes used are:
+// 20 (success),
+// 31 (permanent redirect),
+// 40 (temporary failure),
+// 51 (not found),
+// 53 (proxy request refused — for a URL whose scheme is not "gemini"),
+// and
+// 59 (bad request).
+type Server struct {
+	fsys fs.FS
+	config *tls.Config
+}
+
+// CreateServer returns a strfsgemini.Server that serves 'fsys', using 'certificate' for TLS.
+func CreateServer(fsys fs.FS, certificate tls.Certificate) Server {
+	return Server{
+		fsys:fsys,
+		config:&tls.Config{
+			Certificates:[]tls.Certificate{certificate},
+			MinVersion:tls.VersionTLS12,
+		},
+	}
+}
+
+// Serve accepts connections from 'listener', and serves each of them (in its own goroutine) over TLS.
+//
+// Serve returns when 'listener' returns an error (for example, because it was closed).
+func (receiver Server) Serve(listener net.Listener) error {
+	if nil == listener {
+		return adapter.ErrNilListener
+	}
+	if nil == receiver.fsys {
+		return adapter.ErrNilFS
+	}
+
+	listener = tls.NewListener(listener, receiver.config)
+
+	for {
+		conn, err := listener.Accept()
+		if nil != err {
+			return err
+		}
+
+		go receiver.ServeConn(conn)
+	}
+}
+
+// ServeConn reads a single Gemini request from 'conn', and writes the response to it.
+//
+// ServeConn does not do TLS itself — 'conn' is expected to already be secured (for example, a *tls.Conn).
+// (Serve takes care of this.)
+//
+// ServeConn closes 'conn' before it returns.
+func (receiver Server) ServeConn(conn io.ReadWriteCloser) error {
+	if nil == conn {
+		return adapter.ErrNilConn
+	}
+	defer conn.Close()
+
+	if nil == receiver.fsys {
+		return adapter.ErrNilFS
+	}
+
+	if deadliner, casted := conn.(interface{ SetDeadline(time.Time) error }); casted {
+		deadliner.SetDeadline(time.Now().Add(timeout))
+	}
+
+	var reader *bufio.Reader = bufio.NewReaderSize(io.LimitReader(conn, maxDiscardSize), maxRequestSize+2)
+
+	data, err := reader.ReadSlice('\n')
+	var line string = string(data)
+
+	// The rest of a request that is too long is read (and thrown away), so that the client gets the response
+	// (rather than the connection being reset because it was closed with data that had not been read).
+	var tooLong bool
+	for bufio.ErrBufferFull == err {
+		tooLong = true
+		_, err = reader.ReadSlice('\n')
+	}
+	if nil != err && io.EOF != err {
+		return err
+	}
+
+	var response internalResponse
+	switch {
+	case tooLong, !strings.HasSuffix(line, "\n"):
+		response = badRequest
+	default:
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		response = receiver.respond(line)
+	}
+
+	_, err = response.WriteTo(conn)
+	return err
+}
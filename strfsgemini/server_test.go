@@ -0,0 +1,171 @@
+package strfsgemini_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfsgemini"
+
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	"testing"
+)
+
+func exampleFS() strfs.FS {
+	return strfs.FS{
+		"index.gmi":            strfs.RegularFile{FileContent: strfs.CreateContent("# Hello world!"+"\n")},
+		"css/style.css":        strfs.RegularFile{FileContent: strfs.CreateContent("body{color:red}")},
+		"gmni/file3.gmni":      strfs.RegularFile{FileContent: strfs.CreateContent("once twice thrice"+"\n")},
+		"gmni/fngr/file4.fngr": strfs.RegularFile{FileContent: strfs.CreateContent("Hello world! 😈"+"\n")},
+		"a b/file5.gmi":        strfs.RegularFile{FileContent: strfs.CreateContent("space"+"\n")},
+		"x?y/file6.gmi":        strfs.RegularFile{FileContent: strfs.CreateContent("question"+"\n")},
+		"c:d/file7.gmi":        strfs.RegularFile{FileContent: strfs.CreateContent("colon"+"\n")},
+	}
+}
+
+// certificate returns a self-signed certificate for "localhost", just for testing.
+func certificate(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var template x509.Certificate = x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+}
+
+func TestServer(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	defer listener.Close()
+
+	go strfsgemini.CreateServer(exampleFS(), certificate(t)).Serve(listener)
+
+	tests := []struct{
+		Request  string
+		Expected string
+	}{
+		{
+			Request:  "gemini://localhost/",
+			Expected: "20 text/gemini; charset=utf-8\r\n"+"# Hello world!"+"\n",
+		},
+		{
+			Request:  "gemini://localhost",
+			Expected: "20 text/gemini; charset=utf-8\r\n"+"# Hello world!"+"\n",
+		},
+		{
+			Request:  "gemini://localhost/gmni",
+			Expected: "31 gmni/\r\n",
+		},
+		{
+			Request:  "gemini://localhost/a%20b",
+			Expected: "31 a%20b/\r\n",
+		},
+		{
+			Request:  "gemini://localhost/x%3Fy",
+			Expected: "31 x%3Fy/\r\n",
+		},
+		{
+			Request:  "gemini://localhost/c:d",
+			Expected: "31 ./c:d/\r\n",
+		},
+		{
+			Request:  "gemini://localhost/gmni/",
+			Expected: "20 text/gemini; charset=utf-8\r\n"+
+				"# Index of /gmni/"+"\n"+
+				""+"\n"+
+				"=> ../ ../"+"\n"+
+				"=> fngr/ fngr/"+"\n"+
+				"=> file3.gmni file3.gmni (18 B)"+"\n",
+		},
+		{
+			Request:  "gemini://localhost/gmni/file3.gmni",
+			Expected: "20 text/gemini; charset=utf-8\r\n"+"once twice thrice"+"\n",
+		},
+		{
+			Request:  "gemini://localhost/gmni/fngr/file4.fngr",
+			Expected: "20 text/plain; charset=utf-8\r\n"+"Hello world! 😈"+"\n",
+		},
+		{
+			Request:  "gemini://localhost/gmni/../css/style.css",
+			Expected: "20 text/css; charset=utf-8\r\n"+"body{color:red}",
+		},
+		{
+			Request:  "gemini://localhost/missing.gmi",
+			Expected: "51 Not found\r\n",
+		},
+		{
+			Request:  "https://localhost/",
+			Expected: "53 Proxy request refused\r\n",
+		},
+		{
+			Request:  "/index.gmi",
+			Expected: "59 Bad request\r\n",
+		},
+		{
+			Request:  "gemini://localhost/#top",
+			Expected: "59 Bad request\r\n",
+		},
+		{
+			Request:  "gemini://localhost/" + strings.Repeat("x", 1024),
+			Expected: "59 Bad request\r\n",
+		},
+	}
+
+	for testNumber, test := range tests {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		if _, err := io.WriteString(conn, test.Request+"\r\n"); nil != err {
+			conn.Close()
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		data, err := io.ReadAll(conn)
+		conn.Close()
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, string(data); expected != actual {
+			t.Errorf("For test #%d, the actual response was not what was expected.", testNumber)
+			t.Logf("REQUEST: %q", test.Request)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}
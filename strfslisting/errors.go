@@ -0,0 +1,11 @@
+package strfslisting
+
+import (
+	"github.com/reiver/go-erorr"
+)
+
+const (
+	errNilFS        = erorr.Error("nil file-system")
+	errNilWriter    = erorr.Error("nil writer")
+	errNotDirectory = erorr.Error("not a directory")
+)
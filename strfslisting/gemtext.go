@@ -0,0 +1,70 @@
+package strfslisting
+
+import (
+	"bytes"
+	"io"
+)
+
+// GemtextRenderer renders a directory listing as Gemini gemtext (i.e., "text/gemini").
+//
+// For example:
+//
+//	# Index of /css/
+//
+//	=> ../ ../
+//	=> fonts/ fonts/
+//	=> style.css style.css (15 B, 2022-12-12)
+type GemtextRenderer struct{}
+
+// A trick to make sure strfslisting.GemtextRenderer fits the strfslisting.Renderer interface.
+// This is a compile-time check.
+var _ Renderer = GemtextRenderer{}
+
+// ContentType returns "text/gemini; charset=utf-8".
+//
+// ContentType makes strfslisting.GemtextRenderer fit the strfslisting.Renderer interface.
+func (receiver GemtextRenderer) ContentType() string {
+	return "text/gemini; charset=utf-8"
+}
+
+// Render writes 'listing' to 'writer' as gemtext — a link line for each entry.
+//
+// Render makes strfslisting.GemtextRenderer fit the strfslisting.Renderer interface.
+func (receiver GemtextRenderer) Render(writer io.Writer, listing Listing) error {
+	if nil == writer {
+		return errNilWriter
+	}
+
+	var buffer bytes.Buffer
+
+	buffer.WriteString("# ")
+	buffer.WriteString(title(listing))
+	buffer.WriteString("\n\n")
+
+	if "." != listing.Path && "" != listing.Path {
+		buffer.WriteString("=> ../ ../\n")
+	}
+
+	for _, entry := range listing.Entries {
+		var link string = href(entry.Name, entry.IsDir)
+		var label string = entry.Name
+		if entry.IsDir {
+			label += "/"
+		} else {
+			label += " (" + formatSize(entry.Size)
+			if !entry.ModTime.IsZero() {
+				label += ", " + entry.ModTime.UTC().Format("2006-01-02")
+			}
+			label += ")"
+		}
+
+		buffer.WriteString("=> ")
+		buffer.WriteString(link)
+		buffer.WriteString(" ")
+		buffer.WriteString(label)
+		buffer.WriteString("\n")
+	}
+
+	_, err := buffer.WriteTo(writer)
+	return err
+}
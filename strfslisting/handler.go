@@ -0,0 +1,112 @@
+package strfslisting
+
+import (
+	"bytes"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Handler is an http.Handler that serves a fs.FS (such as a strfs.FS, a strfs.Snapshot, or a strfs.Tree),
+// with a directory listing for each directory that does not have an "index.html" file.
+//
+// Files (and directories with an "index.html" file) are served the same as with http.FileServer.
+//
+// The format of a directory listing is chosen with the "format" query parameter ("html", "json", or "gemtext" — see ParseRenderer),
+// or else from the Accept header, or else is HTML.
+// The order of a directory listing is chosen with the "sort" ("name", "size", or "mtime" — see ParseColumn)
+// and "order" ("asc" or "desc") query parameters.
+type Handler struct {
+	fsys fs.FS
+	fileserver http.Handler
+}
+
+// A trick to make sure strfslisting.Handler fits the http.Handler interface.
+// This is a compile-time check.
+var _ http.Handler = Handler{}
+
+// CreateHandler returns a strfslisting.Handler that serves 'fsys'.
+func CreateHandler(fsys fs.FS) Handler {
+	return Handler{
+		fsys:fsys,
+		fileserver:http.FileServer(http.FS(fsys)),
+	}
+}
+
+// rendererFor returns the strfslisting.Renderer that 'request' asks for.
+func rendererFor(request *http.Request) Renderer {
+	if renderer, found := ParseRenderer(request.URL.Query().Get("format")); found {
+		return renderer
+	}
+
+	var accept string = request.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "text/html"):
+		return HTML
+	case strings.Contains(accept, "application/json"):
+		return JSON
+	case strings.Contains(accept, "text/gemini"):
+		return Gemtext
+	default:
+		return HTML
+	}
+}
+
+// ServeHTTP serves the file, or directory listing, at request.URL.Path.
+//
+// ServeHTTP makes strfslisting.Handler fit the http.Handler interface.
+func (receiver Handler) ServeHTTP(responsewriter http.ResponseWriter, request *http.Request) {
+	if nil == receiver.fsys {
+		http.Error(responsewriter, errNilFS.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var name string = path.Clean("/" + request.URL.Path)[1:]
+	if "" == name {
+		name = "."
+	}
+
+	fileinfo, err := fs.Stat(receiver.fsys, name)
+	if nil != err || !fileinfo.IsDir() {
+		receiver.fileserver.ServeHTTP(responsewriter, request)
+		return
+	}
+	if _, err := fs.Stat(receiver.fsys, path.Join(name, "index.html")); nil == err {
+		receiver.fileserver.ServeHTTP(responsewriter, request)
+		return
+	}
+
+	if !strings.HasSuffix(request.URL.Path, "/") {
+		var location string = path.Base(request.URL.Path) + "/"
+		if "" != request.URL.RawQuery {
+			location += "?" + request.URL.RawQuery
+		}
+		http.Redirect(responsewriter, request, location, http.StatusMovedPermanently)
+		return
+	}
+
+	listing, err := ReadListing(receiver.fsys, name)
+	if nil != err {
+		http.Error(responsewriter, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if column, found := ParseColumn(request.URL.Query().Get("sort")); found {
+		listing.Sort(column, "desc" == request.URL.Query().Get("order"))
+	}
+
+	var renderer Renderer = rendererFor(request)
+
+	var buffer bytes.Buffer
+	if err := renderer.Render(&buffer, listing); nil != err {
+		http.Error(responsewriter, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	responsewriter.Header().Set("Content-Type", renderer.ContentType())
+	responsewriter.Header().Set("Vary", "Accept")
+	if http.MethodHead == request.Method {
+		return
+	}
+	buffer.WriteTo(responsewriter)
+}
@@ -0,0 +1,126 @@
+package strfslisting
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+// HTMLRenderer renders a directory listing as an HTML page, with a table of the names, sizes, and modification-times
+// of the entries.
+//
+// The column headings link to the same listing, sorted by that column (with "?sort=…&order=…" — see ParseColumn).
+// Following the link of the column the listing is already sorted by reverses the order.
+type HTMLRenderer struct{}
+
+// A trick to make sure strfslisting.HTMLRenderer fits the strfslisting.Renderer interface.
+// This is a compile-time check.
+var _ Renderer = HTMLRenderer{}
+
+var htmlTemplate *template.Template = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<thead>
+<tr>{{range .Columns}}<th><a href="{{.Href}}">{{.Label}}</a>{{.Arrow}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{if .Parent}}<tr><td><a href="../">../</a></td><td></td><td></td></tr>
+{{end}}{{range .Entries}}<tr><td><a href="{{.Href}}">{{.Name}}</a></td><td>{{.Size}}</td><td>{{.ModTime}}</td></tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+type internalHTMLColumn struct {
+	Label string
+	Href  string
+	Arrow string
+}
+
+type internalHTMLEntry struct {
+	Name    string
+	Href    string
+	Size    string
+	ModTime string
+}
+
+// ContentType returns "text/html; charset=utf-8".
+//
+// ContentType makes strfslisting.HTMLRenderer fit the strfslisting.Renderer interface.
+func (receiver HTMLRenderer) ContentType() string {
+	return "text/html; charset=utf-8"
+}
+
+// Render writes 'listing' to 'writer' as an HTML page.
+//
+// Render makes strfslisting.HTMLRenderer fit the strfslisting.Renderer interface.
+func (receiver HTMLRenderer) Render(writer io.Writer, listing Listing) error {
+	if nil == writer {
+		return errNilWriter
+	}
+
+	var columns []internalHTMLColumn
+	for _, column := range []struct{
+		Column Column
+		Label  string
+	}{
+		{ColumnName,    "Name"},
+		{ColumnSize,    "Size"},
+		{ColumnModTime, "Last modified"},
+	} {
+		var order string = "asc"
+		var arrow string
+		if listing.SortColumn == column.Column {
+			arrow = " ▲"
+			if listing.SortDescending {
+				arrow = " ▼"
+			} else {
+				order = "desc"
+			}
+		}
+
+		columns = append(columns, internalHTMLColumn{
+			Label: column.Label,
+			Href:  "?sort=" + column.Column.String() + "&order=" + order,
+			Arrow: arrow,
+		})
+	}
+
+	var entries []internalHTMLEntry
+	for _, entry := range listing.Entries {
+		var htmlentry internalHTMLEntry = internalHTMLEntry{
+			Name: entry.Name,
+			Href: href(entry.Name, entry.IsDir),
+			Size: "-",
+		}
+		if entry.IsDir {
+			htmlentry.Name += "/"
+		} else {
+			htmlentry.Size = formatSize(entry.Size)
+		}
+		if !entry.ModTime.IsZero() {
+			htmlentry.ModTime = entry.ModTime.UTC().Format(time.RFC3339)
+		}
+
+		entries = append(entries, htmlentry)
+	}
+
+	return htmlTemplate.Execute(writer, struct{
+		Title   string
+		Parent  bool
+		Columns []internalHTMLColumn
+		Entries []internalHTMLEntry
+	}{
+		Title:   title(listing),
+		Parent:  "." != listing.Path && "" != listing.Path,
+		Columns: columns,
+		Entries: entries,
+	})
+}
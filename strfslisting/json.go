@@ -0,0 +1,87 @@
+package strfslisting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// JSONRenderer renders a directory listing as JSON.
+//
+// For example:
+//
+//	{
+//		"path": "/css/",
+//		"entries": [
+//			{"name":"fonts","type":"directory","size":0,"mode":"0555"},
+//			{"name":"style.css","type":"file","size":15,"mtime":"2022-12-12T10:30:14Z","mode":"0644"}
+//		]
+//	}
+//
+// (The "mtime" is left out if it is not known.)
+type JSONRenderer struct{}
+
+// A trick to make sure strfslisting.JSONRenderer fits the strfslisting.Renderer interface.
+// This is a compile-time check.
+var _ Renderer = JSONRenderer{}
+
+type internalJSONListing struct {
+	Path    string              `json:"path"`
+	Entries []internalJSONEntry `json:"entries"`
+}
+
+type internalJSONEntry struct {
+	Name    string     `json:"name"`
+	Type    string     `json:"type"`
+	Size    int64      `json:"size"`
+	ModTime *time.Time `json:"mtime,omitempty"`
+	Mode    string     `json:"mode"`
+}
+
+// ContentType returns "application/json".
+//
+// ContentType makes strfslisting.JSONRenderer fit the strfslisting.Renderer interface.
+func (receiver JSONRenderer) ContentType() string {
+	return "application/json"
+}
+
+// Render writes 'listing' to 'writer' as JSON.
+//
+// Render makes strfslisting.JSONRenderer fit the strfslisting.Renderer interface.
+func (receiver JSONRenderer) Render(writer io.Writer, listing Listing) error {
+	if nil == writer {
+		return errNilWriter
+	}
+
+	var jsonlisting internalJSONListing = internalJSONListing{
+		Path:    "/",
+		Entries: []internalJSONEntry{},
+	}
+	if "." != listing.Path && "" != listing.Path {
+		jsonlisting.Path = "/" + listing.Path + "/"
+	}
+
+	for _, entry := range listing.Entries {
+		var jsonentry internalJSONEntry = internalJSONEntry{
+			Name: entry.Name,
+			Type: "file",
+			Size: entry.Size,
+			Mode: fmt.Sprintf("%04o", entry.Mode.Perm()),
+		}
+		if entry.IsDir {
+			jsonentry.Type = "directory"
+			jsonentry.Size = 0
+		}
+		if !entry.ModTime.IsZero() {
+			var mtime time.Time = entry.ModTime.UTC()
+			jsonentry.ModTime = &mtime
+		}
+
+		jsonlisting.Entries = append(jsonlisting.Entries, jsonentry)
+	}
+
+	var encoder *json.Encoder = json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(jsonlisting)
+}
@@ -0,0 +1,157 @@
+// Package strfslisting renders directory listings (i.e., directory indexes) of a strfs file-system (or any other fs.FS)
+// as HTML, JSON, or Gemini gemtext.
+//
+// The renderers are not tied to any one server — they can be used by the HTTP handler in this package (see CreateHandler),
+// or by any other server adapter.
+//
+// Example usage:
+//
+//	listing, err := strfslisting.ReadListing(fsys, "css")
+//
+//	// ...
+//
+//	listing.Sort(strfslisting.ColumnSize, true)
+//
+//	err = strfslisting.HTML.Render(w, listing)
+package strfslisting
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+)
+
+// Column is a column of a directory listing, that the listing can be sorted by.
+type Column int
+
+const (
+	ColumnName Column = iota
+	ColumnSize
+	ColumnModTime
+)
+
+// ParseColumn returns the strfslisting.Column named 'name' — "name", "size", or "mtime" (as returned by Column.String).
+func ParseColumn(name string) (Column, bool) {
+	switch name {
+	case "name":
+		return ColumnName, true
+	case "size":
+		return ColumnSize, true
+	case "mtime":
+		return ColumnModTime, true
+	default:
+		return ColumnName, false
+	}
+}
+
+// String returns the name of the column — "name", "size", or "mtime".
+func (receiver Column) String() string {
+	switch receiver {
+	case ColumnSize:
+		return "size"
+	case ColumnModTime:
+		return "mtime"
+	default:
+		return "name"
+	}
+}
+
+// Entry is a file (or directory) in a directory listing.
+type Entry struct {
+	Name    string
+	IsDir   bool
+	Size    int64
+	ModTime time.Time
+	Mode    fs.FileMode
+}
+
+// Listing is a directory listing.
+type Listing struct {
+	// Path is the path of the directory — "." for the root.
+	Path string
+
+	Entries []Entry
+
+	// SortColumn and SortDescending are how Entries are sorted (see Sort).
+	SortColumn     Column
+	SortDescending bool
+}
+
+// ReadListing returns the listing of the directory 'name' of 'fsys', sorted by name.
+func ReadListing(fsys fs.FS, name string) (Listing, error) {
+	if nil == fsys {
+		return Listing{}, errNilFS
+	}
+
+	fileinfo, err := fs.Stat(fsys, name)
+	if nil != err {
+		return Listing{}, err
+	}
+	if !fileinfo.IsDir() {
+		return Listing{}, &fs.PathError{Op:"readlisting", Path:name, Err:errNotDirectory}
+	}
+
+	entries, err := fs.ReadDir(fsys, name)
+	if nil != err {
+		return Listing{}, err
+	}
+
+	var listing Listing = Listing{
+		Path:path.Clean(name),
+	}
+	for _, entry := range entries {
+		fileinfo, err := entry.Info()
+		if nil != err {
+			return Listing{}, err
+		}
+
+		listing.Entries = append(listing.Entries, Entry{
+			Name:    entry.Name(),
+			IsDir:   entry.IsDir(),
+			Size:    fileinfo.Size(),
+			ModTime: fileinfo.ModTime(),
+			Mode:    fileinfo.Mode(),
+		})
+	}
+
+	listing.Sort(ColumnName, false)
+	return listing, nil
+}
+
+// Sort sorts the entries of the listing by 'column' (in descending order, if 'descending' is true).
+//
+// Directories always come before files.
+// Entries that are the same in 'column' are sorted by name.
+func (receiver *Listing) Sort(column Column, descending bool) {
+	if nil == receiver {
+		return
+	}
+
+	receiver.SortColumn = column
+	receiver.SortDescending = descending
+
+	var entries []Entry = receiver.Entries
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].IsDir != entries[j].IsDir {
+			return entries[i].IsDir
+		}
+
+		var a, b Entry = entries[i], entries[j]
+		if descending {
+			a, b = b, a
+		}
+
+		switch column {
+		case ColumnSize:
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+		case ColumnModTime:
+			if !a.ModTime.Equal(b.ModTime) {
+				return a.ModTime.Before(b.ModTime)
+			}
+		}
+		return a.Name < b.Name
+	})
+}
@@ -0,0 +1,318 @@
+package strfslisting_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfslisting"
+
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"testing"
+)
+
+func exampleFS() strfs.FS {
+	var mtime1 time.Time = time.Date(2022, 12, 12, 10, 30, 14, 0, time.UTC)
+	var mtime2 time.Time = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	return strfs.FS{
+		"site/index.html":      strfs.RegularFile{FileContent: strfs.CreateContent("<!DOCTYPE html>"), FileModTime: mtime1, FileMode: 0644},
+		"css/style.css":        strfs.RegularFile{FileContent: strfs.CreateContent("body{color:red}"), FileModTime: mtime1, FileMode: 0644},
+		"css/print.css":        strfs.RegularFile{FileContent: strfs.CreateContent(strings.Repeat("x", 2048)), FileModTime: mtime2, FileMode: 0600},
+		"css/fonts/serif.woff": strfs.RegularFile{FileContent: strfs.CreateContent("woff"), FileModTime: mtime2, FileMode: 0644},
+		"css/a & b.css":        strfs.RegularFile{FileContent: strfs.CreateContent("a{}"), FileModTime: mtime2, FileMode: 0644},
+	}
+}
+
+func names(listing strfslisting.Listing) string {
+	var result []string
+	for _, entry := range listing.Entries {
+		result = append(result, entry.Name)
+	}
+
+	return strings.Join(result, " ")
+}
+
+func TestReadListing(t *testing.T) {
+
+	listing, err := strfslisting.ReadListing(exampleFS(), "css")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	tests := []struct{
+		Column     strfslisting.Column
+		Descending bool
+		Expected   string
+	}{
+		{
+			Column:   strfslisting.ColumnName,
+			Expected: "fonts a & b.css print.css style.css",
+		},
+		{
+			Column:     strfslisting.ColumnName,
+			Descending: true,
+			Expected:   "fonts style.css print.css a & b.css",
+		},
+		{
+			Column:   strfslisting.ColumnSize,
+			Expected: "fonts a & b.css style.css print.css",
+		},
+		{
+			Column:     strfslisting.ColumnSize,
+			Descending: true,
+			Expected:   "fonts print.css style.css a & b.css",
+		},
+		{
+			Column:   strfslisting.ColumnModTime,
+			Expected: "fonts style.css a & b.css print.css",
+		},
+	}
+
+	for testNumber, test := range tests {
+		listing.Sort(test.Column, test.Descending)
+
+		if expected, actual := test.Expected, names(listing); expected != actual {
+			t.Errorf("For test #%d, the actual order was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+
+	if _, err := strfslisting.ReadListing(exampleFS(), "css/style.css"); nil == err {
+		t.Errorf("Expected an error reading the listing of a file.")
+	}
+}
+
+func TestRenderer(t *testing.T) {
+
+	listing, err := strfslisting.ReadListing(exampleFS(), "css")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	{
+		var buffer strings.Builder
+		if err := strfslisting.Gemtext.Render(&buffer, listing); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		var expected string =
+			"# Index of /css/"+"\n"+
+			""+"\n"+
+			"=> ../ ../"+"\n"+
+			"=> fonts/ fonts/"+"\n"+
+			"=> a%20&%20b.css a & b.css (3 B, 2023-01-02)"+"\n"+
+			"=> print.css print.css (2.0 KiB, 2023-01-02)"+"\n"+
+			"=> style.css style.css (15 B, 2022-12-12)"+"\n"
+
+		if actual := buffer.String(); expected != actual {
+			t.Errorf("The actual gemtext was not what was expected.")
+			t.Logf("EXPECTED:\n%s", expected)
+			t.Logf("ACTUAL:\n%s", actual)
+		}
+	}
+
+	{
+		var buffer strings.Builder
+		if err := strfslisting.JSON.Render(&buffer, listing); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		var expected string = `{"path":"/css/","entries":[`+
+			`{"name":"fonts","type":"directory","size":0,"mode":"0555"},`+
+			`{"name":"a & b.css","type":"file","size":3,"mtime":"2023-01-02T03:04:05Z","mode":"0644"},`+
+			`{"name":"print.css","type":"file","size":2048,"mtime":"2023-01-02T03:04:05Z","mode":"0600"},`+
+			`{"name":"style.css","type":"file","size":15,"mtime":"2022-12-12T10:30:14Z","mode":"0644"}`+
+			`]}`+"\n"
+
+		if actual := buffer.String(); expected != actual {
+			t.Errorf("The actual JSON was not what was expected.")
+			t.Logf("EXPECTED: %s", expected)
+			t.Logf("ACTUAL:   %s", actual)
+		}
+	}
+
+	{
+		listing.Sort(strfslisting.ColumnSize, false)
+
+		var buffer strings.Builder
+		if err := strfslisting.HTML.Render(&buffer, listing); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		var actual string = buffer.String()
+		for _, expected := range []string{
+			"<title>Index of /css/</title>",
+			`<a href="../">../</a>`,
+			`<a href="fonts/">fonts/</a>`,
+			`<a href="a%20&amp;%20b.css">a &amp; b.css</a></td><td>3 B</td><td>2023-01-02T03:04:05Z</td>`,
+			`<th><a href="?sort=size&amp;order=desc">Size</a> ▲</th>`,
+			`<th><a href="?sort=name&amp;order=asc">Name</a></th>`,
+		} {
+			if !strings.Contains(actual, expected) {
+				t.Errorf("Expected the HTML to contain %q, but it did not.", expected)
+				t.Logf("HTML:\n%s", actual)
+			}
+		}
+	}
+}
+
+func TestRenderer_colon(t *testing.T) {
+
+	// A name with a ":" in it must not be taken to be a URL with a scheme (such as "a:").
+	var fsys strfs.FS = strfs.FS{
+		"a:b.txt":   strfs.RegularFile{FileContent: strfs.CreateContent("ab")},
+		"c:d/e.txt": strfs.RegularFile{FileContent: strfs.CreateContent("e")},
+	}
+
+	listing, err := strfslisting.ReadListing(fsys, ".")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	tests := []struct{
+		Renderer strfslisting.Renderer
+		Expected []string
+	}{
+		{
+			Renderer: strfslisting.Gemtext,
+			Expected: []string{"=> ./c:d/ c:d/"+"\n", "=> ./a:b.txt a:b.txt "},
+		},
+		{
+			Renderer: strfslisting.HTML,
+			Expected: []string{`<a href="./c:d/">c:d/</a>`, `<a href="./a:b.txt">a:b.txt</a>`},
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var buffer strings.Builder
+		if err := test.Renderer.Render(&buffer, listing); nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one: (%T) %s", testNumber, err, err)
+			continue
+		}
+
+		var actual string = buffer.String()
+		for _, expected := range test.Expected {
+			if !strings.Contains(actual, expected) {
+				t.Errorf("For test #%d, expected the listing to contain %q, but it did not.", testNumber, expected)
+				t.Logf("LISTING:\n%s", actual)
+			}
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+
+	var server *httptest.Server = httptest.NewServer(strfslisting.CreateHandler(exampleFS()))
+	defer server.Close()
+
+	get := func(path string, accept string) (int, string, string) {
+		t.Helper()
+
+		request, err := http.NewRequest("GET", server.URL+path, nil)
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+		if "" != accept {
+			request.Header.Set("Accept", accept)
+		}
+
+		response, err := server.Client().Do(request)
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+		defer response.Body.Close()
+
+		data, err := io.ReadAll(response.Body)
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		return response.StatusCode, response.Header.Get("Content-Type"), string(data)
+	}
+
+	tests := []struct{
+		Path                string
+		Accept              string
+		ExpectedStatus      int
+		ExpectedContentType string
+		ExpectedBody        string
+	}{
+		{
+			Path:                "/css/",
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "text/html; charset=utf-8",
+			ExpectedBody:        `<a href="style.css">style.css</a>`,
+		},
+		{
+			Path:                "/css", // (redirected to "/css/")
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "text/html; charset=utf-8",
+			ExpectedBody:        `<a href="style.css">style.css</a>`,
+		},
+		{
+			Path:                "/css/",
+			Accept:              "application/json",
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "application/json",
+			ExpectedBody:        `{"path":"/css/",`,
+		},
+		{
+			Path:                "/css/?format=gemtext&sort=size&order=desc",
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "text/gemini; charset=utf-8",
+			ExpectedBody:        "=> fonts/ fonts/\n=> print.css",
+		},
+		{
+			Path:                "/",
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "text/html; charset=utf-8",
+			ExpectedBody:        `<a href="site/">site/</a>`,
+		},
+		{
+			Path:                "/site/",
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "text/html; charset=utf-8",
+			ExpectedBody:        "<!DOCTYPE html>",
+		},
+		{
+			Path:                "/css/style.css",
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "text/css; charset=utf-8",
+			ExpectedBody:        "body{color:red}",
+		},
+		{
+			Path:           "/missing.txt",
+			ExpectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for testNumber, test := range tests {
+		status, contentType, body := get(test.Path, test.Accept)
+
+		if expected, actual := test.ExpectedStatus, status; expected != actual {
+			t.Errorf("For test #%d, the actual status-code was not what was expected: expected %d, actually %d", testNumber, expected, actual)
+			t.Logf("PATH: %q", test.Path)
+			continue
+		}
+		if "" != test.ExpectedContentType && test.ExpectedContentType != contentType {
+			t.Errorf("For test #%d, the actual content-type was not what was expected.", testNumber)
+			t.Logf("PATH: %q", test.Path)
+			t.Logf("EXPECTED: %q", test.ExpectedContentType)
+			t.Logf("ACTUAL:   %q", contentType)
+			continue
+		}
+		if !strings.Contains(body, test.ExpectedBody) {
+			t.Errorf("For test #%d, the actual body did not contain what was expected.", testNumber)
+			t.Logf("PATH: %q", test.Path)
+			t.Logf("EXPECTED: %q", test.ExpectedBody)
+			t.Logf("BODY:\n%s", body)
+			continue
+		}
+	}
+}
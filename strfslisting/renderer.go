@@ -0,0 +1,78 @@
+package strfslisting
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// Renderer renders a directory listing in some format.
+type Renderer interface {
+	// ContentType returns the media-type of what Render writes (for example, "text/html; charset=utf-8").
+	ContentType() string
+
+	// Render writes 'listing' to 'writer'.
+	Render(writer io.Writer, listing Listing) error
+}
+
+// The renderers.
+var (
+	Gemtext Renderer = GemtextRenderer{}
+	HTML    Renderer = HTMLRenderer{}
+	JSON    Renderer = JSONRenderer{}
+)
+
+// ParseRenderer returns the strfslisting.Renderer for the format named 'name' — "html", "json", or "gemtext" (or "gemini").
+func ParseRenderer(name string) (Renderer, bool) {
+	switch name {
+	case "html":
+		return HTML, true
+	case "json":
+		return JSON, true
+	case "gemtext", "gemini":
+		return Gemtext, true
+	default:
+		return nil, false
+	}
+}
+
+// href returns the (relative) link to the entry named 'name' — with a "/" at the end of it, if it is a directory.
+//
+// The link is built the same way as net/http's directory listings build it.
+// So, a name that looks like it starts with a URL scheme (such as "a:b.txt") gets "./" in front of it.
+func href(name string, isDir bool) string {
+	var result string = (&url.URL{Path:name}).String()
+	if isDir {
+		result += "/"
+	}
+
+	return result
+}
+
+// formatSize returns 'size' (a number of bytes) in a form that is easy for a person to read (for example, "15 B", or "1.2 KiB").
+func formatSize(size int64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	var value float64 = float64(size) / unit
+	for _, prefix := range "KMGTP" {
+		if value < unit {
+			return fmt.Sprintf("%.1f %ciB", value, prefix)
+		}
+		value /= unit
+	}
+
+	return fmt.Sprintf("%.1f EiB", value)
+}
+
+// title returns the title of a directory listing — for example, "Index of /css/".
+func title(listing Listing) string {
+	if "." == listing.Path || "" == listing.Path {
+		return "Index of /"
+	}
+
+	return "Index of /" + listing.Path + "/"
+}
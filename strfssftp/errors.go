@@ -0,0 +1,10 @@
+package strfssftp
+
+import (
+	"github.com/reiver/go-erorr"
+)
+
+const (
+	errFileTooBig  = erorr.Error("file too big")
+	errIsDirectory = erorr.Error("is a directory")
+)
@@ -0,0 +1,147 @@
+// Package strfssftp lets a strfs file-system be served over SFTP (using the request-server of github.com/pkg/sftp).
+//
+// Handler serves any fs.FS (such as a strfs.FS, or a strfs.Snapshot) read-only.
+// TreeHandler serves a strfs.Tree read-write.
+//
+// Example usage (once an SSH channel for the "sftp" subsystem has been accepted, with golang.org/x/crypto/ssh):
+//
+//	var server *sftp.RequestServer = sftp.NewRequestServer(channel, strfssftp.CreateHandlers(fsys))
+//	defer server.Close()
+//
+//	err := server.Serve()
+package strfssftp
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/internal/adapter"
+	"github.com/pkg/sftp"
+)
+
+// Handler is a read-only SFTP handler over a fs.FS (such as a strfs.FS, or a strfs.Snapshot).
+//
+// Anything that would change the file-system returns a permission-denied error.
+type Handler struct {
+	fsys fs.FS
+}
+
+// A trick to make sure strfssftp.Handler fits the sftp.FileReader, sftp.FileWriter, sftp.FileCmder, and sftp.FileLister interfaces.
+// This is a compile-time check.
+var _ sftp.FileReader = Handler{}
+var _ sftp.FileWriter = Handler{}
+var _ sftp.FileCmder = Handler{}
+var _ sftp.FileLister = Handler{}
+
+// CreateHandler returns a (read-only) strfssftp.Handler over 'fsys'.
+func CreateHandler(fsys fs.FS) Handler {
+	return Handler{
+		fsys:fsys,
+	}
+}
+
+// CreateHandlers returns the sftp.Handlers (for sftp.NewRequestServer) that serve 'fsys'.
+//
+// If 'fsys' is a *strfs.Tree, then it is served read-write (with a strfssftp.TreeHandler);
+// otherwise it is served read-only (with a strfssftp.Handler).
+func CreateHandlers(fsys fs.FS) sftp.Handlers {
+	if tree, casted := fsys.(*strfs.Tree); casted {
+		var handler *TreeHandler = CreateTreeHandler(tree)
+
+		return sftp.Handlers{
+			FileGet:  handler,
+			FilePut:  handler,
+			FileCmd:  handler,
+			FileList: handler,
+		}
+	}
+
+	var handler Handler = CreateHandler(fsys)
+
+	return sftp.Handlers{
+		FileGet:  handler,
+		FilePut:  handler,
+		FileCmd:  handler,
+		FileList: handler,
+	}
+}
+
+// Filecmd always returns an error, because a strfssftp.Handler is read-only.
+//
+// Filecmd makes strfssftp.Handler fit the sftp.FileCmder interface.
+func (receiver Handler) Filecmd(request *sftp.Request) error {
+	return sftp.ErrSSHFxPermissionDenied
+}
+
+// Filelist lists the directory (for a "List" request), or stats the file or directory (for a "Stat" request), at request.Filepath.
+//
+// Filelist makes strfssftp.Handler fit the sftp.FileLister interface.
+func (receiver Handler) Filelist(request *sftp.Request) (sftp.ListerAt, error) {
+	var name string = adapter.CleanName(request.Filepath)
+
+	switch request.Method {
+	case "List":
+		return list(receiver.fsys, name)
+	case "Stat":
+		fileinfo, err := fs.Stat(receiver.fsys, name)
+		if nil != err {
+			return nil, err
+		}
+		return internalListerAt{fileinfo}, nil
+	default:
+		return nil, sftp.ErrSSHFxOpUnsupported
+	}
+}
+
+// Fileread returns an io.ReaderAt for (a copy of the content of) the file at request.Filepath.
+//
+// Fileread makes strfssftp.Handler fit the sftp.FileReader interface.
+func (receiver Handler) Fileread(request *sftp.Request) (io.ReaderAt, error) {
+	return read(receiver.fsys, adapter.CleanName(request.Filepath))
+}
+
+// Filewrite always returns an error, because a strfssftp.Handler is read-only.
+//
+// Filewrite makes strfssftp.Handler fit the sftp.FileWriter interface.
+func (receiver Handler) Filewrite(request *sftp.Request) (io.WriterAt, error) {
+	return nil, sftp.ErrSSHFxPermissionDenied
+}
+
+// list returns the fs.FileInfo of what is in the directory 'name' (of 'fsys').
+func list(fsys fs.FS, name string) (internalListerAt, error) {
+	entries, err := fs.ReadDir(fsys, name)
+	if nil != err {
+		return nil, err
+	}
+
+	var fileinfos internalListerAt
+	for _, entry := range entries {
+		fileinfo, err := entry.Info()
+		if nil != err {
+			return nil, err
+		}
+		fileinfos = append(fileinfos, fileinfo)
+	}
+
+	return fileinfos, nil
+}
+
+// read returns an io.ReaderAt for (a copy of the content of) the file 'name' (of 'fsys').
+func read(fsys fs.FS, name string) (io.ReaderAt, error) {
+	fileinfo, err := fs.Stat(fsys, name)
+	if nil != err {
+		return nil, err
+	}
+	if fileinfo.IsDir() {
+		return nil, &fs.PathError{Op:"read", Path:name, Err:errIsDirectory}
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if nil != err {
+		return nil, err
+	}
+
+	return bytes.NewReader(data), nil
+}
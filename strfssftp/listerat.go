@@ -0,0 +1,31 @@
+package strfssftp
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/pkg/sftp"
+)
+
+// internalListerAt is a sftp.ListerAt over a list of fs.FileInfo.
+type internalListerAt []fs.FileInfo
+
+// A trick to make sure strfssftp.internalListerAt fits the sftp.ListerAt interface.
+// This is a compile-time check.
+var _ sftp.ListerAt = internalListerAt{}
+
+// ListAt copies the fs.FileInfo starting at 'offset' into 'fileinfos'.
+//
+// io.EOF is returned once there are no more.
+func (receiver internalListerAt) ListAt(fileinfos []fs.FileInfo, offset int64) (int, error) {
+	if int64(len(receiver)) <= offset {
+		return 0, io.EOF
+	}
+
+	n := copy(fileinfos, receiver[offset:])
+	if n < len(fileinfos) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
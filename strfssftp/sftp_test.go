@@ -0,0 +1,258 @@
+package strfssftp_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfssftp"
+
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+
+	"testing"
+)
+
+func exampleFS() strfs.FS {
+	var mtime time.Time = time.Date(2022, 12, 12, 10, 30, 14, 0, time.UTC)
+
+	return strfs.FS{
+		"index.html": strfs.RegularFile{
+			FileContent: strfs.CreateContent("<!DOCTYPE html>"+"\n"+"<html><body>Hello world!</body></html>"),
+			FileModTime: mtime,
+			FileMode:    0644,
+		},
+		"css/style.css": strfs.RegularFile{
+			FileContent: strfs.CreateContent("body{color:red}"),
+			FileModTime: mtime,
+			FileMode:    0644,
+		},
+	}
+}
+
+// dial returns an SFTP client connected (with an in-process pipe) to an sftp.RequestServer serving 'fsys'.
+func dial(t *testing.T, fsys fs.FS) *sftp.Client {
+	t.Helper()
+
+	clientConn, serverConn := net.Pipe()
+
+	var server *sftp.RequestServer = sftp.NewRequestServer(serverConn, strfssftp.CreateHandlers(fsys))
+	go server.Serve()
+
+	client, err := sftp.NewClientPipe(clientConn, clientConn)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	return client
+}
+
+func expectFile(t *testing.T, fsys fs.FS, name string, expected string) {
+	t.Helper()
+
+	data, err := fs.ReadFile(fsys, name)
+	if nil != err {
+		t.Errorf("Did not expect an error reading %q but actually got one.", name)
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	if actual := string(data); expected != actual {
+		t.Errorf("The actual content of %q was not what was expected.", name)
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+}
+
+func readDirNames(t *testing.T, client *sftp.Client, name string) string {
+	t.Helper()
+
+	fileinfos, err := client.ReadDir(name)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var names []string
+	for _, fileinfo := range fileinfos {
+		var suffix string
+		if fileinfo.IsDir() {
+			suffix = "/"
+		}
+		names = append(names, fileinfo.Name()+suffix)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, " ")
+}
+
+func TestHandler(t *testing.T) {
+
+	var client *sftp.Client = dial(t, exampleFS())
+
+	if expected, actual := "css/ index.html", readDirNames(t, client, "/"); expected != actual {
+		t.Errorf("The actual directory listing was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	{
+		file, err := client.Open("/css/style.css")
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		if expected, actual := "body{color:red}", string(data); expected != actual {
+			t.Errorf("The actual content was not what was expected.")
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+		}
+	}
+
+	{
+		fileinfo, err := client.Stat("/index.html")
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		if expected, actual := int64(54), fileinfo.Size(); expected != actual {
+			t.Errorf("The actual size was not what was expected: expected %d, actually %d", expected, actual)
+		}
+	}
+
+	if _, err := client.Stat("/missing.txt"); !os.IsNotExist(err) {
+		t.Errorf("Expected a not-exist error for a file that does not exist.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+
+	if _, err := client.Create("/new.txt"); !os.IsPermission(err) {
+		t.Errorf("Expected a permission error creating a file.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+	if err := client.Remove("/index.html"); !os.IsPermission(err) {
+		t.Errorf("Expected a permission error removing a file.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+}
+
+func TestTreeHandler(t *testing.T) {
+
+	tree, err := strfs.CreateTree(exampleFS())
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var client *sftp.Client = dial(t, tree)
+
+	if err := client.Mkdir("/reports"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if expected, actual := "css/ index.html reports/", readDirNames(t, client, "/"); expected != actual {
+		t.Errorf("The actual directory listing was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	{
+		file, err := client.Create("/reports/2023.csv")
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+		if _, err := file.Write([]byte("year,total\n")); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+		if _, err := file.Write([]byte("2023,42\n")); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+		if err := file.Close(); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+	}
+	expectFile(t, tree, "reports/2023.csv", "year,total\n2023,42\n")
+
+	// Writes (and truncates) that would make a file too big are errors (rather than crashing the server).
+	{
+		file, err := client.Create("/reports/huge.bin")
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+		if _, err := file.WriteAt([]byte("x"), 1<<62); nil == err {
+			t.Errorf("Expected an error writing at a huge offset, but did not actually get one.")
+		}
+		if _, err := file.WriteAt([]byte("x"), 1<<63-1); nil == err {
+			t.Errorf("Expected an error writing at an offset that overflows, but did not actually get one.")
+		}
+		file.Close()
+
+		if err := client.Truncate("/reports/2023.csv", 1<<40); nil == err {
+			t.Errorf("Expected an error truncating a file to a huge size, but did not actually get one.")
+		}
+	}
+	expectFile(t, tree, "reports/2023.csv", "year,total\n2023,42\n")
+
+	if _, err := client.Create("/missing/2023.csv"); !os.IsNotExist(err) {
+		t.Errorf("Expected a not-exist error creating a file in a directory that does not exist.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+
+	if err := client.Chmod("/reports/2023.csv", 0600); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := client.Truncate("/reports/2023.csv", 11); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	expectFile(t, tree, "reports/2023.csv", "year,total\n")
+	if fileinfo, err := fs.Stat(tree, "reports/2023.csv"); nil != err || fs.FileMode(0600) != fileinfo.Mode() {
+		t.Errorf("Expected the mode to have been changed to 0600.")
+	}
+
+	if err := client.Rename("/reports", "/css"); nil == err {
+		t.Errorf("Expected an error renaming over something that already exists.")
+	}
+	if err := client.Rename("/reports", "/archive"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	expectFile(t, tree, "archive/2023.csv", "year,total\n")
+
+	if err := client.PosixRename("/index.html", "/archive/2023.csv"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	expectFile(t, tree, "archive/2023.csv", "<!DOCTYPE html>"+"\n"+"<html><body>Hello world!</body></html>")
+
+	if err := client.RemoveDirectory("/archive"); nil == err {
+		t.Errorf("Expected an error removing a directory that is not empty.")
+	}
+	if err := client.Remove("/archive/2023.csv"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	// (The directory was made with Mkdir, so it is still there even though it is empty now.)
+	if expected, actual := "archive/ css/", readDirNames(t, client, "/"); expected != actual {
+		t.Errorf("The actual directory listing was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	if err := client.Mkdir("/empty"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := client.RemoveDirectory("/empty"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if _, err := client.Stat("/empty"); !os.IsNotExist(err) {
+		t.Errorf("Expected the removed directory to not exist anymore.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+}
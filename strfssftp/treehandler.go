@@ -0,0 +1,238 @@
+package strfssftp
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sync"
+	"time"
+
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/internal/adapter"
+	"github.com/pkg/sftp"
+)
+
+// defaultPerm is the permission-bits that a file created over SFTP gets.
+// (A client can change them afterwards, with a "Setstat" request.)
+const defaultPerm fs.FileMode = 0644
+
+// TreeHandler is a read-write SFTP handler over a strfs.Tree.
+//
+// A strfs.Tree only has (implied) directories that have files in them,
+// so an (empty) directory made with "Mkdir" is remembered by the strfssftp.TreeHandler itself.
+//
+// A file opened for writing is written to the strfs.Tree when it is closed.
+// Clients cannot make a file bigger than 64 MiB.
+type TreeHandler struct {
+	tree *strfs.Tree
+
+	mutex sync.Mutex
+	directories adapter.Directories
+}
+
+// A trick to make sure *strfssftp.TreeHandler fits the sftp.FileReader, sftp.FileWriter, sftp.PosixRenameFileCmder, and sftp.FileLister interfaces.
+// This is a compile-time check.
+var _ sftp.FileReader = &TreeHandler{}
+var _ sftp.FileWriter = &TreeHandler{}
+var _ sftp.PosixRenameFileCmder = &TreeHandler{}
+var _ sftp.FileLister = &TreeHandler{}
+
+// maxFileSize is the biggest a client can make a file (by writing to it, or by changing its size).
+const maxFileSize = 64 << 20
+
+// CreateTreeHandler returns a (read-write) strfssftp.TreeHandler over 'tree'.
+func CreateTreeHandler(tree *strfs.Tree) *TreeHandler {
+	return &TreeHandler{
+		tree:tree,
+		directories:adapter.CreateDirectories(tree),
+	}
+}
+
+// Filecmd handles the "Setstat", "Rename", "Rmdir", "Mkdir", and "Remove" requests.
+//
+// For "Setstat", the size (which truncates, or extends, the file), permission-bits, and modification-time can be changed.
+// Everything else is ignored.
+//
+// "Rename" fails if there is already something at request.Target (see PosixRename, which replaces it).
+//
+// Filecmd makes *strfssftp.TreeHandler fit the sftp.FileCmder interface.
+func (receiver *TreeHandler) Filecmd(request *sftp.Request) error {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	var name string = adapter.CleanName(request.Filepath)
+
+	switch request.Method {
+	case "Mkdir":
+		return receiver.directories.Mkdir(name)
+	case "Remove":
+		return receiver.remove(name)
+	case "Rename":
+		if _, err := receiver.directories.Stat(adapter.CleanName(request.Target)); nil == err {
+			return &fs.PathError{Op:"rename", Path:request.Target, Err:fs.ErrExist}
+		}
+		return receiver.directories.Rename(name, adapter.CleanName(request.Target))
+	case "Rmdir":
+		return receiver.directories.Rmdir(name)
+	case "Setstat":
+		return receiver.setstat(name, request.AttrFlags(), request.Attributes())
+	default:
+		return sftp.ErrSSHFxOpUnsupported
+	}
+}
+
+// Filelist lists the directory (for a "List" request), or stats the file or directory (for a "Stat" request), at request.Filepath.
+//
+// The directories made with "Mkdir" are included.
+//
+// Filelist makes *strfssftp.TreeHandler fit the sftp.FileLister interface.
+func (receiver *TreeHandler) Filelist(request *sftp.Request) (sftp.ListerAt, error) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	var name string = adapter.CleanName(request.Filepath)
+
+	switch request.Method {
+	case "List":
+		fileinfos, err := receiver.directories.ReadDir(name)
+		if nil != err {
+			return nil, err
+		}
+		return internalListerAt(fileinfos), nil
+	case "Stat":
+		fileinfo, err := receiver.directories.Stat(name)
+		if nil != err {
+			return nil, err
+		}
+		return internalListerAt{fileinfo}, nil
+	default:
+		return nil, sftp.ErrSSHFxOpUnsupported
+	}
+}
+
+// Fileread returns an io.ReaderAt for (a copy of the content of) the file at request.Filepath.
+//
+// Fileread makes *strfssftp.TreeHandler fit the sftp.FileReader interface.
+func (receiver *TreeHandler) Fileread(request *sftp.Request) (io.ReaderAt, error) {
+	return read(receiver.tree, adapter.CleanName(request.Filepath))
+}
+
+// Filewrite returns an io.WriterAt for the file at request.Filepath — which is written to the strfs.Tree when it is closed.
+//
+// The create, exclusive, truncate, and append flags (of request.Pflags) are supported.
+//
+// Filewrite makes *strfssftp.TreeHandler fit the sftp.FileWriter interface.
+func (receiver *TreeHandler) Filewrite(request *sftp.Request) (io.WriterAt, error) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	var name string = adapter.CleanName(request.Filepath)
+	var flags sftp.FileOpenFlags = request.Pflags()
+
+	if receiver.directories.IsDirectory(name) {
+		return nil, &fs.PathError{Op:"open", Path:name, Err:errIsDirectory}
+	}
+
+	data, err := fs.ReadFile(receiver.tree, name)
+	var exists bool = nil == err
+	switch {
+	case exists && flags.Creat && flags.Excl:
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrExist}
+	case !exists && !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	case !exists && !flags.Creat:
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrNotExist}
+	case !exists && !receiver.directories.IsDirectory(path.Dir(name)):
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrNotExist}
+	}
+
+	var perm fs.FileMode = defaultPerm
+	if exists {
+		fileinfo, err := fs.Stat(receiver.tree, name)
+		if nil != err {
+			return nil, err
+		}
+		perm = fileinfo.Mode().Perm()
+	} else {
+		if err := receiver.tree.WriteFile(name, "", perm); nil != err {
+			return nil, err
+		}
+	}
+
+	var writerat internalWriterAt = internalWriterAt{
+		tree:receiver.tree,
+		name:name,
+		perm:perm,
+		data:data,
+		appending:flags.Append,
+	}
+	if flags.Trunc {
+		writerat.data = nil
+		writerat.dirty = true
+	}
+
+	return &writerat, nil
+}
+
+// PosixRename handles the "PosixRename" request — which (unlike "Rename") replaces what is at request.Target.
+//
+// PosixRename makes *strfssftp.TreeHandler fit the sftp.PosixRenameFileCmder interface.
+func (receiver *TreeHandler) PosixRename(request *sftp.Request) error {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	return receiver.directories.Rename(adapter.CleanName(request.Filepath), adapter.CleanName(request.Target))
+}
+
+// remove removes the file at 'name'.
+func (receiver *TreeHandler) remove(name string) error {
+	if receiver.directories.IsDirectory(name) {
+		return &fs.PathError{Op:"remove", Path:name, Err:errIsDirectory}
+	}
+
+	return receiver.tree.Remove(name)
+}
+
+// setstat changes the size, permission-bits, and/or modification-time of the file at 'name'.
+//
+// Changes to a directory are ignored.
+func (receiver *TreeHandler) setstat(name string, flags sftp.FileAttrFlags, attributes *sftp.FileStat) error {
+	fileinfo, err := receiver.directories.Stat(name)
+	if nil != err {
+		return err
+	}
+	if fileinfo.IsDir() {
+		if flags.Size {
+			return &fs.PathError{Op:"setstat", Path:name, Err:errIsDirectory}
+		}
+		return nil
+	}
+
+	var diff strfs.Diff
+
+	if flags.Size {
+		data, err := fs.ReadFile(receiver.tree, name)
+		if nil != err {
+			return err
+		}
+
+		if maxFileSize < attributes.Size {
+			return &fs.PathError{Op:"setstat", Path:name, Err:errFileTooBig}
+		}
+		if attributes.Size <= uint64(len(data)) {
+			data = data[:attributes.Size]
+		} else {
+			data = append(data, make([]byte, attributes.Size-uint64(len(data)))...)
+		}
+		diff = append(diff, strfs.Change{Name:name, Kind:strfs.ChangeModified, New:strfs.RegularFile{FileContent:strfs.CreateContent(string(data))}})
+	}
+	if flags.Permissions {
+		diff = append(diff, strfs.Change{Name:name, Kind:strfs.ChangeModeChanged, New:strfs.RegularFile{FileMode:attributes.FileMode().Perm()}})
+	}
+	if flags.Acmodtime {
+		diff = append(diff, strfs.Change{Name:name, Kind:strfs.ChangeModTimeChanged, New:strfs.RegularFile{FileModTime:time.Unix(int64(attributes.Mtime), 0)}})
+	}
+
+	return receiver.tree.Apply(diff)
+}
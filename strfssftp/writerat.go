@@ -0,0 +1,76 @@
+package strfssftp
+
+import (
+	"io"
+	"io/fs"
+	"sync"
+
+	"codeberg.org/reiver/go-strfs"
+)
+
+// internalWriterAt is the io.WriterAt returned by the Filewrite method of strfssftp.TreeHandler.
+//
+// What is written is buffered, and written to the strfs.Tree when it is closed.
+// (The sftp.RequestServer closes it when the client closes the file.)
+type internalWriterAt struct {
+	tree *strfs.Tree
+	name string
+	perm fs.FileMode
+
+	mutex sync.Mutex
+	data []byte
+	appending bool
+	dirty bool
+}
+
+// A trick to make sure *strfssftp.internalWriterAt fits the io.WriterAt and io.Closer interfaces.
+// This is a compile-time check.
+var _ io.WriterAt = &internalWriterAt{}
+var _ io.Closer = &internalWriterAt{}
+
+// Close writes what was written to the strfs.Tree.
+func (receiver *internalWriterAt) Close() error {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if !receiver.dirty {
+		return nil
+	}
+
+	if err := receiver.tree.WriteFile(receiver.name, string(receiver.data), receiver.perm); nil != err {
+		return err
+	}
+	receiver.dirty = false
+	return nil
+}
+
+// WriteAt writes 'p' at 'offset' — or, if the file was opened for appending, at the end (whatever 'offset' is).
+//
+// A file cannot be made bigger than maxFileSize.
+//
+// (The sftp.RequestServer might call WriteAt from more than one goroutine at the same time.)
+func (receiver *internalWriterAt) WriteAt(p []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, &fs.PathError{Op:"write", Path:receiver.name, Err:fs.ErrInvalid}
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if receiver.appending {
+		offset = int64(len(receiver.data))
+	}
+
+	// (Checked this way, rather than with offset+len(p), so that a huge offset cannot overflow.)
+	if maxFileSize < offset || maxFileSize-offset < int64(len(p)) {
+		return 0, &fs.PathError{Op:"write", Path:receiver.name, Err:errFileTooBig}
+	}
+
+	if end := offset + int64(len(p)); int64(len(receiver.data)) < end {
+		receiver.data = append(receiver.data, make([]byte, end-int64(len(receiver.data)))...)
+	}
+	copy(receiver.data[offset:], p)
+	receiver.dirty = true
+
+	return len(p), nil
+}
@@ -0,0 +1,43 @@
+package strfstest
+
+import (
+	"fmt"
+)
+
+// The operations that a strfstest.RecordingFS records.
+const (
+	OperationClose   = "close"
+	OperationOpen    = "open"
+	OperationRead    = "read"
+	OperationReadDir = "readdir"
+	OperationSeek    = "seek"
+	OperationStat    = "stat"
+)
+
+// Access is a single operation recorded by a strfstest.RecordingFS.
+type Access struct {
+	// Operation is one of OperationClose, OperationOpen, OperationRead, OperationReadDir, OperationSeek, or OperationStat.
+	Operation string
+
+	// Path is the (fs.FS) path of the file the operation was on.
+	Path string
+
+	// Bytes is the number of bytes read (for OperationRead),
+	// the new offset (for OperationSeek),
+	// or the number of directory entries returned (for OperationReadDir).
+	Bytes int64
+
+	// Err is the error the operation returned (if any).
+	Err error
+}
+
+// String returns a human-readable version of the strfstest.Access.
+//
+// String makes strfstest.Access fit the fmt.Stringer interface.
+func (receiver Access) String() string {
+	if nil != receiver.Err {
+		return fmt.Sprintf("%s %q %d (error: %s)", receiver.Operation, receiver.Path, receiver.Bytes, receiver.Err)
+	}
+
+	return fmt.Sprintf("%s %q %d", receiver.Operation, receiver.Path, receiver.Bytes)
+}
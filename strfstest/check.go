@@ -0,0 +1,123 @@
+package strfstest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/reiver/go-erorr"
+)
+
+func formatFileInfo(info fs.FileInfo) string {
+	if nil == info {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("name=%q size=%d mode=%v modtime=%v isdir=%t", info.Name(), info.Size(), info.Mode(), info.ModTime(), info.IsDir())
+}
+
+func testClose(closer io.Closer) error {
+	if err := closer.Close(); nil != err {
+		return erorr.Errorf("Close returned an error: %w", err)
+	}
+
+	if reader, casted := closer.(io.Reader); casted {
+		var b [1]byte
+		if _, err := reader.Read(b[:]); nil == err {
+			return erorr.Errorf("Read after Close did not return an error")
+		}
+	}
+
+	if err := closer.Close(); nil != err {
+		return erorr.Errorf("Close (called a second time) returned an error: %w", err)
+	}
+
+	return nil
+}
+
+func testRead(reader io.Reader, expected string) error {
+	actual, err := io.ReadAll(reader)
+	if nil != err {
+		return erorr.Errorf("Read returned an error: %w", err)
+	}
+	if !bytes.Equal([]byte(expected), actual) {
+		return erorr.Errorf("Read returned %q but expected %q", actual, expected)
+	}
+
+	return nil
+}
+
+func testReadAt(readerAt io.ReaderAt, expected string) error {
+	for offset := 0; offset <= len(expected); offset++ {
+		var p []byte = make([]byte, len(expected)-offset)
+
+		n, err := readerAt.ReadAt(p, int64(offset))
+		if nil != err && io.EOF != err {
+			return erorr.Errorf("ReadAt(%d) returned an error: %w", offset, err)
+		}
+		if expected, actual := expected[offset:], string(p[:n]); expected != actual {
+			return erorr.Errorf("ReadAt(%d) returned %q but expected %q", offset, actual, expected)
+		}
+	}
+
+	{
+		var b [1]byte
+		n, err := readerAt.ReadAt(b[:], int64(len(expected)))
+		if 0 != n || io.EOF != err {
+			return erorr.Errorf("ReadAt past the end returned (%d, %v) but expected (0, io.EOF)", n, err)
+		}
+	}
+
+	return nil
+}
+
+func testSeek(readSeeker io.ReadSeeker, expected string) error {
+	var size int64 = int64(len(expected))
+
+	tests := []struct{
+		Start    int64
+		Offset   int64
+		Whence   int
+		Expected int64
+	}{
+		{Start: 0,      Offset: 0,       Whence: io.SeekStart,   Expected: 0},
+		{Start: 0,      Offset: size,    Whence: io.SeekStart,   Expected: size},
+		{Start: size,   Offset: -size,   Whence: io.SeekCurrent, Expected: 0},
+		{Start: 0,      Offset: size/2,  Whence: io.SeekCurrent, Expected: size/2},
+		{Start: size/2, Offset: size/4,  Whence: io.SeekCurrent, Expected: size/2+size/4},
+		{Start: 0,      Offset: 0,       Whence: io.SeekEnd,     Expected: size},
+		{Start: 0,      Offset: -size/3, Whence: io.SeekEnd,     Expected: size-size/3},
+	}
+
+	for _, test := range tests {
+		if _, err := readSeeker.Seek(test.Start, io.SeekStart); nil != err {
+			return erorr.Errorf("Seek(%d, io.SeekStart) returned an error: %w", test.Start, err)
+		}
+
+		position, err := readSeeker.Seek(test.Offset, test.Whence)
+		if nil != err {
+			return erorr.Errorf("Seek(%d, %d) returned an error: %w", test.Offset, test.Whence, err)
+		}
+		if expected, actual := test.Expected, position; expected != actual {
+			return erorr.Errorf("Seek(%d, %d) returned position %d but expected %d", test.Offset, test.Whence, actual, expected)
+		}
+
+		if err := testRead(readSeeker, expected[position:]); nil != err {
+			return erorr.Errorf("after Seek(%d, %d): %w", test.Offset, test.Whence, err)
+		}
+	}
+
+	if _, err := readSeeker.Seek(0, 3); nil == err {
+		return erorr.Errorf("Seek with an invalid whence did not return an error")
+	}
+	if _, err := readSeeker.Seek(-1, io.SeekStart); nil == err {
+		return erorr.Errorf("Seek to a negative position did not return an error")
+	}
+
+	if _, err := readSeeker.Seek(0, io.SeekStart); nil != err {
+		return erorr.Errorf("Seek(0, io.SeekStart) returned an error: %w", err)
+	}
+
+	return nil
+}
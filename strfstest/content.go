@@ -0,0 +1,15 @@
+package strfstest
+
+import (
+	"io"
+)
+
+// Content is the method-set that all of the strfs content types have in common.
+//
+// For example: *strfs.Content, *strfs.MultiContent, *strfs.NormalizedContent, *strfs.RopeContent, and *strfs.TranscodedContent.
+type Content interface {
+	io.ReadSeekCloser
+	Closed() bool
+	Size() int64
+	String() string
+}
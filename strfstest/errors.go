@@ -0,0 +1,14 @@
+package strfstest
+
+import (
+	"github.com/reiver/go-erorr"
+)
+
+const (
+	errNilContent  = erorr.Error("nil content")
+	errNilFile     = erorr.Error("nil file")
+	errNilFS       = erorr.Error("nil file-system")
+	errNilReceiver = erorr.Error("nil receiver")
+	errNotSeeker   = erorr.Error("not a seeker")
+	errNotStater   = erorr.Error("not a stater")
+)
@@ -0,0 +1,54 @@
+package strfstest
+
+import (
+	"time"
+)
+
+// Faults says which faults a strfstest.FaultyFile should inject.
+//
+// The zero value injects no faults.
+//
+// Example usage:
+//
+//	var faults strfstest.Faults = strfstest.Faults{
+//		MaxReadSize:    3,
+//		ReadError:      io.ErrUnexpectedEOF,
+//		ReadErrorAfter: 10,
+//	}
+type Faults struct {
+	// MaxReadSize, if not zero, is the most number of bytes that each Read will return.
+	// (I.e., it causes short reads.)
+	MaxReadSize int
+
+	// RandomShortReads, if true, makes each Read return a pseudo-random number of bytes
+	// between 1 and len(p) (or MaxReadSize, if that is smaller).
+	//
+	// The pseudo-random numbers come from Seed, so a given Seed always produces the same short reads.
+	RandomShortReads bool
+
+	// ReadError, if not nil, is returned by Read once ReadErrorAfter bytes have been read.
+	//
+	// For example, io.ErrUnexpectedEOF.
+	//
+	// The Read that reaches ReadErrorAfter bytes is cut short (so it ends right at ReadErrorAfter bytes), and returns no error.
+	// Every Read after that returns ReadError.
+	ReadError error
+	ReadErrorAfter int64
+
+	// ReadDelay, if not zero, is how long each Read waits before returning.
+	// (I.e., it causes slow reads.)
+	ReadDelay time.Duration
+
+	// SeekError, if not nil, is returned by every Seek.
+	SeekError error
+
+	// StatError, if not nil, is returned by every Stat.
+	StatError error
+
+	// CloseError, if not nil, is returned by every Close.
+	// (The wrapped file is still closed.)
+	CloseError error
+
+	// Seed seeds the pseudo-random numbers used by RandomShortReads.
+	Seed int64
+}
@@ -0,0 +1,157 @@
+package strfstest
+
+import (
+	"io"
+	"io/fs"
+	"math/rand"
+	"time"
+)
+
+// FaultyFile wraps a strfs file (such as a *strfs.RegularFile) or strfs content (such as a *strfs.Content),
+// and injects faults into it — short reads, read errors, slow reads, seek errors, stat errors, and close errors.
+//
+// FaultyFile is for testing error-handling code.
+//
+// Example usage:
+//
+//	var regularfile strfs.RegularFile = strfs.RegularFile{
+//		FileContent: strfs.CreateContent("once twice thrice fource"),
+//		FileName:    "file4.txt",
+//	}
+//
+//	var faultyfile strfstest.FaultyFile = strfstest.CreateFaultyFile(&regularfile, strfstest.Faults{
+//		RandomShortReads: true,
+//		ReadError:        io.ErrUnexpectedEOF,
+//		ReadErrorAfter:   10,
+//		Seed:             1974,
+//	})
+//
+//	var file fs.File = &faultyfile
+type FaultyFile struct {
+	file io.ReadCloser
+	faults Faults
+	randomness *rand.Rand
+	count int64
+}
+
+var (
+	// A trick to make sure strfstest.FaultyFile fits the fs.File interface.
+	// This is a compile-time check.
+	_ fs.File = &FaultyFile{}
+
+	// A trick to make sure strfstest.FaultyFile fits the io.ReadSeekCloser interface.
+	// This is a compile-time check.
+	_ io.ReadSeekCloser = &FaultyFile{}
+)
+
+// CreateFaultyFile returns a strfstest.FaultyFile that wraps 'file' and injects the faults in 'faults'.
+//
+// If 'file' does NOT have a Stat method, then Stat returns an error.
+// If 'file' does NOT have a Seek method, then Seek returns an error.
+func CreateFaultyFile(file io.ReadCloser, faults Faults) FaultyFile {
+	return FaultyFile{
+		file:file,
+		faults:faults,
+		randomness:rand.New(rand.NewSource(faults.Seed)),
+	}
+}
+
+// Close closes the wrapped file, and then returns Faults.CloseError (if there is one).
+func (receiver *FaultyFile) Close() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if nil == receiver.file {
+		return errNilFile
+	}
+
+	err := receiver.file.Close()
+	if nil != receiver.faults.CloseError {
+		return receiver.faults.CloseError
+	}
+	return err
+}
+
+// Count returns how many bytes have been read (in total) through the strfstest.FaultyFile.
+func (receiver *FaultyFile) Count() int64 {
+	if nil == receiver {
+		return 0
+	}
+
+	return receiver.count
+}
+
+// Read reads from the wrapped file, injecting short reads, read errors, and slow reads (as configured).
+func (receiver *FaultyFile) Read(p []byte) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+	if nil == receiver.file {
+		return 0, errNilFile
+	}
+
+	var faults Faults = receiver.faults
+
+	if 0 < faults.ReadDelay {
+		time.Sleep(faults.ReadDelay)
+	}
+
+	if nil != faults.ReadError {
+		var remaining int64 = faults.ReadErrorAfter - receiver.count
+		if remaining <= 0 {
+			return 0, faults.ReadError
+		}
+		if remaining < int64(len(p)) {
+			p = p[:remaining]
+		}
+	}
+
+	if 0 < faults.MaxReadSize && faults.MaxReadSize < len(p) {
+		p = p[:faults.MaxReadSize]
+	}
+
+	if faults.RandomShortReads && 1 < len(p) {
+		p = p[:1+receiver.randomness.Intn(len(p))]
+	}
+
+	n, err := receiver.file.Read(p)
+	receiver.count += int64(n)
+	return n, err
+}
+
+// Seek returns Faults.SeekError (if there is one), else seeks the wrapped file.
+func (receiver *FaultyFile) Seek(offset int64, whence int) (int64, error) {
+	if nil == receiver {
+		var nada int64
+		return nada, errNilReceiver
+	}
+	if nil != receiver.faults.SeekError {
+		var nada int64
+		return nada, receiver.faults.SeekError
+	}
+
+	seeker, casted := receiver.file.(io.Seeker)
+	if !casted {
+		var nada int64
+		return nada, errNotSeeker
+	}
+
+	return seeker.Seek(offset, whence)
+}
+
+// Stat returns Faults.StatError (if there is one), else the wrapped file's Stat.
+func (receiver *FaultyFile) Stat() (fs.FileInfo, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+	if nil != receiver.faults.StatError {
+		return nil, receiver.faults.StatError
+	}
+
+	stater, casted := receiver.file.(interface{ Stat() (fs.FileInfo, error) })
+	if !casted {
+		return nil, errNotStater
+	}
+
+	return stater.Stat()
+}
@@ -0,0 +1,153 @@
+package strfstest_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfstest"
+
+	"errors"
+	"io"
+
+	"testing"
+)
+
+func faultyRegularFile(content string, faults strfstest.Faults) *strfstest.FaultyFile {
+	var regularfile strfs.RegularFile = strfs.RegularFile{
+		FileContent: strfs.CreateContent(content),
+		FileName:    "faulty.txt",
+	}
+
+	var faultyfile strfstest.FaultyFile = strfstest.CreateFaultyFile(&regularfile, faults)
+	return &faultyfile
+}
+
+func TestFaultyFile_shortReads(t *testing.T) {
+
+	const content string = "once twice thrice fource"
+
+	var faultyfile *strfstest.FaultyFile = faultyRegularFile(content, strfstest.Faults{MaxReadSize: 3})
+
+	var b [10]byte
+	n, err := faultyfile.Read(b[:])
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+	if expected, actual := 3, n; expected != actual {
+		t.Errorf("The actual number-of-bytes read was not what was expected.")
+		t.Logf("EXPECTED NUMBER-BYTES-READ: %d", expected)
+		t.Logf("ACTUAL   NUMBER-BYTES-READ: %d", actual)
+		return
+	}
+
+	rest, err := io.ReadAll(faultyfile)
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+	if expected, actual := content, string(b[:n])+string(rest); expected != actual {
+		t.Errorf("The actual content was not what was expected.")
+		t.Logf("EXPECTED CONTENT: %q", expected)
+		t.Logf("ACTUAL   CONTENT: %q", actual)
+		return
+	}
+}
+
+func TestFaultyFile_readError(t *testing.T) {
+
+	const content string = "once twice thrice fource"
+
+	var faultyfile *strfstest.FaultyFile = faultyRegularFile(content, strfstest.Faults{
+		ReadError:      io.ErrUnexpectedEOF,
+		ReadErrorAfter: 10,
+	})
+
+	actual, err := io.ReadAll(faultyfile)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("The actual error was not what was expected.")
+		t.Logf("EXPECTED ERROR: %s", io.ErrUnexpectedEOF)
+		t.Logf("ACTUAL   ERROR: %v", err)
+		return
+	}
+	if expected, actual := content[:10], string(actual); expected != actual {
+		t.Errorf("The actual content read before the error was not what was expected.")
+		t.Logf("EXPECTED CONTENT: %q", expected)
+		t.Logf("ACTUAL   CONTENT: %q", actual)
+		return
+	}
+}
+
+func TestFaultyFile_seed(t *testing.T) {
+
+	const content string = "once twice thrice fource"
+
+	var faults strfstest.Faults = strfstest.Faults{
+		RandomShortReads: true,
+		Seed:             1974,
+	}
+
+	readSizes := func() []int {
+		var faultyfile *strfstest.FaultyFile = faultyRegularFile(content, faults)
+
+		var sizes []int
+		for {
+			var b [8]byte
+			n, err := faultyfile.Read(b[:])
+			if io.EOF == err {
+				return sizes
+			}
+			if nil != err {
+				t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+			}
+			sizes = append(sizes, n)
+		}
+	}
+
+	var sizes1 []int = readSizes()
+	var sizes2 []int = readSizes()
+
+	if expected, actual := sizes1, sizes2; len(expected) != len(actual) {
+		t.Errorf("Expected the same seed to produce the same short reads but actually didn't.")
+		t.Logf("SIZES #1: %v", expected)
+		t.Logf("SIZES #2: %v", actual)
+		return
+	}
+	for i := range sizes1 {
+		if expected, actual := sizes1[i], sizes2[i]; expected != actual {
+			t.Errorf("Expected the same seed to produce the same short reads but actually didn't.")
+			t.Logf("SIZES #1: %v", sizes1)
+			t.Logf("SIZES #2: %v", sizes2)
+			return
+		}
+	}
+}
+
+func TestFaultyFile_errors(t *testing.T) {
+
+	var seekError  error = errors.New("seek error")
+	var statError  error = errors.New("stat error")
+	var closeError error = errors.New("close error")
+
+	var faultyfile *strfstest.FaultyFile = faultyRegularFile("once", strfstest.Faults{
+		SeekError:  seekError,
+		StatError:  statError,
+		CloseError: closeError,
+	})
+
+	if _, err := faultyfile.Seek(0, io.SeekStart); seekError != err {
+		t.Errorf("The actual seek error was not what was expected.")
+		t.Logf("EXPECTED ERROR: %v", seekError)
+		t.Logf("ACTUAL   ERROR: %v", err)
+	}
+	if _, err := faultyfile.Stat(); statError != err {
+		t.Errorf("The actual stat error was not what was expected.")
+		t.Logf("EXPECTED ERROR: %v", statError)
+		t.Logf("ACTUAL   ERROR: %v", err)
+	}
+	if err := faultyfile.Close(); closeError != err {
+		t.Errorf("The actual close error was not what was expected.")
+		t.Logf("EXPECTED ERROR: %v", closeError)
+		t.Logf("ACTUAL   ERROR: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package strfstest
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"codeberg.org/reiver/go-strfs"
+)
+
+// UpdateGoldenEnv is the name of the environment-variable that makes GoldenTxtar and GoldenDir rewrite the golden files
+// (rather than check against them).
+//
+// For example:
+//
+//	STRFS_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "STRFS_UPDATE_GOLDEN"
+
+// updating returns whether the STRFS_UPDATE_GOLDEN environment-variable is set to true (such as "1" or "true").
+func updating() bool {
+	value, err := strconv.ParseBool(os.Getenv(UpdateGoldenEnv))
+	return nil == err && value
+}
+
+// Golden checks that the regular files of 'actual' are the same as the regular files of 'expected' (such as a strfs.FS).
+//
+// Each file that is missing, unexpected, or has different content is reported with tb.Errorf
+// (along with a unified diff, for a file with different content).
+// Modification-times and permission-bits are NOT compared.
+//
+// To compare against txtar text (rather than a file-system), use strfs.ImportTxtar on it first.
+//
+// Typical usage inside a test is:
+//
+//	strfstest.Golden(t, expected, actual)
+func Golden(tb testing.TB, expected fs.FS, actual fs.FS) {
+	tb.Helper()
+
+	golden(tb, expected, actual, "")
+}
+
+func golden(tb testing.TB, expected fs.FS, actual fs.FS, hint string) {
+	tb.Helper()
+
+	diff, err := strfs.DiffFS(expected, actual)
+	if nil != err {
+		tb.Errorf("strfstest: problem comparing file-systems: %s%s", err, hint)
+		return
+	}
+
+	for _, change := range diff {
+		switch {
+		case 0 != change.Kind & strfs.ChangeAdded:
+			tb.Errorf("strfstest: unexpected file %q%s\n%s", change.Name, hint, change.UnifiedDiff())
+		case 0 != change.Kind & strfs.ChangeRemoved:
+			tb.Errorf("strfstest: missing file %q%s", change.Name, hint)
+		case 0 != change.Kind & strfs.ChangeModified:
+			tb.Errorf("strfstest: file %q is not what was expected%s\n%s", change.Name, hint, change.UnifiedDiff())
+		}
+	}
+}
+
+// GoldenDir is like Golden, except that the expected files are the files in the directory 'dirname'.
+//
+// If the tests are run with the STRFS_UPDATE_GOLDEN environment-variable set (i.e., "STRFS_UPDATE_GOLDEN=1 go test"), then nothing is checked.
+// Instead, the directory is rewritten to have the files of 'actual' — files in the directory that are NOT in 'actual' are removed.
+//
+// Typical usage inside a test is:
+//
+//	strfstest.GoldenDir(t, "testdata/site", actual)
+func GoldenDir(tb testing.TB, dirname string, actual fs.FS) {
+	tb.Helper()
+
+	if updating() {
+		if err := updateDir(dirname, actual); nil != err {
+			tb.Errorf("strfstest: problem updating golden directory %q: %s", dirname, err)
+			return
+		}
+		tb.Logf("strfstest: updated golden directory %q", dirname)
+		return
+	}
+
+	golden(tb, os.DirFS(dirname), actual, " (run the tests with "+UpdateGoldenEnv+"=1 to update the golden directory "+strconv.Quote(dirname)+")")
+}
+
+// GoldenTxtar is like Golden, except that the expected files are the files in the txtar archive 'filename'.
+//
+// Because a file in a txtar archive always ends in a newline, a file in 'actual' that does NOT end in a newline
+// is compared as if it did.
+//
+// If the tests are run with the STRFS_UPDATE_GOLDEN environment-variable set (i.e., "STRFS_UPDATE_GOLDEN=1 go test"), then nothing is checked.
+// Instead, 'filename' is rewritten to have the files of 'actual'.
+//
+// Typical usage inside a test is:
+//
+//	strfstest.GoldenTxtar(t, "testdata/site.txtar", actual)
+func GoldenTxtar(tb testing.TB, filename string, actual fs.FS) {
+	tb.Helper()
+
+	var buffer bytes.Buffer
+	if err := strfs.ExportTxtar(&buffer, actual); nil != err {
+		tb.Errorf("strfstest: problem turning the actual file-system into a txtar archive: %s", err)
+		return
+	}
+
+	if updating() {
+		if err := os.WriteFile(filename, buffer.Bytes(), 0644); nil != err {
+			tb.Errorf("strfstest: problem updating golden txtar file %q: %s", filename, err)
+			return
+		}
+		tb.Logf("strfstest: updated golden txtar file %q", filename)
+		return
+	}
+
+	var hint string = " (run the tests with " + UpdateGoldenEnv + "=1 to update the golden txtar file " + strconv.Quote(filename) + ")"
+
+	// What 'actual' looks like after a trip through the txtar format.
+	normalized, err := strfs.ImportTxtar(&buffer)
+	if nil != err {
+		tb.Errorf("strfstest: problem reading back the actual file-system as a txtar archive: %s", err)
+		return
+	}
+
+	file, err := os.Open(filename)
+	if nil != err {
+		tb.Errorf("strfstest: problem opening golden txtar file: %s%s", err, hint)
+		return
+	}
+	defer file.Close()
+
+	expected, err := strfs.ImportTxtar(file)
+	if nil != err {
+		tb.Errorf("strfstest: problem reading golden txtar file %q: %s", filename, err)
+		return
+	}
+
+	golden(tb, expected, normalized, hint)
+}
+
+// updateDir makes the directory 'dirname' have (just) the regular files of 'fsys'.
+func updateDir(dirname string, fsys fs.FS) error {
+	var wanted map[string]struct{} = map[string]struct{}{}
+
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if nil != err {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		wanted[name] = struct{}{}
+
+		data, err := fs.ReadFile(fsys, name)
+		if nil != err {
+			return err
+		}
+
+		var path string = filepath.Join(dirname, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); nil != err {
+			return err
+		}
+		return os.WriteFile(path, data, 0644)
+	})
+	if nil != err {
+		return err
+	}
+
+	return fs.WalkDir(os.DirFS(dirname), ".", func(name string, entry fs.DirEntry, err error) error {
+		if nil != err {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if _, found := wanted[name]; found {
+			return nil
+		}
+
+		return os.Remove(filepath.Join(dirname, filepath.FromSlash(name)))
+	})
+}
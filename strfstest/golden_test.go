@@ -0,0 +1,191 @@
+package strfstest_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfstest"
+
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"testing"
+)
+
+// A package that imports strfstest can still have its own -update flag.
+// (If strfstest registered an -update flag itself, then this would panic with "flag redefined: update".)
+var update = flag.Bool("update", false, "update")
+
+// recordingTB is a testing.TB that records the errors reported to it (rather than failing the test).
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (receiver *recordingTB) Errorf(format string, a ...any) {
+	receiver.errors = append(receiver.errors, fmt.Sprintf(format, a...))
+}
+
+func (receiver *recordingTB) Helper() {}
+
+func (receiver *recordingTB) Logf(string, ...any) {}
+
+func TestGolden(t *testing.T) {
+
+	var expected strfs.FS = strfs.FS{
+		"same.txt":     strfs.RegularFile{FileContent: strfs.CreateContent("same\n")},
+		"missing.txt":  strfs.RegularFile{FileContent: strfs.CreateContent("missing\n")},
+		"changed.txt":  strfs.RegularFile{FileContent: strfs.CreateContent("once\ntwice\n")},
+	}
+
+	var actual strfs.FS = strfs.FS{
+		"same.txt":     strfs.RegularFile{FileContent: strfs.CreateContent("same\n"), FileMode: 0600},
+		"extra.txt":    strfs.RegularFile{FileContent: strfs.CreateContent("extra\n")},
+		"changed.txt":  strfs.RegularFile{FileContent: strfs.CreateContent("once\nthrice\n")},
+	}
+
+	var tb recordingTB = recordingTB{TB:t}
+	strfstest.Golden(&tb, expected, actual)
+
+	if expected, actual := 3, len(tb.errors); expected != actual {
+		t.Fatalf("The actual number of errors was not what was expected: expected %d, actually %d: %q", expected, actual, tb.errors)
+	}
+
+	for i, expected := range []string{
+		`strfstest: file "changed.txt" is not what was expected` + "\n" +
+			"--- a/changed.txt" + "\n" +
+			"+++ b/changed.txt" + "\n" +
+			"@@ -1,2 +1,2 @@" + "\n" +
+			" once" + "\n" +
+			"-twice" + "\n" +
+			"+thrice" + "\n",
+		`strfstest: unexpected file "extra.txt"`,
+		`strfstest: missing file "missing.txt"`,
+	} {
+		if actual := tb.errors[i]; !strings.HasPrefix(actual, expected) {
+			t.Errorf("For error #%d, the actual error was not what was expected.", i)
+			t.Logf("EXPECTED ERROR: %q", expected)
+			t.Logf("ACTUAL   ERROR: %q", actual)
+		}
+	}
+
+	{
+		var tb recordingTB = recordingTB{TB:t}
+		strfstest.Golden(&tb, expected, expected)
+
+		if 0 != len(tb.errors) {
+			t.Errorf("Did not expect any errors but actually got some: %q", tb.errors)
+		}
+	}
+}
+
+func TestGoldenTxtar(t *testing.T) {
+
+	var filename string = filepath.Join(t.TempDir(), "golden.txtar")
+
+	var actual strfs.FS = strfs.FS{
+		"index.html":    strfs.RegularFile{FileContent: strfs.CreateContent("<!DOCTYPE html>")},
+		"css/style.css": strfs.RegularFile{FileContent: strfs.CreateContent("body{color:red}\n")},
+	}
+
+	{
+		var tb recordingTB = recordingTB{TB:t}
+		strfstest.GoldenTxtar(&tb, filename, actual)
+
+		if expected, actual := 1, len(tb.errors); expected != actual {
+			t.Fatalf("Expected an error for a golden file that does not exist: %q", tb.errors)
+		}
+		if !strings.Contains(tb.errors[0], strfstest.UpdateGoldenEnv) {
+			t.Errorf("Expected the error to mention %s: %q", strfstest.UpdateGoldenEnv, tb.errors[0])
+		}
+	}
+
+	withUpdate(t, func() {
+		var tb recordingTB = recordingTB{TB:t}
+		strfstest.GoldenTxtar(&tb, filename, actual)
+
+		if 0 != len(tb.errors) {
+			t.Fatalf("Did not expect any errors but actually got some: %q", tb.errors)
+		}
+	})
+
+	{
+		data, err := os.ReadFile(filename)
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		if expected, actual := "-- css/style.css --\nbody{color:red}\n-- index.html --\n<!DOCTYPE html>\n", string(data); expected != actual {
+			t.Errorf("The actual golden txtar file was not what was expected.")
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+		}
+	}
+
+	{
+		var tb recordingTB = recordingTB{TB:t}
+		strfstest.GoldenTxtar(&tb, filename, actual)
+
+		if 0 != len(tb.errors) {
+			t.Errorf("Did not expect any errors but actually got some: %q", tb.errors)
+		}
+	}
+}
+
+func TestGoldenDir(t *testing.T) {
+
+	var dirname string = t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dirname, "stale.txt"), []byte("stale\n"), 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var actual strfs.FS = strfs.FS{
+		"index.html":    strfs.RegularFile{FileContent: strfs.CreateContent("<!DOCTYPE html>")},
+		"css/style.css": strfs.RegularFile{FileContent: strfs.CreateContent("body{color:red}\n")},
+	}
+
+	{
+		var tb recordingTB = recordingTB{TB:t}
+		strfstest.GoldenDir(&tb, dirname, actual)
+
+		if expected, actual := 3, len(tb.errors); expected != actual {
+			t.Fatalf("The actual number of errors was not what was expected: expected %d, actually %d: %q", expected, actual, tb.errors)
+		}
+	}
+
+	withUpdate(t, func() {
+		var tb recordingTB = recordingTB{TB:t}
+		strfstest.GoldenDir(&tb, dirname, actual)
+
+		if 0 != len(tb.errors) {
+			t.Fatalf("Did not expect any errors but actually got some: %q", tb.errors)
+		}
+	})
+
+	if _, err := os.Stat(filepath.Join(dirname, "stale.txt")); !os.IsNotExist(err) {
+		t.Errorf("Expected the stale file to be removed from the golden directory.")
+	}
+
+	{
+		var tb recordingTB = recordingTB{TB:t}
+		strfstest.GoldenDir(&tb, dirname, actual)
+
+		if 0 != len(tb.errors) {
+			t.Errorf("Did not expect any errors but actually got some: %q", tb.errors)
+		}
+	}
+}
+
+// withUpdate runs 'fn' as if the tests were run with the STRFS_UPDATE_GOLDEN environment-variable set.
+func withUpdate(t *testing.T, fn func()) {
+	t.Helper()
+
+	if err := os.Setenv(strfstest.UpdateGoldenEnv, "1"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	defer os.Unsetenv(strfstest.UpdateGoldenEnv)
+
+	fn()
+}
@@ -0,0 +1,271 @@
+package strfstest
+
+import (
+	"io"
+	"io/fs"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// RecordingFS wraps a fs.FS (such as a strfs file-system) and records every
+// Open, Stat, ReadDir, Read, Seek, and Close done through it.
+//
+// RecordingFS is for tests that want to assert which files were opened, how many bytes were read from them,
+// and whether they were closed.
+//
+// RecordingFS is safe to use from multiple goroutines.
+//
+// Example usage:
+//
+//	var recordingfs strfstest.RecordingFS = strfstest.CreateRecordingFS(fsys)
+//	recordingfs.ReportLeaks(t)
+//
+//	// ...
+//
+//	if expected, actual := int64(15), recordingfs.BytesRead("index.html"); expected != actual {
+//		t.Errorf("expected %d bytes to be read but actually %d were", expected, actual)
+//	}
+type RecordingFS struct {
+	fsys fs.FS
+	mutex *sync.Mutex
+	state *internalRecordingState
+}
+
+type internalRecordingState struct {
+	accesses []Access
+	opened map[*recordingFile]string
+}
+
+var (
+	// A trick to make sure strfstest.RecordingFS fits the fs.FS interface.
+	// This is a compile-time check.
+	_ fs.FS = RecordingFS{}
+
+	// A trick to make sure strfstest.RecordingFS fits the fs.ReadDirFS interface.
+	// This is a compile-time check.
+	_ fs.ReadDirFS = RecordingFS{}
+
+	// A trick to make sure strfstest.RecordingFS fits the fs.StatFS interface.
+	// This is a compile-time check.
+	_ fs.StatFS = RecordingFS{}
+)
+
+// CreateRecordingFS returns a strfstest.RecordingFS that wraps (and records all the accesses to) 'fsys'.
+func CreateRecordingFS(fsys fs.FS) RecordingFS {
+	return RecordingFS{
+		fsys:fsys,
+		mutex:new(sync.Mutex),
+		state:&internalRecordingState{
+			opened:map[*recordingFile]string{},
+		},
+	}
+}
+
+// Accesses returns (a copy of) everything that has been recorded, in the order it happened.
+func (receiver RecordingFS) Accesses() []Access {
+	if nil == receiver.state {
+		return nil
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	return append([]Access(nil), receiver.state.accesses...)
+}
+
+// BytesRead returns the total number of bytes read from the file at 'path' (across every time it was opened).
+func (receiver RecordingFS) BytesRead(path string) int64 {
+	var total int64
+	for _, access := range receiver.Accesses() {
+		if OperationRead == access.Operation && path == access.Path {
+			total += access.Bytes
+		}
+	}
+
+	return total
+}
+
+// Count returns how many times 'operation' was done on the file at 'path'.
+func (receiver RecordingFS) Count(operation string, path string) int {
+	var count int
+	for _, access := range receiver.Accesses() {
+		if operation == access.Operation && path == access.Path {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Leaked returns the paths of the files that were (successfully) opened, but have NOT been closed (yet).
+//
+// A path is returned once for each time it was leaked.
+func (receiver RecordingFS) Leaked() []string {
+	if nil == receiver.state {
+		return nil
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	var paths []string
+	for _, path := range receiver.state.opened {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// Open opens the file at 'name' in the wrapped fs.FS, and records it.
+//
+// Open makes strfstest.RecordingFS fit the fs.FS interface.
+func (receiver RecordingFS) Open(name string) (fs.File, error) {
+	if nil == receiver.fsys {
+		return nil, errNilFS
+	}
+
+	file, err := receiver.fsys.Open(name)
+	receiver.record(Access{Operation:OperationOpen, Path:name, Err:err})
+	if nil != err {
+		return nil, err
+	}
+
+	var recordingfile *recordingFile = &recordingFile{
+		file:file,
+		path:name,
+		recordingfs:receiver,
+	}
+
+	receiver.mutex.Lock()
+	receiver.state.opened[recordingfile] = name
+	receiver.mutex.Unlock()
+
+	if _, casted := file.(fs.ReadDirFile); casted {
+		return &recordingDir{recordingfile}, nil
+	}
+	return recordingfile, nil
+}
+
+// Opened returns the paths of every file that was (successfully) opened, in the order they were opened.
+//
+// A path is returned once for each time it was opened.
+func (receiver RecordingFS) Opened() []string {
+	var paths []string
+	for _, access := range receiver.Accesses() {
+		if OperationOpen == access.Operation && nil == access.Err {
+			paths = append(paths, access.Path)
+		}
+	}
+
+	return paths
+}
+
+// ReadDir reads the directory at 'name' in the wrapped fs.FS, and records it.
+//
+// ReadDir makes strfstest.RecordingFS fit the fs.ReadDirFS interface.
+func (receiver RecordingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if nil == receiver.fsys {
+		return nil, errNilFS
+	}
+
+	entries, err := fs.ReadDir(receiver.fsys, name)
+	receiver.record(Access{Operation:OperationReadDir, Path:name, Bytes:int64(len(entries)), Err:err})
+	return entries, err
+}
+
+func (receiver RecordingFS) record(access Access) {
+	if nil == receiver.state {
+		return
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	receiver.state.accesses = append(receiver.state.accesses, access)
+}
+
+// ReportLeaks makes the test report (with Errorf) every file that is still open when the test ends.
+func (receiver RecordingFS) ReportLeaks(tb testing.TB) {
+	tb.Helper()
+
+	tb.Cleanup(func() {
+		for _, path := range receiver.Leaked() {
+			tb.Errorf("strfstest: file %q was opened but never closed", path)
+		}
+	})
+}
+
+// Stat stats the file at 'name' in the wrapped fs.FS, and records it.
+//
+// Stat makes strfstest.RecordingFS fit the fs.StatFS interface.
+func (receiver RecordingFS) Stat(name string) (fs.FileInfo, error) {
+	if nil == receiver.fsys {
+		return nil, errNilFS
+	}
+
+	fileinfo, err := fs.Stat(receiver.fsys, name)
+	receiver.record(Access{Operation:OperationStat, Path:name, Err:err})
+	return fileinfo, err
+}
+
+type recordingFile struct {
+	file fs.File
+	path string
+	recordingfs RecordingFS
+}
+
+func (receiver *recordingFile) Close() error {
+	err := receiver.file.Close()
+	receiver.recordingfs.record(Access{Operation:OperationClose, Path:receiver.path, Err:err})
+
+	receiver.recordingfs.mutex.Lock()
+	delete(receiver.recordingfs.state.opened, receiver)
+	receiver.recordingfs.mutex.Unlock()
+
+	return err
+}
+
+func (receiver *recordingFile) Read(p []byte) (int, error) {
+	n, err := receiver.file.Read(p)
+	if io.EOF == err {
+		receiver.recordingfs.record(Access{Operation:OperationRead, Path:receiver.path, Bytes:int64(n)})
+	} else {
+		receiver.recordingfs.record(Access{Operation:OperationRead, Path:receiver.path, Bytes:int64(n), Err:err})
+	}
+	return n, err
+}
+
+func (receiver *recordingFile) Seek(offset int64, whence int) (int64, error) {
+	seeker, casted := receiver.file.(io.Seeker)
+	if !casted {
+		var nada int64
+		receiver.recordingfs.record(Access{Operation:OperationSeek, Path:receiver.path, Err:errNotSeeker})
+		return nada, errNotSeeker
+	}
+
+	position, err := seeker.Seek(offset, whence)
+	receiver.recordingfs.record(Access{Operation:OperationSeek, Path:receiver.path, Bytes:position, Err:err})
+	return position, err
+}
+
+func (receiver *recordingFile) Stat() (fs.FileInfo, error) {
+	fileinfo, err := receiver.file.Stat()
+	receiver.recordingfs.record(Access{Operation:OperationStat, Path:receiver.path, Err:err})
+	return fileinfo, err
+}
+
+type recordingDir struct {
+	*recordingFile
+}
+
+func (receiver *recordingDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries, err := receiver.file.(fs.ReadDirFile).ReadDir(n)
+	if io.EOF == err {
+		receiver.recordingfs.record(Access{Operation:OperationReadDir, Path:receiver.path, Bytes:int64(len(entries))})
+	} else {
+		receiver.recordingfs.record(Access{Operation:OperationReadDir, Path:receiver.path, Bytes:int64(len(entries)), Err:err})
+	}
+	return entries, err
+}
@@ -0,0 +1,145 @@
+package strfstest_test
+
+import (
+	"codeberg.org/reiver/go-strfs/strfstest"
+
+	"io"
+	"io/fs"
+	"testing/fstest"
+
+	"testing"
+)
+
+func TestRecordingFS(t *testing.T) {
+
+	var recordingfs strfstest.RecordingFS = strfstest.CreateRecordingFS(regularFileFS{
+		mapfs: fstest.MapFS{
+			"file1.txt":       &fstest.MapFile{Data: []byte("once")},
+			"gmni/file3.gmni": &fstest.MapFile{Data: []byte("once twice thrice")},
+			"gmni/file4.fngr": &fstest.MapFile{Data: []byte("once twice thrice fource")},
+		},
+	})
+
+	{
+		data, err := fs.ReadFile(recordingfs, "gmni/file3.gmni")
+		if nil != err {
+			t.Errorf("Did not expect an error but actually got one.")
+			t.Logf("ERROR: (%T) %s", err, err)
+			return
+		}
+		if expected, actual := "once twice thrice", string(data); expected != actual {
+			t.Errorf("The actual content was not what was expected.")
+			t.Logf("EXPECTED CONTENT: %q", expected)
+			t.Logf("ACTUAL   CONTENT: %q", actual)
+			return
+		}
+	}
+
+	var leaked fs.File
+	{
+		var err error
+
+		leaked, err = recordingfs.Open("gmni/file4.fngr")
+		if nil != err {
+			t.Errorf("Did not expect an error but actually got one.")
+			t.Logf("ERROR: (%T) %s", err, err)
+			return
+		}
+
+		var b [5]byte
+		_, err = io.ReadFull(leaked, b[:])
+		if nil != err {
+			t.Errorf("Did not expect an error but actually got one.")
+			t.Logf("ERROR: (%T) %s", err, err)
+			return
+		}
+	}
+
+	{
+		_, err := recordingfs.Open("does/not/exist.txt")
+		if nil == err {
+			t.Errorf("Expected an error but did not actually get one.")
+			return
+		}
+	}
+
+	{
+		entries, err := recordingfs.ReadDir("gmni")
+		if nil != err {
+			t.Errorf("Did not expect an error but actually got one.")
+			t.Logf("ERROR: (%T) %s", err, err)
+			return
+		}
+		if expected, actual := 2, len(entries); expected != actual {
+			t.Errorf("The actual number of directory entries was not what was expected.")
+			t.Logf("EXPECTED: %d", expected)
+			t.Logf("ACTUAL:   %d", actual)
+			return
+		}
+	}
+
+	{
+		var expected []string = []string{"gmni/file3.gmni", "gmni/file4.fngr"}
+		var actual   []string = recordingfs.Opened()
+
+		if len(expected) != len(actual) || expected[0] != actual[0] || expected[1] != actual[1] {
+			t.Errorf("The actual opened files were not what was expected.")
+			t.Logf("EXPECTED: %#v", expected)
+			t.Logf("ACTUAL:   %#v", actual)
+			return
+		}
+	}
+
+	if expected, actual := int64(len("once twice thrice")), recordingfs.BytesRead("gmni/file3.gmni"); expected != actual {
+		t.Errorf("The actual number of bytes read was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+		return
+	}
+	if expected, actual := int64(5), recordingfs.BytesRead("gmni/file4.fngr"); expected != actual {
+		t.Errorf("The actual number of bytes read was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+		return
+	}
+	if expected, actual := 1, recordingfs.Count(strfstest.OperationClose, "gmni/file3.gmni"); expected != actual {
+		t.Errorf("The actual number of closes was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+		return
+	}
+
+	{
+		var leakedPaths []string = recordingfs.Leaked()
+
+		if 1 != len(leakedPaths) || "gmni/file4.fngr" != leakedPaths[0] {
+			t.Errorf("The actual leaked files were not what was expected.")
+			t.Logf("LEAKED: %#v", leakedPaths)
+			return
+		}
+	}
+
+	leaked.Close()
+	if leakedPaths := recordingfs.Leaked(); 0 != len(leakedPaths) {
+		t.Errorf("Did not expect any leaked files but actually got some.")
+		t.Logf("LEAKED: %#v", leakedPaths)
+		return
+	}
+}
+
+func TestRecordingFS_testFS(t *testing.T) {
+
+	var recordingfs strfstest.RecordingFS = strfstest.CreateRecordingFS(regularFileFS{
+		mapfs: fstest.MapFS{
+			"file1.txt":       &fstest.MapFile{Data: []byte("once")},
+			"gmni/file3.gmni": &fstest.MapFile{Data: []byte("once twice thrice")},
+		},
+	})
+	recordingfs.ReportLeaks(t)
+
+	if err := strfstest.TestFS(recordingfs, "file1.txt", "gmni/file3.gmni"); nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+}
@@ -0,0 +1,64 @@
+package strfstest
+
+import (
+	"io"
+
+	"github.com/reiver/go-erorr"
+)
+
+// TestContent checks that a strfs content type behaves correctly.
+//
+// 'content' must NOT have been read from yet, and is closed by the time TestContent returns.
+// 'expected' is what the content is expected to be.
+//
+// TestContent checks that:
+//
+//	• Size and String are consistent with 'expected',
+//	• Read returns exactly 'expected',
+//	• Seek works with io.SeekStart, io.SeekCurrent, and io.SeekEnd, and fails for an invalid whence or a negative position,
+//	• ReadAt (if there is one) returns the correct bytes, and
+//	• after Close, Closed returns true, and Read fails (and Close can be called again).
+//
+// Typical usage inside a test is:
+//
+//	var content strfs.Content = strfs.CreateContent("Hello world!")
+//
+//	if err := strfstest.TestContent(&content, "Hello world!"); nil != err {
+//		t.Fatal(err)
+//	}
+func TestContent(content Content, expected string) error {
+	if nil == content {
+		return errNilContent
+	}
+
+	if content.Closed() {
+		return erorr.Errorf("expected content to be open but actually was closed")
+	}
+	if expected, actual := int64(len(expected)), content.Size(); expected != actual {
+		return erorr.Errorf("Size returned %d but expected %d", actual, expected)
+	}
+	if expected, actual := expected, content.String(); expected != actual {
+		return erorr.Errorf("String returned %q but expected %q", actual, expected)
+	}
+
+	if err := testRead(content, expected); nil != err {
+		return err
+	}
+	if err := testSeek(content, expected); nil != err {
+		return err
+	}
+	if readerAt, casted := content.(io.ReaderAt); casted {
+		if err := testReadAt(readerAt, expected); nil != err {
+			return err
+		}
+	}
+
+	if err := testClose(content); nil != err {
+		return err
+	}
+	if !content.Closed() {
+		return erorr.Errorf("expected content to be closed after Close but actually wasn't")
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package strfstest_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfstest"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"testing"
+)
+
+func TestTestContent(t *testing.T) {
+
+	tests := []string{
+		"",
+		"once",
+		"once twice thrice fource",
+		"۰	۱	۲	۳	۴	۵	۶	۷	۸	۹",
+		"Hello world! 😈",
+	}
+
+	for testNumber, test := range tests {
+
+		{
+			var content strfs.Content = strfs.CreateContent(test)
+
+			if err := strfstest.TestContent(&content, test); nil != err {
+				t.Errorf("For test #%d, strfs.Content did not pass: %s", testNumber, err)
+				t.Logf("CONTENT: %q", test)
+			}
+		}
+
+		{
+			section, err := strfs.CreateSectionContent(strfs.CreateContent("<"+test+">"), 1, int64(len(test)))
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			if err := strfstest.TestContent(&section, test); nil != err {
+				t.Errorf("For test #%d, section strfs.Content did not pass: %s", testNumber, err)
+				t.Logf("CONTENT: %q", test)
+			}
+		}
+
+		{
+			var content strfs.MultiContent = strfs.CreateMultiContent(strfs.CreateContent(test), strfs.CreateContent(test))
+
+			if err := strfstest.TestContent(&content, test+test); nil != err {
+				t.Errorf("For test #%d, strfs.MultiContent did not pass: %s", testNumber, err)
+				t.Logf("CONTENT: %q", test)
+			}
+		}
+
+		{
+			var content strfs.NormalizedContent = strfs.NormalizeContent(strfs.CreateContent(test), strfs.NormalizeAddBOM)
+
+			if err := strfstest.TestContent(&content, "\uFEFF"+test); nil != err {
+				t.Errorf("For test #%d, strfs.NormalizedContent did not pass: %s", testNumber, err)
+				t.Logf("CONTENT: %q", test)
+			}
+		}
+
+		{
+			var content strfs.RopeContent = strfs.CreateRopeContent(test, test, test)
+
+			if err := strfstest.TestContent(&content, test+test+test); nil != err {
+				t.Errorf("For test #%d, strfs.RopeContent did not pass: %s", testNumber, err)
+				t.Logf("CONTENT: %q", test)
+			}
+		}
+
+		{
+			content, err := strfs.TranscodeContent(strfs.CreateContent(test), charmap.Windows1252, strfs.UnrepresentableHTMLEscape)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				continue
+			}
+
+			if err := strfstest.TestContent(&content, content.String()); nil != err {
+				t.Errorf("For test #%d, strfs.TranscodedContent did not pass: %s", testNumber, err)
+				t.Logf("CONTENT: %q", test)
+			}
+		}
+	}
+}
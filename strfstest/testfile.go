@@ -0,0 +1,85 @@
+package strfstest
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/reiver/go-erorr"
+)
+
+// TestFile checks that a strfs file (such as a *strfs.RegularFile) behaves correctly.
+//
+// 'file' must NOT have been read from yet, and is closed by the time TestFile returns.
+// 'expected' is what the content of the file is expected to be.
+//
+// TestFile checks that:
+//
+//	• Stat returns a fs.FileInfo whose Size, Mode, and IsDir are consistent with a regular file containing 'expected',
+//	• Read returns exactly 'expected',
+//	• Seek (if there is one) works with io.SeekStart, io.SeekCurrent, and io.SeekEnd, and fails for an invalid whence or a negative position,
+//	• ReadAt (if there is one) returns the correct bytes,
+//	• Stat returns the same thing after reading as it did before, and
+//	• after Close, Read fails (and Close can be called again).
+//
+// Typical usage inside a test is:
+//
+//	var regularfile strfs.RegularFile = strfs.RegularFile{
+//		FileContent: strfs.CreateContent("Hello world!"),
+//		FileName:    "hello.txt",
+//	}
+//
+//	if err := strfstest.TestFile(&regularfile, "Hello world!"); nil != err {
+//		t.Fatal(err)
+//	}
+func TestFile(file fs.File, expected string) error {
+	if nil == file {
+		return errNilFile
+	}
+
+	before, err := file.Stat()
+	if nil != err {
+		return erorr.Errorf("Stat returned an error: %w", err)
+	}
+	if nil == before {
+		return erorr.Errorf("Stat returned a nil fs.FileInfo")
+	}
+	if before.IsDir() {
+		return erorr.Errorf("Stat says %q is a directory but expected a regular file", before.Name())
+	}
+	if !before.Mode().IsRegular() {
+		return erorr.Errorf("Stat says %q has mode %v but expected a regular file", before.Name(), before.Mode())
+	}
+	if expected, actual := int64(len(expected)), before.Size(); expected != actual {
+		return erorr.Errorf("Stat says %q has size %d but expected %d", before.Name(), actual, expected)
+	}
+
+	if err := testRead(file, expected); nil != err {
+		return erorr.Errorf("%s: %w", before.Name(), err)
+	}
+	if readSeeker, casted := file.(io.ReadSeeker); casted {
+		if err := testSeek(readSeeker, expected); nil != err {
+			return erorr.Errorf("%s: %w", before.Name(), err)
+		}
+	}
+	if readerAt, casted := file.(io.ReaderAt); casted {
+		if err := testReadAt(readerAt, expected); nil != err {
+			return erorr.Errorf("%s: %w", before.Name(), err)
+		}
+	}
+
+	{
+		after, err := file.Stat()
+		if nil != err {
+			return erorr.Errorf("%s: Stat (after reading) returned an error: %w", before.Name(), err)
+		}
+		if expected, actual := formatFileInfo(before), formatFileInfo(after); expected != actual {
+			return erorr.Errorf("%s: Stat (after reading) returned %s but before reading returned %s", before.Name(), actual, expected)
+		}
+	}
+
+	if err := testClose(file); nil != err {
+		return erorr.Errorf("%s: %w", before.Name(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package strfstest_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfstest"
+
+	"time"
+
+	"testing"
+)
+
+func TestTestFile(t *testing.T) {
+
+	tests := []struct{
+		FileContent string
+		FileName    string
+		FileModTime time.Time
+	}{
+		{
+			FileContent: "",
+			FileName:    "empty.txt",
+			FileModTime: time.Now(),
+		},
+		{
+			FileContent: "once twice thrice",
+			FileName:    "file3.gmni",
+			FileModTime: time.Date(1974, 12, 18, 4, 5, 6, 7, time.Local),
+		},
+		{
+			FileContent: "Hello world! 😈",
+			FileName:    "file4.fngr",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var regularfile strfs.RegularFile = strfs.RegularFile{
+			FileContent: strfs.CreateContent(test.FileContent),
+			FileName:    test.FileName,
+			FileModTime: test.FileModTime,
+		}
+
+		if err := strfstest.TestFile(&regularfile, test.FileContent); nil != err {
+			t.Errorf("For test #%d, strfs.RegularFile did not pass: %s", testNumber, err)
+			t.Logf("REGULARFILE-NAME: %q", test.FileName)
+			t.Logf("REGULARFILE-CONTENT: %q", test.FileContent)
+			continue
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package strfstest implements support for testing the strfs content types, strfs files, and strfs file-systems.
+//
+// It is to strfs what the testing/fstest package is to io/fs.
+package strfstest
+
+import (
+	"io"
+	"io/fs"
+	"sync"
+	"testing/fstest"
+
+	"github.com/reiver/go-erorr"
+)
+
+// concurrentOpens is how many times TestFS opens each file at the same time.
+const concurrentOpens = 8
+
+// TestFS checks that a strfs file-system behaves correctly.
+//
+// TestFS first runs fstest.TestFS (which, amongst other things, checks Stat consistency, and ReadDir paging).
+// Then, for every regular file in the file-system, TestFS:
+//
+//	• runs TestFile on it, and
+//	• opens it many times at the same time, and checks that each of those opened files can be read independently.
+//
+// As with fstest.TestFS, 'fsys' must contain at least the 'expected' files.
+//
+// Typical usage inside a test is:
+//
+//	if err := strfstest.TestFS(fsys, "index.html", "css/style.css"); nil != err {
+//		t.Fatal(err)
+//	}
+func TestFS(fsys fs.FS, expected ...string) error {
+	if nil == fsys {
+		return errNilFS
+	}
+
+	if err := fstest.TestFS(fsys, expected...); nil != err {
+		return err
+	}
+
+	return fs.WalkDir(fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		if nil != err {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, path)
+		if nil != err {
+			return erorr.Errorf("%s: problem reading file: %w", path, err)
+		}
+
+		{
+			file, err := fsys.Open(path)
+			if nil != err {
+				return erorr.Errorf("%s: problem opening file: %w", path, err)
+			}
+
+			if err := TestFile(file, string(data)); nil != err {
+				return erorr.Errorf("%s: %w", path, err)
+			}
+		}
+
+		if err := testConcurrentOpens(fsys, path, string(data)); nil != err {
+			return erorr.Errorf("%s: %w", path, err)
+		}
+
+		return nil
+	})
+}
+
+func testConcurrentOpens(fsys fs.FS, path string, expected string) error {
+
+	var files [concurrentOpens]fs.File
+	for i := range files {
+		file, err := fsys.Open(path)
+		if nil != err {
+			for _, file := range files[:i] {
+				file.Close()
+			}
+			return erorr.Errorf("problem opening file (concurrent open #%d): %w", i, err)
+		}
+		files[i] = file
+	}
+
+	// Read the first byte of each file before reading the rest, to catch files that (incorrectly) share a read offset.
+	var firsts [concurrentOpens][]byte
+	if 0 < len(expected) {
+		for i, file := range files {
+			var b [1]byte
+			n, err := io.ReadFull(file, b[:])
+			if nil != err {
+				return erorr.Errorf("problem reading first byte (concurrent open #%d): %w", i, err)
+			}
+			firsts[i] = append([]byte(nil), b[:n]...)
+		}
+	}
+
+	var waitgroup sync.WaitGroup
+	var errs [concurrentOpens]error
+	for i, file := range files {
+		waitgroup.Add(1)
+		go func(i int, file fs.File) {
+			defer waitgroup.Done()
+			defer file.Close()
+
+			rest, err := io.ReadAll(file)
+			if nil != err {
+				errs[i] = erorr.Errorf("problem reading (concurrent open #%d): %w", i, err)
+				return
+			}
+
+			if expected, actual := expected, string(firsts[i]) + string(rest); expected != actual {
+				errs[i] = erorr.Errorf("read %q but expected %q (concurrent open #%d)", actual, expected, i)
+				return
+			}
+		}(i, file)
+	}
+	waitgroup.Wait()
+
+	for _, err := range errs {
+		if nil != err {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package strfstest_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfstest"
+
+	"io/fs"
+	"path"
+	"testing/fstest"
+	"time"
+
+	"testing"
+)
+
+// regularFileFS is a fs.FS whose regular files are strfs.RegularFile.
+// (The directories come from fstest.MapFS.)
+type regularFileFS struct {
+	mapfs fstest.MapFS
+}
+
+func (receiver regularFileFS) Open(name string) (fs.File, error) {
+	file, err := receiver.mapfs.Open(name)
+	if nil != err {
+		return nil, err
+	}
+
+	mapfile, found := receiver.mapfs[name]
+	if !found || mapfile.Mode.IsDir() {
+		return file, nil
+	}
+	file.Close()
+
+	return &strfs.RegularFile{
+		FileContent: strfs.CreateContent(string(mapfile.Data)),
+		FileName:    path.Base(name),
+		FileModTime: mapfile.ModTime,
+	}, nil
+}
+
+func TestTestFS(t *testing.T) {
+
+	var fsys fs.FS = regularFileFS{
+		mapfs: fstest.MapFS{
+			"empty.txt":       &fstest.MapFile{Data: []byte("")},
+			"file1.txt":       &fstest.MapFile{Data: []byte("once"), ModTime: time.Date(2022, 12, 12, 10, 30, 14, 2, time.UTC)},
+			"html/file2.html": &fstest.MapFile{Data: []byte("once twice")},
+			"gmni/file3.gmni": &fstest.MapFile{Data: []byte("once twice thrice")},
+			"gmni/file4.fngr": &fstest.MapFile{Data: []byte("once twice thrice fource")},
+		},
+	}
+
+	err := strfstest.TestFS(fsys, "empty.txt", "file1.txt", "html/file2.html", "gmni/file3.gmni", "gmni/file4.fngr")
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+}
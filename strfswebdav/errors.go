@@ -0,0 +1,14 @@
+package strfswebdav
+
+import (
+	"github.com/reiver/go-erorr"
+)
+
+const (
+	errFileTooBig   = erorr.Error("file too big")
+	errIsDirectory  = erorr.Error("is a directory")
+	errNilReceiver  = erorr.Error("nil receiver")
+	errNilTree      = erorr.Error("nil tree")
+	errNotDirectory = erorr.Error("not a directory")
+	errNotSeeker    = erorr.Error("not a seeker")
+)
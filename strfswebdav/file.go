@@ -0,0 +1,88 @@
+package strfswebdav
+
+import (
+	"io"
+	"io/fs"
+
+	"golang.org/x/net/webdav"
+)
+
+// internalFile is a (read-only) webdav.File over a fs.File.
+type internalFile struct {
+	file fs.File
+	name string
+}
+
+var _ webdav.File = &internalFile{}
+
+func (receiver *internalFile) Close() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	return receiver.file.Close()
+}
+
+func (receiver *internalFile) Read(p []byte) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	return receiver.file.Read(p)
+}
+
+// Readdir returns (up to 'count') of the fs.FileInfo of the files (and directories) in the directory.
+//
+// As with http.File, if 'count' is zero (or less) then all of them are returned.
+func (receiver *internalFile) Readdir(count int) ([]fs.FileInfo, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+
+	directory, casted := receiver.file.(fs.ReadDirFile)
+	if !casted {
+		return nil, &fs.PathError{Op:"readdir", Path:receiver.name, Err:errNotDirectory}
+	}
+
+	entries, err := directory.ReadDir(count)
+
+	var fileinfos []fs.FileInfo = make([]fs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		fileinfo, err := entry.Info()
+		if nil != err {
+			return fileinfos, err
+		}
+		fileinfos = append(fileinfos, fileinfo)
+	}
+
+	return fileinfos, err
+}
+
+func (receiver *internalFile) Seek(offset int64, whence int) (int64, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	seeker, casted := receiver.file.(io.Seeker)
+	if !casted {
+		return 0, &fs.PathError{Op:"seek", Path:receiver.name, Err:errNotSeeker}
+	}
+
+	return seeker.Seek(offset, whence)
+}
+
+func (receiver *internalFile) Stat() (fs.FileInfo, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+
+	return receiver.file.Stat()
+}
+
+func (receiver *internalFile) Write([]byte) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	return 0, &fs.PathError{Op:"write", Path:receiver.name, Err:fs.ErrPermission}
+}
@@ -0,0 +1,98 @@
+// Package strfswebdav lets a strfs file-system be served over WebDAV (using golang.org/x/net/webdav).
+//
+// FileSystem serves any fs.FS (such as a strfs.FS, or a strfs.Snapshot) read-only.
+// TreeFileSystem serves a strfs.Tree read-write.
+//
+// Example usage:
+//
+//	var handler http.Handler = strfswebdav.CreateHandler(strfswebdav.CreateFileSystem(fsys))
+//
+//	err := http.ListenAndServe(":8080", handler)
+package strfswebdav
+
+import (
+	"context"
+	"io/fs"
+	"os"
+
+	"codeberg.org/reiver/go-strfs/internal/adapter"
+	"golang.org/x/net/webdav"
+)
+
+// writeFlags are the os.OpenFile flags that mean a file is being opened to be changed.
+const writeFlags = os.O_WRONLY | os.O_RDWR | os.O_CREATE | os.O_TRUNC | os.O_APPEND
+
+// FileSystem is a read-only webdav.FileSystem over a fs.FS (such as a strfs.FS, or a strfs.Snapshot).
+//
+// Anything that would change the file-system returns an error (that os.IsPermission is true for).
+type FileSystem struct {
+	fsys fs.FS
+}
+
+// A trick to make sure strfswebdav.FileSystem fits the webdav.FileSystem interface.
+// This is a compile-time check.
+var _ webdav.FileSystem = FileSystem{}
+
+// CreateFileSystem returns a (read-only) strfswebdav.FileSystem over 'fsys'.
+func CreateFileSystem(fsys fs.FS) FileSystem {
+	return FileSystem{
+		fsys:fsys,
+	}
+}
+
+// Mkdir always returns an error, because a strfswebdav.FileSystem is read-only.
+//
+// Mkdir makes strfswebdav.FileSystem fit the webdav.FileSystem interface.
+func (receiver FileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	return &fs.PathError{Op:"mkdir", Path:name, Err:fs.ErrPermission}
+}
+
+// OpenFile opens the file (or directory) at 'name' for reading.
+//
+// If 'flag' has any of os.O_WRONLY, os.O_RDWR, os.O_CREATE, os.O_TRUNC, or os.O_APPEND, then OpenFile returns an error
+// (because a strfswebdav.FileSystem is read-only).
+//
+// OpenFile makes strfswebdav.FileSystem fit the webdav.FileSystem interface.
+func (receiver FileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if nil == receiver.fsys {
+		return nil, adapter.ErrNilFS
+	}
+	if 0 != flag & writeFlags {
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrPermission}
+	}
+
+	file, err := receiver.fsys.Open(adapter.CleanName(name))
+	if nil != err {
+		return nil, err
+	}
+
+	return &internalFile{
+		file:file,
+		name:name,
+	}, nil
+}
+
+// RemoveAll always returns an error, because a strfswebdav.FileSystem is read-only.
+//
+// RemoveAll makes strfswebdav.FileSystem fit the webdav.FileSystem interface.
+func (receiver FileSystem) RemoveAll(ctx context.Context, name string) error {
+	return &fs.PathError{Op:"remove", Path:name, Err:fs.ErrPermission}
+}
+
+// Rename always returns an error, because a strfswebdav.FileSystem is read-only.
+//
+// Rename makes strfswebdav.FileSystem fit the webdav.FileSystem interface.
+func (receiver FileSystem) Rename(ctx context.Context, oldName string, newName string) error {
+	return &fs.PathError{Op:"rename", Path:oldName, Err:fs.ErrPermission}
+}
+
+// Stat returns the fs.FileInfo of the file (or directory) at 'name'.
+//
+// Stat makes strfswebdav.FileSystem fit the webdav.FileSystem interface.
+func (receiver FileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	if nil == receiver.fsys {
+		return nil, adapter.ErrNilFS
+	}
+
+	return fs.Stat(receiver.fsys, adapter.CleanName(name))
+}
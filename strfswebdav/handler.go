@@ -0,0 +1,16 @@
+package strfswebdav
+
+import (
+	"golang.org/x/net/webdav"
+)
+
+// CreateHandler returns a webdav.Handler that serves 'filesystem' (such as a strfswebdav.FileSystem, or a *strfswebdav.TreeFileSystem),
+// with locking done by the standard in-memory lock-system (i.e., webdav.NewMemLS).
+//
+// The returned webdav.Handler can be changed (for example, to set its Prefix or Logger) before it is used.
+func CreateHandler(filesystem webdav.FileSystem) *webdav.Handler {
+	return &webdav.Handler{
+		FileSystem: filesystem,
+		LockSystem: webdav.NewMemLS(),
+	}
+}
@@ -0,0 +1,173 @@
+package strfswebdav
+
+import (
+	"io"
+	"io/fs"
+	"path"
+
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/internal/adapter"
+	"golang.org/x/net/webdav"
+)
+
+// internalDirectoryFile is a webdav.File for a directory of a strfswebdav.TreeFileSystem.
+type internalDirectoryFile struct {
+	name string
+	fileinfos []fs.FileInfo
+	offset int
+}
+
+var _ webdav.File = &internalDirectoryFile{}
+
+func (receiver *internalDirectoryFile) Close() error {
+	return nil
+}
+
+func (receiver *internalDirectoryFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op:"read", Path:receiver.name, Err:errIsDirectory}
+}
+
+func (receiver *internalDirectoryFile) Readdir(count int) ([]fs.FileInfo, error) {
+	var remaining []fs.FileInfo = receiver.fileinfos[receiver.offset:]
+
+	if 0 < count && 0 == len(remaining) {
+		return nil, io.EOF
+	}
+	if 0 < count && count < len(remaining) {
+		remaining = remaining[:count]
+	}
+
+	receiver.offset += len(remaining)
+	return append([]fs.FileInfo(nil), remaining...), nil
+}
+
+func (receiver *internalDirectoryFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, &fs.PathError{Op:"seek", Path:receiver.name, Err:errIsDirectory}
+}
+
+func (receiver *internalDirectoryFile) Stat() (fs.FileInfo, error) {
+	return adapter.DirectoryInfo(path.Base(receiver.name)), nil
+}
+
+func (receiver *internalDirectoryFile) Write([]byte) (int, error) {
+	return 0, &fs.PathError{Op:"write", Path:receiver.name, Err:errIsDirectory}
+}
+
+// internalWriteFile is a webdav.File for a file of a strfswebdav.TreeFileSystem that has been opened for writing.
+//
+// What is written is kept in memory, and is only written to the strfs.Tree when the file is closed.
+// A file cannot be made bigger than maxFileSize.
+type internalWriteFile struct {
+	tree *strfs.Tree
+	name string
+	data []byte
+	offset int64
+	readable bool
+	dirty bool
+	closed bool
+}
+
+var _ webdav.File = &internalWriteFile{}
+
+func (receiver *internalWriteFile) Close() error {
+	if receiver.closed {
+		return &fs.PathError{Op:"close", Path:receiver.name, Err:fs.ErrClosed}
+	}
+	receiver.closed = true
+
+	if !receiver.dirty {
+		return nil
+	}
+
+	// The permission-bits are only used if the file was removed (by someone else) while it was open.
+	return receiver.tree.WriteFile(receiver.name, string(receiver.data), 0644)
+}
+
+func (receiver *internalWriteFile) Read(p []byte) (int, error) {
+	if receiver.closed {
+		return 0, &fs.PathError{Op:"read", Path:receiver.name, Err:fs.ErrClosed}
+	}
+	if !receiver.readable {
+		return 0, &fs.PathError{Op:"read", Path:receiver.name, Err:fs.ErrPermission}
+	}
+
+	if int64(len(receiver.data)) <= receiver.offset {
+		return 0, io.EOF
+	}
+
+	var n int = copy(p, receiver.data[receiver.offset:])
+	receiver.offset += int64(n)
+	return n, nil
+}
+
+func (receiver *internalWriteFile) Readdir(int) ([]fs.FileInfo, error) {
+	return nil, &fs.PathError{Op:"readdir", Path:receiver.name, Err:errNotDirectory}
+}
+
+func (receiver *internalWriteFile) Seek(offset int64, whence int) (int64, error) {
+	if receiver.closed {
+		return 0, &fs.PathError{Op:"seek", Path:receiver.name, Err:fs.ErrClosed}
+	}
+
+	var absolute int64
+	switch whence {
+	case io.SeekStart:
+		absolute = offset
+	case io.SeekCurrent:
+		absolute = receiver.offset + offset
+	case io.SeekEnd:
+		absolute = int64(len(receiver.data)) + offset
+	default:
+		return 0, &fs.PathError{Op:"seek", Path:receiver.name, Err:fs.ErrInvalid}
+	}
+	if absolute < 0 {
+		return 0, &fs.PathError{Op:"seek", Path:receiver.name, Err:fs.ErrInvalid}
+	}
+
+	receiver.offset = absolute
+	return absolute, nil
+}
+
+func (receiver *internalWriteFile) Stat() (fs.FileInfo, error) {
+	fileinfo, err := fs.Stat(receiver.tree, receiver.name)
+	if nil != err {
+		return nil, err
+	}
+
+	return internalWriteFileInfo{
+		FileInfo:fileinfo,
+		size:int64(len(receiver.data)),
+	}, nil
+}
+
+func (receiver *internalWriteFile) Write(p []byte) (int, error) {
+	if receiver.closed {
+		return 0, &fs.PathError{Op:"write", Path:receiver.name, Err:fs.ErrClosed}
+	}
+
+	// (Checked this way, rather than with offset+len(p), so that a huge offset cannot overflow.)
+	if maxFileSize < receiver.offset || maxFileSize-receiver.offset < int64(len(p)) {
+		return 0, &fs.PathError{Op:"write", Path:receiver.name, Err:errFileTooBig}
+	}
+
+	var end int64 = receiver.offset + int64(len(p))
+	if int64(len(receiver.data)) < end {
+		// (Grown with append, so that writing a file in many small pieces does NOT copy it over and over again.)
+		receiver.data = append(receiver.data, make([]byte, end-int64(len(receiver.data)))...)
+	}
+
+	copy(receiver.data[receiver.offset:], p)
+	receiver.offset = end
+	receiver.dirty = true
+	return len(p), nil
+}
+
+// internalWriteFileInfo is the fs.FileInfo of a file that is open for writing — its size is the size of what has been written so far.
+type internalWriteFileInfo struct {
+	fs.FileInfo
+	size int64
+}
+
+func (receiver internalWriteFileInfo) Size() int64 {
+	return receiver.size
+}
@@ -0,0 +1,200 @@
+package strfswebdav
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"sync"
+
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/internal/adapter"
+	"golang.org/x/net/webdav"
+)
+
+// TreeFileSystem is a read-write webdav.FileSystem over a strfs.Tree.
+//
+// A strfs.Tree only has (implied) directories that have files in them,
+// so an (empty) directory made with MKCOL is remembered by the strfswebdav.TreeFileSystem itself.
+//
+// A file opened for writing is written to the strfs.Tree when it is closed.
+// Clients cannot make a file bigger than 64 MiB.
+type TreeFileSystem struct {
+	tree *strfs.Tree
+
+	mutex sync.Mutex
+	directories adapter.Directories
+}
+
+// maxFileSize is the biggest a client can make a file (by writing to it).
+const maxFileSize = 64 << 20
+
+// A trick to make sure *strfswebdav.TreeFileSystem fits the webdav.FileSystem interface.
+// This is a compile-time check.
+var _ webdav.FileSystem = &TreeFileSystem{}
+
+// CreateTreeFileSystem returns a (read-write) strfswebdav.TreeFileSystem over 'tree'.
+func CreateTreeFileSystem(tree *strfs.Tree) *TreeFileSystem {
+	return &TreeFileSystem{
+		tree:tree,
+		directories:adapter.CreateDirectories(tree),
+	}
+}
+
+// Mkdir makes an (empty) directory at 'name'.
+//
+// Mkdir makes *strfswebdav.TreeFileSystem fit the webdav.FileSystem interface.
+func (receiver *TreeFileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if nil == receiver.tree {
+		return errNilTree
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	return receiver.directories.Mkdir(adapter.CleanName(name))
+}
+
+// OpenFile opens the file (or directory) at 'name'.
+//
+// os.O_RDONLY, os.O_WRONLY, os.O_RDWR, os.O_CREATE, os.O_EXCL, and os.O_TRUNC are supported in 'flag'.
+// 'perm' is the permission-bits that a created file gets.
+//
+// OpenFile makes *strfswebdav.TreeFileSystem fit the webdav.FileSystem interface.
+func (receiver *TreeFileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+	if nil == receiver.tree {
+		return nil, errNilTree
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	var cleaned string = adapter.CleanName(name)
+
+	if 0 != flag & (os.O_APPEND | os.O_SYNC) {
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrInvalid}
+	}
+
+	if receiver.directories.IsDirectory(cleaned) {
+		if 0 != flag & writeFlags {
+			return nil, &fs.PathError{Op:"open", Path:name, Err:errIsDirectory}
+		}
+
+		return receiver.openDirectory(cleaned)
+	}
+
+	if 0 == flag & writeFlags {
+		file, err := receiver.tree.Open(cleaned)
+		if nil != err {
+			return nil, err
+		}
+
+		return &internalFile{
+			file:file,
+			name:name,
+		}, nil
+	}
+
+	data, err := fs.ReadFile(receiver.tree, cleaned)
+	var exists bool = nil == err
+	switch {
+	case exists && 0 != flag & os.O_CREATE && 0 != flag & os.O_EXCL:
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrExist}
+	case !exists && !os.IsNotExist(err):
+		return nil, err
+	case !exists && 0 == flag & os.O_CREATE:
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrNotExist}
+	case !exists && !receiver.directories.IsDirectory(path.Dir(cleaned)):
+		return nil, &fs.PathError{Op:"open", Path:name, Err:fs.ErrNotExist}
+	}
+
+	if !exists {
+		if err := receiver.tree.WriteFile(cleaned, "", perm); nil != err {
+			return nil, err
+		}
+	}
+
+	var writefile internalWriteFile = internalWriteFile{
+		tree:receiver.tree,
+		name:cleaned,
+		data:data,
+		readable:0 == flag & os.O_WRONLY,
+		dirty:!exists,
+	}
+	if 0 != flag & os.O_TRUNC {
+		writefile.data = nil
+		writefile.dirty = true
+	}
+
+	return &writefile, nil
+}
+
+// openDirectory returns a webdav.File for the directory at 'name', whose entries include the directories made with Mkdir.
+func (receiver *TreeFileSystem) openDirectory(name string) (webdav.File, error) {
+	fileinfos, err := receiver.directories.ReadDir(name)
+	if nil != err {
+		return nil, err
+	}
+
+	return &internalDirectoryFile{
+		name:name,
+		fileinfos:fileinfos,
+	}, nil
+}
+
+// RemoveAll removes the file, or the directory (and everything in it), at 'name'.
+//
+// RemoveAll makes *strfswebdav.TreeFileSystem fit the webdav.FileSystem interface.
+func (receiver *TreeFileSystem) RemoveAll(ctx context.Context, name string) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if nil == receiver.tree {
+		return errNilTree
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	return receiver.directories.RemoveAll(adapter.CleanName(name))
+}
+
+// Rename moves the file, or the directory (and everything in it), at 'oldName' to 'newName'.
+//
+// Rename makes *strfswebdav.TreeFileSystem fit the webdav.FileSystem interface.
+func (receiver *TreeFileSystem) Rename(ctx context.Context, oldName string, newName string) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if nil == receiver.tree {
+		return errNilTree
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	return receiver.directories.Rename(adapter.CleanName(oldName), adapter.CleanName(newName))
+}
+
+// Stat returns the fs.FileInfo of the file (or directory) at 'name'.
+//
+// Stat makes *strfswebdav.TreeFileSystem fit the webdav.FileSystem interface.
+func (receiver *TreeFileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+	if nil == receiver.tree {
+		return nil, errNilTree
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	return receiver.directories.Stat(adapter.CleanName(name))
+}
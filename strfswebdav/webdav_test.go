@@ -0,0 +1,233 @@
+package strfswebdav_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfswebdav"
+
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	"testing"
+)
+
+func exampleFS() strfs.FS {
+	var mtime time.Time = time.Date(2022, 12, 12, 10, 30, 14, 0, time.UTC)
+
+	return strfs.FS{
+		"index.html": strfs.RegularFile{
+			FileContent: strfs.CreateContent("<!DOCTYPE html>"+"\n"+"<html><body>Hello world!</body></html>"),
+			FileModTime: mtime,
+			FileMode:    0644,
+		},
+		"css/style.css": strfs.RegularFile{
+			FileContent: strfs.CreateContent("body{color:red}"),
+			FileModTime: mtime,
+			FileMode:    0644,
+		},
+	}
+}
+
+// do makes a request to 'server', and returns the status-code and body of the response.
+func do(t *testing.T, server *httptest.Server, method string, path string, body string, headers ...string) (int, string) {
+	t.Helper()
+
+	request, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		request.Header.Set(headers[i], headers[i+1])
+	}
+
+	response, err := server.Client().Do(request)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	return response.StatusCode, string(data)
+}
+
+func expectStatus(t *testing.T, server *httptest.Server, expected int, method string, path string, body string, headers ...string) string {
+	t.Helper()
+
+	actual, responseBody := do(t, server, method, path, body, headers...)
+	if expected != actual {
+		t.Errorf("For %s %s, the actual status-code was not what was expected.", method, path)
+		t.Logf("EXPECTED STATUS-CODE: %d", expected)
+		t.Logf("ACTUAL   STATUS-CODE: %d", actual)
+		t.Logf("BODY: %s", responseBody)
+	}
+
+	return responseBody
+}
+
+func TestFileSystem(t *testing.T) {
+
+	var server *httptest.Server = httptest.NewServer(strfswebdav.CreateHandler(strfswebdav.CreateFileSystem(exampleFS())))
+	defer server.Close()
+
+	{
+		var body string = expectStatus(t, server, http.StatusMultiStatus, "PROPFIND", "/", "", "Depth", "1")
+
+		for _, expected := range []string{"<D:href>/</D:href>", "<D:href>/index.html</D:href>", "<D:href>/css/</D:href>", "<D:getcontentlength>54</D:getcontentlength>"} {
+			if !strings.Contains(body, expected) {
+				t.Errorf("Expected the PROPFIND response to contain %q, but it did not.", expected)
+				t.Logf("BODY: %s", body)
+			}
+		}
+	}
+
+	if expected, actual := "body{color:red}", expectStatus(t, server, http.StatusOK, "GET", "/css/style.css", ""); expected != actual {
+		t.Errorf("The actual content was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	expectStatus(t, server, http.StatusNotFound, "GET", "/missing.txt", "")
+	expectStatus(t, server, http.StatusNotFound, "PUT", "/new.txt", "new") // (webdav.Handler reports any error opening a file to PUT as 404.)
+	expectStatus(t, server, http.StatusMethodNotAllowed, "MKCOL", "/dir", "")
+	expectStatus(t, server, http.StatusMethodNotAllowed, "DELETE", "/index.html", "")
+	expectStatus(t, server, http.StatusOK, "GET", "/index.html", "")
+}
+
+func TestTreeFileSystem(t *testing.T) {
+
+	tree, err := strfs.CreateTree(exampleFS())
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var server *httptest.Server = httptest.NewServer(strfswebdav.CreateHandler(strfswebdav.CreateTreeFileSystem(tree)))
+	defer server.Close()
+
+	expectStatus(t, server, http.StatusCreated, "MKCOL", "/blog", "")
+	expectStatus(t, server, http.StatusMethodNotAllowed, "MKCOL", "/blog", "")
+	expectStatus(t, server, http.StatusConflict, "MKCOL", "/missing/blog", "")
+
+	{
+		var body string = expectStatus(t, server, http.StatusMultiStatus, "PROPFIND", "/", "", "Depth", "1")
+
+		if expected := "<D:href>/blog/</D:href>"; !strings.Contains(body, expected) {
+			t.Errorf("Expected the PROPFIND response to contain the empty directory %q, but it did not.", expected)
+			t.Logf("BODY: %s", body)
+		}
+	}
+
+	expectStatus(t, server, http.StatusCreated, "PUT", "/blog/post.html", "<p>first post</p>")
+	expectStatus(t, server, http.StatusCreated, "PUT", "/index.html", "<p>new index</p>")
+	expectStatus(t, server, http.StatusNotFound, "PUT", "/missing/post.html", "nope")
+
+	expectFile(t, tree, "blog/post.html", "<p>first post</p>")
+	expectFile(t, tree, "index.html", "<p>new index</p>")
+
+	expectStatus(t, server, http.StatusCreated, "MOVE", "/blog", "", "Destination", server.URL+"/posts")
+	expectFile(t, tree, "posts/post.html", "<p>first post</p>")
+	expectStatus(t, server, http.StatusNotFound, "GET", "/blog/post.html", "")
+
+	expectStatus(t, server, http.StatusCreated, "COPY", "/css/style.css", "", "Destination", server.URL+"/posts/style.css")
+	expectFile(t, tree, "posts/style.css", "body{color:red}")
+	expectFile(t, tree, "css/style.css", "body{color:red}")
+
+	expectStatus(t, server, http.StatusNoContent, "DELETE", "/posts", "")
+	if _, err := fs.Stat(tree, "posts"); nil == err {
+		t.Errorf("Expected the deleted directory to not exist anymore.")
+	}
+
+	{
+		const lockBody = `<?xml version="1.0" encoding="utf-8"?>` +
+			`<D:lockinfo xmlns:D="DAV:"><D:lockscope><D:exclusive/></D:lockscope><D:locktype><D:write/></D:locktype><D:owner>tester</D:owner></D:lockinfo>`
+
+		request, err := http.NewRequest("LOCK", server.URL+"/index.html", strings.NewReader(lockBody))
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+		response, err := server.Client().Do(request)
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+		response.Body.Close()
+
+		if expected, actual := http.StatusOK, response.StatusCode; expected != actual {
+			t.Fatalf("The actual LOCK status-code was not what was expected: expected %d, actually %d", expected, actual)
+		}
+
+		var token string = response.Header.Get("Lock-Token")
+		if "" == token {
+			t.Fatalf("Expected a Lock-Token, but did not actually get one.")
+		}
+
+		expectStatus(t, server, http.StatusLocked, "PUT", "/index.html", "locked out")
+		expectStatus(t, server, http.StatusCreated, "PUT", "/index.html", "locked in", "If", "("+token+")")
+		expectStatus(t, server, http.StatusNoContent, "UNLOCK", "/index.html", "", "Lock-Token", token)
+		expectStatus(t, server, http.StatusCreated, "PUT", "/index.html", "unlocked")
+	}
+
+	expectFile(t, tree, "index.html", "unlocked")
+}
+
+func TestTreeFileSystem_tooBig(t *testing.T) {
+
+	tree, err := strfs.CreateTree(exampleFS())
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var filesystem *strfswebdav.TreeFileSystem = strfswebdav.CreateTreeFileSystem(tree)
+
+	file, err := filesystem.OpenFile(context.Background(), "/huge.bin", os.O_RDWR|os.O_CREATE, 0644)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if _, err := file.Write([]byte("small")); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	// Writes that would make a file too big are errors (rather than using up all the memory).
+	if _, err := file.Seek(1<<62, io.SeekStart); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if _, err := file.Write([]byte("x")); nil == err {
+		t.Errorf("Expected an error writing at a huge offset, but did not actually get one.")
+	}
+	if _, err := file.Seek(1<<63-1, io.SeekStart); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if _, err := file.Write([]byte("x")); nil == err {
+		t.Errorf("Expected an error writing at an offset that overflows, but did not actually get one.")
+	}
+
+	if err := file.Close(); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	expectFile(t, tree, "huge.bin", "small")
+}
+
+func expectFile(t *testing.T, fsys fs.FS, name string, expected string) {
+	t.Helper()
+
+	data, err := fs.ReadFile(fsys, name)
+	if nil != err {
+		t.Errorf("Did not expect an error reading %q but actually got one.", name)
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	if actual := string(data); expected != actual {
+		t.Errorf("The actual content of %q was not what was expected.", name)
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+}
@@ -0,0 +1,98 @@
+package strfs
+
+import (
+	"archive/tar"
+	"io"
+	"io/fs"
+
+	"github.com/reiver/go-erorr"
+)
+
+// ExportTar writes each regular file in 'fsys' (such as a strfs.FS) to 'writer' as a tar archive,
+// keeping each file's path, content, modification-time, and permission-bits.
+//
+// Directories are NOT written as their own entries — they are implied by the paths of the files.
+func ExportTar(writer io.Writer, fsys fs.FS) error {
+	if nil == writer {
+		return errNilWriter
+	}
+
+	var tarwriter *tar.Writer = tar.NewWriter(writer)
+
+	err := exportWalk(fsys, func(name string, fileinfo fs.FileInfo, data []byte) error {
+		var header tar.Header = tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Size:     int64(len(data)),
+			Mode:     int64(fileinfo.Mode().Perm()),
+			ModTime:  fileinfo.ModTime(),
+		}
+
+		if err := tarwriter.WriteHeader(&header); nil != err {
+			return err
+		}
+		_, err := tarwriter.Write(data)
+		return err
+	})
+	if nil != err {
+		return err
+	}
+
+	return tarwriter.Close()
+}
+
+// ImportTar reads a tar archive from 'reader' and returns a strfs.FS with each of its regular files,
+// keeping each file's path, content, modification-time, and permission-bits.
+//
+// Directory entries are skipped (since, in a strfs.FS, directories are implied by the paths of the files).
+// So are PAX headers (such as the "pax_global_header" that "git archive" writes).
+// Any other kind of entry (such as a symbolic link) causes an error.
+// So does more than one file with the same path.
+func ImportTar(reader io.Reader) (FS, error) {
+	if nil == reader {
+		return nil, errNilReader
+	}
+
+	var fsys FS = FS{}
+
+	var tarreader *tar.Reader = tar.NewReader(reader)
+	for {
+		header, err := tarreader.Next()
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			return nil, err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir, tar.TypeXGlobalHeader, tar.TypeXHeader:
+			continue
+		case tar.TypeReg, tar.TypeRegA:
+			// Nothing here.
+		default:
+			return nil, erorr.Errorf("tar entry %q has unsupported type %q", header.Name, header.Typeflag)
+		}
+
+		name, err := importPath(header.Name)
+		if nil != err {
+			return nil, err
+		}
+		if _, duplicate := fsys[name]; duplicate {
+			return nil, erorr.Errorf("tar archive has more than one file named %q", name)
+		}
+
+		data, err := io.ReadAll(tarreader)
+		if nil != err {
+			return nil, err
+		}
+
+		fsys[name] = RegularFile{
+			FileContent: CreateContent(string(data)),
+			FileModTime: header.ModTime,
+			FileMode:    fs.FileMode(header.Mode).Perm(),
+		}
+	}
+
+	return fsys, nil
+}
@@ -0,0 +1,115 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"archive/tar"
+	"bytes"
+	"io/fs"
+
+	"testing"
+)
+
+func TestExportTar_ImportTar(t *testing.T) {
+
+	var expected strfs.FS = exampleFS()
+
+	var buffer bytes.Buffer
+	if err := strfs.ExportTar(&buffer, expected); nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	actual, err := strfs.ImportTar(&buffer)
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	sameFS(t, expected, actual, true)
+}
+
+func TestImportTar_duplicate(t *testing.T) {
+
+	var buffer bytes.Buffer
+	{
+		var writer *tar.Writer = tar.NewWriter(&buffer)
+		for _, content := range []string{"first", "second"} {
+			var header tar.Header = tar.Header{
+				Typeflag:tar.TypeReg,
+				Name:"dir/file.txt",
+				Mode:0644,
+				Size:int64(len(content)),
+			}
+			if err := writer.WriteHeader(&header); nil != err {
+				t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+			}
+			if _, err := writer.Write([]byte(content)); nil != err {
+				t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+			}
+		}
+		if err := writer.Close(); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+	}
+
+	if _, err := strfs.ImportTar(&buffer); nil == err {
+		t.Errorf("Expected an error importing a tar archive with two files with the same name, but did not actually get one.")
+	}
+}
+
+func TestImportTar_paxGlobalHeader(t *testing.T) {
+
+	var buffer bytes.Buffer
+	{
+		var writer *tar.Writer = tar.NewWriter(&buffer)
+
+		// This is what "git archive" puts at the beginning of the tar archives it writes.
+		var global tar.Header = tar.Header{
+			Typeflag:tar.TypeXGlobalHeader,
+			Name:"pax_global_header",
+			PAXRecords:map[string]string{"comment":"b6c4f7a8e9d0c1b2a3f4e5d6c7b8a9f0e1d2c3b4"},
+		}
+		if err := writer.WriteHeader(&global); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		var content string = "Hello world!"
+		var header tar.Header = tar.Header{
+			Typeflag:tar.TypeReg,
+			Name:"dir/file.txt",
+			Mode:0644,
+			Size:int64(len(content)),
+		}
+		if err := writer.WriteHeader(&header); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+		if _, err := writer.Write([]byte(content)); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+		if err := writer.Close(); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+	}
+
+	fsys, err := strfs.ImportTar(&buffer)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if expected, actual := 1, len(fsys); expected != actual {
+		t.Errorf("The actual number of files was not what was expected: expected %d, actually %d", expected, actual)
+	}
+
+	data, err := fs.ReadFile(fsys, "dir/file.txt")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if expected, actual := "Hello world!", string(data); expected != actual {
+		t.Errorf("The actual content was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+}
@@ -0,0 +1,231 @@
+package strfs
+
+import (
+	"io"
+
+	"github.com/reiver/go-erorr"
+	"golang.org/x/text/encoding"
+)
+
+// Unrepresentable says what TranscodeContent should do with a rune that the target encoding cannot represent.
+type Unrepresentable int
+
+const (
+	// UnrepresentableError makes TranscodeContent return an error.
+	UnrepresentableError Unrepresentable = iota
+
+	// UnrepresentableReplace makes TranscodeContent replace the rune with the target encoding's replacement character.
+	// (For many encodings this is "\x1A", or "?".)
+	UnrepresentableReplace
+
+	// UnrepresentableHTMLEscape makes TranscodeContent replace the rune with an HTML numeric character reference.
+	// For example, "😈" would become "&#128520;".
+	UnrepresentableHTMLEscape
+)
+
+// TranscodedContent is a view of a strfs.Content in a character-set other than UTF-8.
+//
+// For example, in Latin-1 (ISO-8859-1), Windows-1252, or UTF-16.
+//
+// Size, Read, and Seek all work in terms of the transcoded bytes.
+//
+// Example usage:
+//
+//	import "golang.org/x/text/encoding/charmap"
+//
+//	// ...
+//
+//	var content strfs.Content = strfs.CreateContent("Café")
+//
+//	transcoded, err := strfs.TranscodeContent(content, charmap.Windows1252, strfs.UnrepresentableError)
+//
+//	// transcoded.Size() == 4
+type TranscodedContent struct {
+	pieces internalPieces
+	encoding encoding.Encoding
+	initialized bool
+	closed bool
+}
+
+var (
+	// A trick to make sure strfs.TranscodedContent fits the io.ReadSeekCloser interface.
+	// This is a compile-time check.
+	_ io.ReadSeekCloser = &TranscodedContent{}
+
+	// A trick to make sure strfs.TranscodedContent fits the io.ReaderAt interface.
+	// This is a compile-time check.
+	_ io.ReaderAt = &TranscodedContent{}
+)
+
+// TranscodeContent returns a strfs.TranscodedContent that is the (UTF-8) strfs.Content given to it, transcoded into 'enc'.
+//
+// What happens to runes that 'enc' cannot represent is controlled by 'unrepresentable'.
+//
+// If 'content' is empty (see EmptyContent), then the returned strfs.TranscodedContent is also empty (and thus closed).
+func TranscodeContent(content Content, enc encoding.Encoding, unrepresentable Unrepresentable) (TranscodedContent, error) {
+	if nil == enc {
+		return TranscodedContent{}, errNilEncoding
+	}
+
+	if EmptyContent() == content {
+		return TranscodedContent{}, nil
+	}
+
+	var encoder *encoding.Encoder
+	switch unrepresentable {
+	case UnrepresentableError:
+		encoder = enc.NewEncoder()
+	case UnrepresentableReplace:
+		encoder = encoding.ReplaceUnsupported(enc.NewEncoder())
+	case UnrepresentableHTMLEscape:
+		encoder = encoding.HTMLEscapeUnsupported(enc.NewEncoder())
+	default:
+		return TranscodedContent{}, erorr.Errorf("unknown unrepresentable-rune handling %d", unrepresentable)
+	}
+
+	transcoded, err := encoder.String(content.String())
+	if nil != err {
+		return TranscodedContent{}, erorr.Errorf("problem transcoding content: %w", err)
+	}
+
+	return TranscodedContent{
+		pieces:createPieces(transcoded),
+		encoding:enc,
+		initialized:true,
+	}, nil
+}
+
+// Close will stop the Read method from working.
+//
+// Close can safely be called more than once.
+//
+// Close makes strfs.TranscodedContent fit the io.Closer interface.
+func (receiver *TranscodedContent) Close() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.closed = true
+	return nil
+}
+
+// Closed returns whether a strfs.TranscodedContent is closed or not.
+func (receiver *TranscodedContent) Closed() bool {
+	if nil == receiver {
+		return true
+	}
+	if !receiver.initialized {
+		return true
+	}
+
+	return receiver.closed
+}
+
+// Content returns a strfs.Content of the transcoded content, so that it can be the FileContent of a strfs.RegularFile
+// (and thus be put into a strfs.FS, or a strfs.Tree).
+//
+// The transcoded bytes are NOT copied.
+//
+// If the strfs.TranscodedContent is empty, then the returned strfs.Content is also empty (see EmptyContent).
+//
+// Example usage:
+//
+//	transcoded, err := strfs.TranscodeContent(content, charmap.Windows1252, strfs.UnrepresentableError)
+//
+//	// ...
+//
+//	var regularfile strfs.RegularFile = strfs.RegularFile{
+//		FileContent: transcoded.Content(),
+//		FileName:    "cafe.txt",
+//		FileModTime: time.Now(),
+//	}
+func (receiver *TranscodedContent) Content() Content {
+	if nil == receiver {
+		return EmptyContent()
+	}
+	if !receiver.initialized {
+		return EmptyContent()
+	}
+
+	return createPiecesContent(receiver.pieces)
+}
+
+// Encoding returns the encoding that was given to TranscodeContent.
+func (receiver *TranscodedContent) Encoding() encoding.Encoding {
+	if nil == receiver {
+		return nil
+	}
+
+	return receiver.encoding
+}
+
+// Read reads up to len(p) bytes of the transcoded content into 'p'.
+// Read returns the number of bytes actually read, and any errors it encountered.
+//
+// Read makes strfs.TranscodedContent fit the io.Reader interface.
+func (receiver *TranscodedContent) Read(p []byte) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		return 0, errClosed
+	}
+
+	return receiver.pieces.Read(p)
+}
+
+// ReadAt reads len(p) bytes of the transcoded content, starting at 'offset', into 'p'.
+//
+// ReadAt makes strfs.TranscodedContent fit the io.ReaderAt interface.
+func (receiver *TranscodedContent) ReadAt(p []byte, offset int64) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		return 0, errClosed
+	}
+
+	return receiver.pieces.ReadAt(p, offset)
+}
+
+// Seek sets the offset for the next Read.
+// The offset is in terms of the transcoded content.
+//
+// Seek makes strfs.TranscodedContent fit the io.Seeker interface.
+func (receiver *TranscodedContent) Seek(offset int64, whence int) (int64, error) {
+	if nil == receiver {
+		var nada int64
+		return nada, errNilReceiver
+	}
+
+	if receiver.Closed() {
+		var nada int64
+		return nada, errClosed
+	}
+
+	return receiver.pieces.Seek(offset, whence)
+}
+
+// Size returns the size of the transcoded content as the number of bytes.
+func (receiver *TranscodedContent) Size() int64 {
+	if nil == receiver {
+		return 0
+	}
+
+	return receiver.pieces.Size()
+}
+
+// String returns the transcoded content.
+//
+// Note that the returned string is (probably) NOT UTF-8.
+//
+// String makes *strfs.TranscodedContent fit the fmt.Stringer interface.
+func (receiver *TranscodedContent) String() string {
+	if nil == receiver {
+		return ""
+	}
+
+	return receiver.pieces.String()
+}
@@ -0,0 +1,170 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"io"
+	"io/fs"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+
+	"testing"
+)
+
+func TestTranscodeContent(t *testing.T) {
+
+	tests := []struct{
+		Content         string
+		Encoding        encoding.Encoding
+		Unrepresentable strfs.Unrepresentable
+		Expected        string
+	}{
+		{
+			Content:         "once twice thrice fource",
+			Encoding:        charmap.ISO8859_1,
+			Unrepresentable: strfs.UnrepresentableError,
+			Expected:        "once twice thrice fource",
+		},
+		{
+			Content:         "Café",
+			Encoding:        charmap.ISO8859_1,
+			Unrepresentable: strfs.UnrepresentableError,
+			Expected:        "Caf\xE9",
+		},
+		{
+			Content:         "“Café”",
+			Encoding:        charmap.Windows1252,
+			Unrepresentable: strfs.UnrepresentableError,
+			Expected:        "\x93Caf\xE9\x94",
+		},
+		{
+			Content:         "Hi",
+			Encoding:        unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+			Unrepresentable: strfs.UnrepresentableError,
+			Expected:        "\x00H\x00i",
+		},
+		{
+			Content:         "Hi",
+			Encoding:        unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+			Unrepresentable: strfs.UnrepresentableError,
+			Expected:        "\xFF\xFEH\x00i\x00",
+		},
+
+
+
+		{
+			Content:         "Hello world! 😈",
+			Encoding:        charmap.Windows1252,
+			Unrepresentable: strfs.UnrepresentableReplace,
+			Expected:        "Hello world! \x1A",
+		},
+		{
+			Content:         "Hello world! 😈",
+			Encoding:        charmap.Windows1252,
+			Unrepresentable: strfs.UnrepresentableHTMLEscape,
+			Expected:        "Hello world! &#128520;",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		transcoded, err := strfs.TranscodeContent(strfs.CreateContent(test.Content), test.Encoding, test.Unrepresentable)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+			t.Logf("ERROR: (%T) %s", err, err)
+			t.Logf("CONTENT: %q", test.Content)
+			continue
+		}
+
+		if expected, actual := int64(len(test.Expected)), transcoded.Size(); expected != actual {
+			t.Errorf("For test #%d, the actual transcoded-content-size was not what was expected.", testNumber)
+			t.Logf("EXPECTED TRANSCODED-CONTENT-SIZE: %d", expected)
+			t.Logf("ACTUAL   TRANSCODED-CONTENT-SIZE: %d", actual)
+			t.Logf("CONTENT: %q", test.Content)
+			continue
+		}
+
+		{
+			actualBytes, err := io.ReadAll(&transcoded)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				t.Logf("CONTENT: %q", test.Content)
+				continue
+			}
+
+			if expected, actual := test.Expected, string(actualBytes); expected != actual {
+				t.Errorf("For test #%d, the actual transcoded-content was not what was expected.", testNumber)
+				t.Logf("EXPECTED TRANSCODED-CONTENT: %q", expected)
+				t.Logf("ACTUAL   TRANSCODED-CONTENT: %q", actual)
+				t.Logf("CONTENT: %q", test.Content)
+				continue
+			}
+		}
+
+		{
+			_, err := transcoded.Seek(1, io.SeekStart)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				t.Logf("CONTENT: %q", test.Content)
+				continue
+			}
+
+			actualBytes, err := io.ReadAll(&transcoded)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error but actually got one.", testNumber)
+				t.Logf("ERROR: (%T) %s", err, err)
+				t.Logf("CONTENT: %q", test.Content)
+				continue
+			}
+
+			if expected, actual := test.Expected[1:], string(actualBytes); expected != actual {
+				t.Errorf("For test #%d, the actual transcoded-content read after seeking was not what was expected.", testNumber)
+				t.Logf("EXPECTED TRANSCODED-CONTENT: %q", expected)
+				t.Logf("ACTUAL   TRANSCODED-CONTENT: %q", actual)
+				t.Logf("CONTENT: %q", test.Content)
+				continue
+			}
+		}
+	}
+}
+
+func TestTranscodeContent_error(t *testing.T) {
+
+	_, err := strfs.TranscodeContent(strfs.CreateContent("Hello world! 😈"), charmap.ISO8859_1, strfs.UnrepresentableError)
+	if nil == err {
+		t.Errorf("Expected an error but did not actually get one.")
+		return
+	}
+}
+
+func TestTranscodedContent_Content(t *testing.T) {
+
+	transcoded, err := strfs.TranscodeContent(strfs.CreateContent("Café"), charmap.Windows1252, strfs.UnrepresentableError)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var fsys strfs.FS = strfs.FS{
+		"cafe.txt": strfs.RegularFile{FileContent: transcoded.Content(), FileMode: 0644},
+	}
+
+	data, err := fs.ReadFile(fsys, "cafe.txt")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if expected, actual := "Caf\xE9", string(data); expected != actual {
+		t.Errorf("The actual file content was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	var empty strfs.TranscodedContent
+	if strfs.EmptyContent() != empty.Content() {
+		t.Errorf("Expected the content of an empty strfs.TranscodedContent to be empty.")
+	}
+}
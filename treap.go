@@ -0,0 +1,154 @@
+package strfs
+
+import (
+	"strings"
+)
+
+// internalTreapNode is a node of a persistent treap of files, sorted by path.
+//
+// A node is NEVER changed once it is part of a treap — changes copy the nodes on the path from the root to the change
+// (and share everything else), so older roots still see the older version of the treap.
+//
+// The priority of a node comes from (a hash of) its path, so the same set of paths always gives a treap with the same shape.
+type internalTreapNode struct {
+	name string
+	file RegularFile
+	priority uint64
+	left *internalTreapNode
+	right *internalTreapNode
+}
+
+// treapPriority returns the (deterministic) priority for the path 'name'.
+func treapPriority(name string) uint64 {
+	// FNV-1a, followed by the splitmix64 finalizer (to spread the bits out).
+	var hash uint64 = 0xCBF29CE484222325
+	for i := 0; i < len(name); i++ {
+		hash ^= uint64(name[i])
+		hash *= 0x100000001B3
+	}
+
+	hash = (hash ^ (hash >> 30)) * 0xBF58476D1CE4E5B9
+	hash = (hash ^ (hash >> 27)) * 0x94D049BB133111EB
+	hash =  hash ^ (hash >> 31)
+	return hash
+}
+
+// treapAscend calls 'fn' for each file whose path is greater-than or equal-to 'from', in order.
+// treapAscend stops early if 'fn' returns false, and returns false if it stopped early.
+func treapAscend(node *internalTreapNode, from string, fn func(name string, file RegularFile) bool) bool {
+	if nil == node {
+		return true
+	}
+
+	if from <= node.name {
+		if !treapAscend(node.left, from, fn) {
+			return false
+		}
+		if !fn(node.name, node.file) {
+			return false
+		}
+	}
+
+	return treapAscend(node.right, from, fn)
+}
+
+// treapDelete returns a treap that is 'node' without the file at 'name'.
+func treapDelete(node *internalTreapNode, name string) *internalTreapNode {
+	if nil == node {
+		return nil
+	}
+
+	var clone internalTreapNode = *node
+	switch {
+	case name < node.name:
+		clone.left = treapDelete(node.left, name)
+	case node.name < name:
+		clone.right = treapDelete(node.right, name)
+	default:
+		return treapMerge(node.left, node.right)
+	}
+
+	return &clone
+}
+
+// treapGet returns the file at 'name', and whether there is one.
+func treapGet(node *internalTreapNode, name string) (RegularFile, bool) {
+	for nil != node {
+		switch {
+		case name < node.name:
+			node = node.left
+		case node.name < name:
+			node = node.right
+		default:
+			return node.file, true
+		}
+	}
+
+	return RegularFile{}, false
+}
+
+// treapHasPrefix returns whether there is a file whose path starts with 'prefix'.
+func treapHasPrefix(node *internalTreapNode, prefix string) bool {
+	var found bool
+	treapAscend(node, prefix, func(name string, _ RegularFile) bool {
+		found = strings.HasPrefix(name, prefix)
+		return false
+	})
+
+	return found
+}
+
+// treapInsert returns a treap that is 'node' with 'file' at 'name' (replacing any file already there).
+func treapInsert(node *internalTreapNode, name string, file RegularFile) *internalTreapNode {
+	if nil == node {
+		return &internalTreapNode{
+			name:name,
+			file:file,
+			priority:treapPriority(name),
+		}
+	}
+
+	var clone internalTreapNode = *node
+	switch {
+	case name < node.name:
+		clone.left = treapInsert(node.left, name, file)
+		if clone.priority < clone.left.priority {
+			// Rotate right. (clone.left is a new node, so it is safe to change it.)
+			var left *internalTreapNode = clone.left
+			clone.left = left.right
+			left.right = &clone
+			return left
+		}
+	case node.name < name:
+		clone.right = treapInsert(node.right, name, file)
+		if clone.priority < clone.right.priority {
+			// Rotate left. (clone.right is a new node, so it is safe to change it.)
+			var right *internalTreapNode = clone.right
+			clone.right = right.left
+			right.left = &clone
+			return right
+		}
+	default:
+		clone.file = file
+	}
+
+	return &clone
+}
+
+// treapMerge joins two treaps (where everything in 'left' comes before everything in 'right') into one treap.
+func treapMerge(left *internalTreapNode, right *internalTreapNode) *internalTreapNode {
+	switch {
+	case nil == left:
+		return right
+	case nil == right:
+		return left
+	case right.priority < left.priority:
+		var clone internalTreapNode = *left
+		clone.right = treapMerge(left.right, right)
+		return &clone
+	default:
+		var clone internalTreapNode = *right
+		clone.left = treapMerge(left, right.left)
+		return &clone
+	}
+}
@@ -0,0 +1,424 @@
+package strfs
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tree is a mutable file-system (i.e., a [fs.FS]) made up of strfs.RegularFile.
+//
+// Unlike strfs.FS, files in a strfs.Tree can be written, removed, renamed, and chmod'ed after it is created
+// (and the changes can be watched — see Watch and WatchFunc).
+//
+// An immutable version of a strfs.Tree can be taken at any time with Snapshot (see strfs.Snapshot).
+//
+// A strfs.Tree is safe to use from more than one goroutine at the same time.
+//
+// The zero value of a strfs.Tree is an empty tree that is ready to use.
+// A strfs.Tree should NOT be copied after it is first used.
+//
+// Example usage:
+//
+//	var tree strfs.Tree
+//
+//	err := tree.WriteFile("index.html", "<!DOCTYPE html>"+"\n"+"<html><body>Hello world!</body></html>", 0644)
+//
+//	// ...
+//
+//	file, err := tree.Open("index.html")
+type Tree struct {
+	mutex sync.RWMutex
+	root *internalTreapNode
+	watchers map[*Watcher]struct{}
+}
+
+// A trick to make sure *strfs.Tree fits the fs.FS interface.
+// This is a compile-time check.
+var _ fs.FS = &Tree{}
+
+// CreateTree returns a strfs.Tree whose (initial) files are the files of 'files'.
+//
+// CreateTree returns an error if 'files' has a key that is NOT a valid path, or has a key that is a parent directory of another key (see the Validate method of strfs.FS).
+//
+// 'files' itself is NOT changed by changes to the returned strfs.Tree.
+func CreateTree(files FS) (*Tree, error) {
+	if err := files.Validate(); nil != err {
+		return nil, err
+	}
+
+	var tree Tree
+
+	for name, regularfile := range files {
+		tree.root = treapInsert(tree.root, name, regularfile)
+	}
+
+	return &tree, nil
+}
+
+// Apply changes the tree, so that it has the changes in 'diff' (see DiffFS).
+//
+// For each change in 'diff':
+//
+//   - for strfs.ChangeAdded, the New file is added — there must NOT already be a file there;
+//   - for strfs.ChangeRemoved, the file is removed — there must already be a file there;
+//   - otherwise, the content (strfs.ChangeModified), permission-bits (strfs.ChangeModeChanged), and/or modification-time (strfs.ChangeModTimeChanged)
+//     of the file are set to those of the New file — there must already be a file there.
+//
+// Either all the changes are applied, or (if there is an error) none of them are.
+func (receiver *Tree) Apply(diff Diff) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	var root *internalTreapNode = receiver.root
+	var events []Event
+
+	for _, change := range diff {
+		var name string = change.Name
+		if !validTreePath(name) {
+			return &fs.PathError{Op:"apply", Path:name, Err:fs.ErrInvalid}
+		}
+
+		regularfile, found := treapGet(root, name)
+
+		switch {
+		case 0 != change.Kind & ChangeAdded:
+			if found {
+				return &fs.PathError{Op:"apply", Path:name, Err:fs.ErrExist}
+			}
+			if err := conflict(root, "apply", name); nil != err {
+				return err
+			}
+
+			regularfile = change.New
+			regularfile.FileMode = regularfile.FileMode.Perm()
+			root = treapInsert(root, name, regularfile)
+			events = append(events, Event{Name:name, Op:EventCreate})
+		case 0 != change.Kind & ChangeRemoved:
+			if !found {
+				return &fs.PathError{Op:"apply", Path:name, Err:fs.ErrNotExist}
+			}
+
+			root = treapDelete(root, name)
+			events = append(events, Event{Name:name, Op:EventRemove})
+		default:
+			if !found {
+				return &fs.PathError{Op:"apply", Path:name, Err:fs.ErrNotExist}
+			}
+
+			var op EventOp
+			if 0 != change.Kind & ChangeModified {
+				regularfile.FileContent = change.New.FileContent
+				op |= EventWrite
+			}
+			if 0 != change.Kind & ChangeModTimeChanged {
+				regularfile.FileModTime = change.New.FileModTime
+				op |= EventWrite
+			}
+			if 0 != change.Kind & ChangeModeChanged {
+				regularfile.FileMode = change.New.FileMode.Perm()
+				op |= EventChmod
+			}
+			if 0 == op {
+				continue
+			}
+
+			root = treapInsert(root, name, regularfile)
+			events = append(events, Event{Name:name, Op:op})
+		}
+	}
+
+	receiver.root = root
+	for _, event := range events {
+		receiver.notify(event)
+	}
+	return nil
+}
+
+// Chmod changes the permission-bits of the file at 'name'.
+func (receiver *Tree) Chmod(name string, mode fs.FileMode) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	regularfile, err := receiver.regularFile("chmod", name)
+	if nil != err {
+		return err
+	}
+
+	regularfile.FileMode = mode.Perm()
+	receiver.root = treapInsert(receiver.root, name, regularfile)
+
+	receiver.notify(Event{Name:name, Op:EventChmod})
+	return nil
+}
+
+// conflict returns an error if a file at 'name' would conflict with the directories (or files) already in the treap 'root'.
+//
+// A file conflicts if there is already a directory at 'name', or if any of its parent directories is a file.
+func conflict(root *internalTreapNode, op string, name string) error {
+	if treapHasPrefix(root, name + "/") {
+		return &fs.PathError{Op:op, Path:name, Err:errIsDirectory}
+	}
+
+	for index := strings.LastIndexByte(name, '/'); 0 <= index; index = strings.LastIndexByte(name[:index], '/') {
+		if _, found := treapGet(root, name[:index]); found {
+			return &fs.PathError{Op:op, Path:name, Err:errNotDirectory}
+		}
+	}
+
+	return nil
+}
+
+func (receiver *Tree) notify(event Event) {
+	for watcher := range receiver.watchers {
+		watcher.push(event)
+	}
+}
+
+// Open opens the file (or directory) at 'name'.
+//
+// What is opened is a copy — later changes to the strfs.Tree do NOT change a file (or directory) that is already open.
+//
+// Open makes *strfs.Tree fit the fs.FS interface.
+func (receiver *Tree) Open(name string) (fs.File, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+
+	return receiver.Snapshot().Open(name)
+}
+
+// regularFile returns the file at 'name', or an error if there is no (regular) file there.
+func (receiver *Tree) regularFile(op string, name string) (RegularFile, error) {
+	if !validTreePath(name) {
+		return RegularFile{}, &fs.PathError{Op:op, Path:name, Err:fs.ErrInvalid}
+	}
+
+	regularfile, found := treapGet(receiver.root, name)
+	if !found {
+		if treapHasPrefix(receiver.root, name + "/") {
+			return RegularFile{}, &fs.PathError{Op:op, Path:name, Err:errIsDirectory}
+		}
+		return RegularFile{}, &fs.PathError{Op:op, Path:name, Err:fs.ErrNotExist}
+	}
+
+	return regularfile, nil
+}
+
+// Remove removes the file at 'name'.
+//
+// Directories are NOT removed by Remove (they go away by themselves once the last file in them is removed).
+func (receiver *Tree) Remove(name string) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if _, err := receiver.regularFile("remove", name); nil != err {
+		return err
+	}
+
+	receiver.root = treapDelete(receiver.root, name)
+
+	receiver.notify(Event{Name:name, Op:EventRemove})
+	return nil
+}
+
+// Rename moves the file at 'oldname' to 'newname'.
+//
+// If there is already a file at 'newname', then it is replaced.
+//
+// If 'oldname' is a directory, then every file in it is moved (at once) to be under 'newname' instead.
+// There must NOT already be a directory (or file) at 'newname' in that case.
+func (receiver *Tree) Rename(oldname string, newname string) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	regularfile, err := receiver.regularFile("rename", oldname)
+	if nil != err && errIsDirectory == errors.Unwrap(err) {
+		return receiver.renameDirectory(oldname, newname)
+	}
+	if nil != err {
+		return err
+	}
+	if !validTreePath(newname) {
+		return &fs.PathError{Op:"rename", Path:newname, Err:fs.ErrInvalid}
+	}
+	if oldname == newname {
+		return nil
+	}
+
+	var root *internalTreapNode = treapDelete(receiver.root, oldname)
+	if err := conflict(root, "rename", newname); nil != err {
+		return err
+	}
+	receiver.root = treapInsert(root, newname, regularfile)
+
+	receiver.notify(Event{Name:newname, OldName:oldname, Op:EventRename})
+	return nil
+}
+
+// renameDirectory moves every file in the directory 'oldname' to be under 'newname' instead.
+func (receiver *Tree) renameDirectory(oldname string, newname string) error {
+	if !validTreePath(newname) || strings.HasPrefix(newname+"/", oldname+"/") {
+		// (A directory cannot be moved into itself.)
+		return &fs.PathError{Op:"rename", Path:newname, Err:fs.ErrInvalid}
+	}
+
+	var names []string
+	var files []RegularFile
+	treapAscend(receiver.root, oldname+"/", func(name string, file RegularFile) bool {
+		if !strings.HasPrefix(name, oldname+"/") {
+			return false
+		}
+		names = append(names, name)
+		files = append(files, file)
+		return true
+	})
+
+	var root *internalTreapNode = receiver.root
+	for _, name := range names {
+		root = treapDelete(root, name)
+	}
+
+	if _, found := treapGet(root, newname); found || treapHasPrefix(root, newname+"/") {
+		return &fs.PathError{Op:"rename", Path:newname, Err:fs.ErrExist}
+	}
+	if err := conflict(root, "rename", newname); nil != err {
+		return err
+	}
+
+	for index, name := range names {
+		root = treapInsert(root, newname+strings.TrimPrefix(name, oldname), files[index])
+	}
+	receiver.root = root
+
+	for _, name := range names {
+		receiver.notify(Event{Name:newname+strings.TrimPrefix(name, oldname), OldName:name, Op:EventRename})
+	}
+	return nil
+}
+
+// Snapshot returns an immutable version of the tree, as it is now.
+//
+// Snapshot is cheap — nothing is copied (see strfs.Snapshot).
+func (receiver *Tree) Snapshot() Snapshot {
+	if nil == receiver {
+		return Snapshot{}
+	}
+
+	receiver.mutex.RLock()
+	defer receiver.mutex.RUnlock()
+
+	return Snapshot{
+		root:receiver.root,
+	}
+}
+
+// Watch returns a strfs.Watcher that delivers the changes made to the tree (from now on) over a channel
+// (see the Events method of strfs.Watcher).
+//
+// Only changes to files that are at 'prefix', or in the directory 'prefix', are delivered.
+// For example, a 'prefix' of "templates" would get changes to "templates/index.html" and "templates/blog/post.html",
+// but NOT to "templates.txt".
+// An empty 'prefix' (or ".") gets all the changes.
+// (A EventRename is delivered if either its Name or OldName matches 'prefix'.)
+//
+// Example usage:
+//
+//	watcher := tree.Watch("templates")
+//	defer watcher.Close()
+//
+//	for event := range watcher.Events() {
+//		// ...
+//	}
+func (receiver *Tree) Watch(prefix string) *Watcher {
+	return receiver.watch(prefix, make(chan Event), nil)
+}
+
+// WatchFunc is like Watch, except that the changes are delivered by calling 'fn' (from a goroutine of the strfs.Watcher)
+// rather than over a channel.
+//
+// 'fn' is never called more than once at the same time (for the same strfs.Watcher).
+func (receiver *Tree) WatchFunc(prefix string, fn func(Event)) *Watcher {
+	if nil == fn {
+		fn = func(Event){}
+	}
+
+	return receiver.watch(prefix, nil, fn)
+}
+
+func (receiver *Tree) watch(prefix string, events chan Event, fn func(Event)) *Watcher {
+	if nil == receiver {
+		return nil
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	var watcher *Watcher = createWatcher(receiver, prefix, events, fn)
+
+	if nil == receiver.watchers {
+		receiver.watchers = map[*Watcher]struct{}{}
+	}
+	receiver.watchers[watcher] = struct{}{}
+
+	return watcher
+}
+
+// WriteFile writes 'content' to the file at 'name', creating the file if it does not already exist.
+//
+// 'mode' (the permission-bits) is only used if the file is created.
+// The modification-time of the file is set to the current time.
+func (receiver *Tree) WriteFile(name string, content string, mode fs.FileMode) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if !validTreePath(name) {
+		return &fs.PathError{Op:"write", Path:name, Err:fs.ErrInvalid}
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	regularfile, found := treapGet(receiver.root, name)
+	if !found {
+		if err := conflict(receiver.root, "write", name); nil != err {
+			return err
+		}
+		regularfile.FileMode = mode.Perm()
+	}
+
+	regularfile.FileContent = CreateContent(content)
+	regularfile.FileModTime = time.Now()
+
+	receiver.root = treapInsert(receiver.root, name, regularfile)
+
+	var op EventOp = EventWrite
+	if !found {
+		op = EventCreate
+	}
+	receiver.notify(Event{Name:name, Op:op})
+	return nil
+}
+
+// validTreePath returns whether 'name' can be the path of a file in a strfs.Tree.
+func validTreePath(name string) bool {
+	return "." != name && fs.ValidPath(name)
+}
@@ -0,0 +1,244 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+	"codeberg.org/reiver/go-strfs/strfstest"
+
+	"errors"
+	"io"
+	"io/fs"
+
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+
+	var tree strfs.Tree
+
+	if err := tree.WriteFile("index.html", "<!DOCTYPE html>", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := tree.WriteFile("css/style.css", "body{color:red}", 0600); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := tree.WriteFile("css/style.css", "body{color:blue}", 0777); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := tree.Rename("index.html", "about/index.html"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := tree.Chmod("about/index.html", 0444); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if err := strfstest.TestFS(&tree, "about/index.html", "css/style.css"); nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+
+	{
+		data, err := fs.ReadFile(&tree, "css/style.css")
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		if expected, actual := "body{color:blue}", string(data); expected != actual {
+			t.Errorf("The actual content was not what was expected.")
+			t.Logf("EXPECTED CONTENT: %q", expected)
+			t.Logf("ACTUAL   CONTENT: %q", actual)
+		}
+	}
+
+	{
+		fileinfo, err := fs.Stat(&tree, "css/style.css")
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		if expected, actual := fs.FileMode(0600), fileinfo.Mode(); expected != actual {
+			t.Errorf("The actual mode was not what was expected.")
+			t.Logf("EXPECTED MODE: %s", expected)
+			t.Logf("ACTUAL   MODE: %s", actual)
+		}
+	}
+
+	{
+		fileinfo, err := fs.Stat(&tree, "about/index.html")
+		if nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+
+		if expected, actual := fs.FileMode(0444), fileinfo.Mode(); expected != actual {
+			t.Errorf("The actual mode was not what was expected.")
+			t.Logf("EXPECTED MODE: %s", expected)
+			t.Logf("ACTUAL   MODE: %s", actual)
+		}
+	}
+
+	if err := tree.Remove("css/style.css"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if err := strfstest.TestFS(&tree, "about/index.html"); nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+
+	if _, err := tree.Open("css"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected the removed file's directory to not exist anymore.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+}
+
+func TestTree_open(t *testing.T) {
+
+	tree, err := strfs.CreateTree(strfs.FS{
+		"file.txt": strfs.RegularFile{
+			FileContent: strfs.CreateContent("before"),
+		},
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	file, err := tree.Open("file.txt")
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	defer file.Close()
+
+	if err := tree.WriteFile("file.txt", "after", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	data, err := io.ReadAll(file)
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if expected, actual := "before", string(data); expected != actual {
+		t.Errorf("Did not expect a file that is already open to change.")
+		t.Logf("EXPECTED CONTENT: %q", expected)
+		t.Logf("ACTUAL   CONTENT: %q", actual)
+	}
+}
+
+func TestTree_Rename_directory(t *testing.T) {
+
+	tree, err := strfs.CreateTree(strfs.FS{
+		"index.html":         strfs.RegularFile{FileContent: strfs.CreateContent("<!DOCTYPE html>")},
+		"blog/post.html":     strfs.RegularFile{FileContent: strfs.CreateContent("first post")},
+		"blog/2023/new.html": strfs.RegularFile{FileContent: strfs.CreateContent("new post")},
+		"blogroll.html":      strfs.RegularFile{FileContent: strfs.CreateContent("friends")},
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if err := tree.Rename("blog", "posts/blog"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if err := strfstest.TestFS(tree, "index.html", "posts/blog/post.html", "posts/blog/2023/new.html", "blogroll.html"); nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+
+	if _, err := fs.Stat(tree, "blog"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected the old directory to not exist anymore.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+}
+
+func TestTree_error(t *testing.T) {
+
+	tree, err := strfs.CreateTree(strfs.FS{
+		"file.txt":     strfs.RegularFile{FileContent: strfs.CreateContent("once")},
+		"dir/file.txt": strfs.RegularFile{FileContent: strfs.CreateContent("twice")},
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	tests := []struct{
+		Name     string
+		Func     func() error
+		Expected error
+	}{
+		{
+			Name:     "write invalid path",
+			Func:     func() error { return tree.WriteFile("../file.txt", "", 0644) },
+			Expected: fs.ErrInvalid,
+		},
+		{
+			Name:     "write root",
+			Func:     func() error { return tree.WriteFile(".", "", 0644) },
+			Expected: fs.ErrInvalid,
+		},
+		{
+			Name: "write over directory",
+			Func: func() error { return tree.WriteFile("dir", "", 0644) },
+		},
+		{
+			Name: "write under file",
+			Func: func() error { return tree.WriteFile("file.txt/nope.txt", "", 0644) },
+		},
+		{
+			Name:     "remove missing",
+			Func:     func() error { return tree.Remove("missing.txt") },
+			Expected: fs.ErrNotExist,
+		},
+		{
+			Name: "remove directory",
+			Func: func() error { return tree.Remove("dir") },
+		},
+		{
+			Name:     "rename missing",
+			Func:     func() error { return tree.Rename("missing.txt", "other.txt") },
+			Expected: fs.ErrNotExist,
+		},
+		{
+			Name: "rename over directory",
+			Func: func() error { return tree.Rename("file.txt", "dir") },
+		},
+		{
+			Name:     "rename to invalid path",
+			Func:     func() error { return tree.Rename("file.txt", "/file.txt") },
+			Expected: fs.ErrInvalid,
+		},
+		{
+			Name:     "rename directory into itself",
+			Func:     func() error { return tree.Rename("dir", "dir/sub") },
+			Expected: fs.ErrInvalid,
+		},
+		{
+			Name:     "rename directory over file",
+			Func:     func() error { return tree.Rename("dir", "file.txt") },
+			Expected: fs.ErrExist,
+		},
+		{
+			Name:     "chmod missing",
+			Func:     func() error { return tree.Chmod("missing.txt", 0644) },
+			Expected: fs.ErrNotExist,
+		},
+	}
+
+	for testNumber, test := range tests {
+		err := test.Func()
+		if nil == err {
+			t.Errorf("For test #%d (%s), expected an error but did not actually get one.", testNumber, test.Name)
+			continue
+		}
+		if nil != test.Expected && !errors.Is(err, test.Expected) {
+			t.Errorf("For test #%d (%s), the actual error was not what was expected.", testNumber, test.Name)
+			t.Logf("EXPECTED ERROR: %s", test.Expected)
+			t.Logf("ACTUAL   ERROR: (%T) %s", err, err)
+			continue
+		}
+	}
+
+	if err := strfstest.TestFS(tree, "file.txt", "dir/file.txt"); nil != err {
+		t.Errorf("Did not expect the failed changes to change the tree.")
+		t.Logf("ERROR: (%T) %s", err, err)
+	}
+}
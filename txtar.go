@@ -0,0 +1,124 @@
+package strfs
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/reiver/go-erorr"
+)
+
+// ExportTxtar writes each regular file in 'fsys' (such as a strfs.FS) to 'writer' in the txtar format
+// (the simple text archive format used by the Go project's tests).
+//
+// The txtar format only has paths and contents — modification-times and permission-bits are NOT kept.
+//
+// A file whose content does NOT end in a newline gets one added (as the txtar format requires).
+// A file whose content contains a line that looks like a txtar file marker (i.e., "-- name --") causes an error.
+func ExportTxtar(writer io.Writer, fsys fs.FS) error {
+	if nil == writer {
+		return errNilWriter
+	}
+
+	return exportWalk(fsys, func(name string, fileinfo fs.FileInfo, data []byte) error {
+		var content string = string(data)
+
+		for _, line := range strings.SplitAfter(content, "\n") {
+			if _, isMarker := txtarMarker(line); isMarker {
+				return erorr.Errorf("file %q cannot be put into a txtar archive, because it contains the txtar file marker %q", name, strings.TrimRight(line, "\r\n"))
+			}
+		}
+
+		if "" != content && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+
+		_, err := io.WriteString(writer, "-- "+name+" --\n"+content)
+		return err
+	})
+}
+
+// ImportTxtar reads a txtar archive (the simple text archive format used by the Go project's tests) from 'reader'
+// and returns a strfs.FS with each of its files.
+//
+// Each file's content shares its bytes with the (single) string the archive is read into.
+//
+// The comment at the beginning of the archive (before the first file) is ignored.
+// More than one file with the same path causes an error.
+func ImportTxtar(reader io.Reader) (FS, error) {
+	if nil == reader {
+		return nil, errNilReader
+	}
+
+	data, err := io.ReadAll(reader)
+	if nil != err {
+		return nil, err
+	}
+
+	return parseTxtar(string(data))
+}
+
+func parseTxtar(archive string) (FS, error) {
+	var fsys FS = FS{}
+
+	var name string
+	var start int = -1
+
+	flush := func(end int) error {
+		if start < 0 {
+			return nil
+		}
+
+		filepath, err := importPath(name)
+		if nil != err {
+			return err
+		}
+		if _, duplicate := fsys[filepath]; duplicate {
+			return erorr.Errorf("txtar archive has more than one file named %q", filepath)
+		}
+
+		fsys[filepath] = RegularFile{
+			FileContent: CreateContent(archive[start:end]),
+		}
+		return nil
+	}
+
+	var offset int
+	for offset < len(archive) {
+		var line string = archive[offset:]
+		if index := strings.IndexByte(line, '\n'); 0 <= index {
+			line = line[:index+1]
+		}
+
+		if markerName, isMarker := txtarMarker(line); isMarker {
+			if err := flush(offset); nil != err {
+				return nil, err
+			}
+			name = markerName
+			start = offset + len(line)
+		}
+
+		offset += len(line)
+	}
+	if err := flush(len(archive)); nil != err {
+		return nil, err
+	}
+
+	return fsys, nil
+}
+
+// txtarMarker returns the file name in a txtar file marker line (i.e., "-- name --"), and whether 'line' is one.
+func txtarMarker(line string) (string, bool) {
+	line = strings.TrimRight(line, "\r\n")
+
+	if !strings.HasPrefix(line, "-- ") || !strings.HasSuffix(line, " --") || len(line) < len("-- x --") {
+		return "", false
+	}
+
+	var name string = strings.TrimSpace(line[len("-- ") : len(line)-len(" --")])
+	if "" == name {
+		return "", false
+	}
+
+	return name, true
+}
@@ -0,0 +1,58 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"bytes"
+	"strings"
+
+	"testing"
+)
+
+func TestExportTxtar_ImportTxtar(t *testing.T) {
+
+	var expected strfs.FS = exampleFS()
+
+	var buffer bytes.Buffer
+	if err := strfs.ExportTxtar(&buffer, expected); nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	actual, err := strfs.ImportTxtar(&buffer)
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	sameFS(t, expected, actual, false)
+}
+
+func TestImportTxtar(t *testing.T) {
+
+	const archive string =
+		"This is a comment."+"\n"+
+		"-- file1.txt --"+"\n"+
+		"once"+"\n"+
+		"-- ./gmni/file3.gmni --"+"\n"+
+		"once twice thrice"+"\n"+
+		"fource"+"\n"+
+		"-- empty.txt --"+"\n"
+
+	actual, err := strfs.ImportTxtar(strings.NewReader(archive))
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	var expected strfs.FS = strfs.FS{
+		"file1.txt":       strfs.RegularFile{FileContent: strfs.CreateContent("once"+"\n")},
+		"gmni/file3.gmni": strfs.RegularFile{FileContent: strfs.CreateContent("once twice thrice"+"\n"+"fource"+"\n")},
+		"empty.txt":       strfs.RegularFile{FileContent: strfs.CreateContent("")},
+	}
+
+	sameFS(t, expected, actual, false)
+}
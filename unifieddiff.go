@@ -0,0 +1,276 @@
+package strfs
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// unifiedDiffContext is the number of unchanged lines shown around each change in a unified diff.
+const unifiedDiffContext = 3
+
+// unifiedDiffMaxEdits is the most edits that are searched for (by the Myers diff algorithm).
+// Past it, the whole of the old content is shown as removed, and the whole of the new content as added.
+// (This keeps the memory and time used by a diff of two very different large files bounded.)
+const unifiedDiffMaxEdits = 4096
+
+type internalEditOp int
+
+const (
+	editEqual internalEditOp = iota
+	editDelete
+	editInsert
+)
+
+type internalEdit struct {
+	op internalEditOp
+	line string
+}
+
+// unifiedDiff returns a unified diff of 'oldcontent' (named 'oldname') and 'newcontent' (named 'newname').
+func unifiedDiff(oldname string, newname string, oldcontent string, newcontent string) string {
+	if oldcontent == newcontent {
+		return ""
+	}
+
+	if !isText(oldcontent) || !isText(newcontent) {
+		return "Binary files " + oldname + " and " + newname + " differ\n"
+	}
+
+	var edits []internalEdit = diffLines(splitLines(oldcontent), splitLines(newcontent))
+
+	var builder strings.Builder
+	builder.WriteString("--- ")
+	builder.WriteString(oldname)
+	builder.WriteString("\n")
+	builder.WriteString("+++ ")
+	builder.WriteString(newname)
+	builder.WriteString("\n")
+
+	// oldlines[i] and newlines[i] are the (0-based) line numbers that edits[i] is at.
+	var oldlines []int = make([]int, len(edits)+1)
+	var newlines []int = make([]int, len(edits)+1)
+	for i, edit := range edits {
+		oldlines[i+1], newlines[i+1] = oldlines[i], newlines[i]
+		if editInsert != edit.op {
+			oldlines[i+1]++
+		}
+		if editDelete != edit.op {
+			newlines[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		if editEqual == edits[i].op {
+			i++
+			continue
+		}
+
+		// Find the end of the hunk — changes that are close enough together share a hunk.
+		var end int = i
+		for j := i; j < len(edits); j++ {
+			if editEqual == edits[j].op {
+				continue
+			}
+			if end < j - 2*unifiedDiffContext {
+				break
+			}
+			end = j + 1
+		}
+
+		var first int = i - unifiedDiffContext
+		if first < 0 {
+			first = 0
+		}
+		var last int = end + unifiedDiffContext
+		if len(edits) < last {
+			last = len(edits)
+		}
+
+		builder.WriteString("@@ -")
+		builder.WriteString(hunkRange(oldlines[first], oldlines[last]-oldlines[first]))
+		builder.WriteString(" +")
+		builder.WriteString(hunkRange(newlines[first], newlines[last]-newlines[first]))
+		builder.WriteString(" @@\n")
+
+		for _, edit := range edits[first:last] {
+			switch edit.op {
+			case editEqual:
+				builder.WriteString(" ")
+			case editDelete:
+				builder.WriteString("-")
+			case editInsert:
+				builder.WriteString("+")
+			}
+			builder.WriteString(edit.line)
+			if !strings.HasSuffix(edit.line, "\n") {
+				builder.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = last
+	}
+
+	return builder.String()
+}
+
+// diffLines returns the (shortest) edits that turn 'a' into 'b', using the Myers diff algorithm.
+func diffLines(a []string, b []string) []internalEdit {
+	var edits []internalEdit
+
+	// Lines that are the same at the start and end do NOT need to be searched.
+	var prefix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	var suffix int
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for _, line := range a[:prefix] {
+		edits = append(edits, internalEdit{op:editEqual, line:line})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, internalEdit{op:editEqual, line:line})
+	}
+
+	return edits
+}
+
+// myers returns the (shortest) edits that turn 'a' into 'b'.
+//
+// See "An O(ND) Difference Algorithm and Its Variations" by Eugene W. Myers.
+func myers(a []string, b []string) []internalEdit {
+	var n, m int = len(a), len(b)
+	var max int = n + m
+	if unifiedDiffMaxEdits < max {
+		max = unifiedDiffMaxEdits
+	}
+
+	// v[k] is the furthest x reached on diagonal k (offset by 'max', so that k can be negative).
+	// trace[d] is (the part of) v that was used to find the edits at distance d.
+	var v []int = make([]int, 2*max+2)
+	var trace [][]int
+
+	var found bool
+	for d := 0; d <= max && !found; d++ {
+		trace = append(trace, append([]int(nil), v[max-d:max+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if -d == k || (d != k && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			var y int = x - k
+
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+
+			if n <= x && m <= y {
+				found = true
+				break
+			}
+		}
+	}
+
+	if !found {
+		var edits []internalEdit
+		for _, line := range a {
+			edits = append(edits, internalEdit{op:editDelete, line:line})
+		}
+		for _, line := range b {
+			edits = append(edits, internalEdit{op:editInsert, line:line})
+		}
+		return edits
+	}
+
+	// Walk back through the trace, from the end, to find the edits.
+	var reversed []internalEdit
+	var x, y int = n, m
+	for d := len(trace) - 1; 0 < d; d-- {
+		var saved []int = trace[d]
+		get := func(k int) int {
+			return saved[k+d]
+		}
+
+		var k int = x - y
+		var previousK int
+		if -d == k || (d != k && get(k-1) < get(k+1)) {
+			previousK = k + 1
+		} else {
+			previousK = k - 1
+		}
+		var previousX int = get(previousK)
+		var previousY int = previousX - previousK
+
+		for previousX < x && previousY < y {
+			reversed = append(reversed, internalEdit{op:editEqual, line:a[x-1]})
+			x--
+			y--
+		}
+
+		if x == previousX {
+			reversed = append(reversed, internalEdit{op:editInsert, line:b[y-1]})
+			y--
+		} else {
+			reversed = append(reversed, internalEdit{op:editDelete, line:a[x-1]})
+			x--
+		}
+	}
+	for 0 < x && 0 < y {
+		reversed = append(reversed, internalEdit{op:editEqual, line:a[x-1]})
+		x--
+		y--
+	}
+
+	var edits []internalEdit = make([]internalEdit, len(reversed))
+	for i, edit := range reversed {
+		edits[len(reversed)-1-i] = edit
+	}
+
+	return edits
+}
+
+// hunkRange returns the range part of a hunk header, such as "12,7".
+//
+// 'start' is the 0-based line number that the hunk starts at.
+func hunkRange(start int, count int) string {
+	switch count {
+	case 0:
+		return strconv.Itoa(start) + ",0"
+	case 1:
+		return strconv.Itoa(start + 1)
+	default:
+		return strconv.Itoa(start + 1) + "," + strconv.Itoa(count)
+	}
+}
+
+// isText returns whether 'value' looks like text (rather than binary data).
+func isText(value string) bool {
+	return utf8.ValidString(value) && !strings.ContainsRune(value, 0)
+}
+
+// splitLines splits 'value' into lines, where each line keeps its "\n" (except maybe the last line).
+func splitLines(value string) []string {
+	var lines []string
+
+	for "" != value {
+		var index int = strings.IndexByte(value, '\n')
+		if index < 0 {
+			lines = append(lines, value)
+			break
+		}
+
+		lines = append(lines, value[:index+1])
+		value = value[index+1:]
+	}
+
+	return lines
+}
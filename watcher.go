@@ -0,0 +1,193 @@
+package strfs
+
+import (
+	"sync"
+)
+
+// Watcher receives the changes made to (part of) a strfs.Tree.
+//
+// A strfs.Watcher is returned by the Watch and WatchFunc methods of strfs.Tree.
+//
+// Changes are delivered by a goroutine that belongs to the strfs.Watcher, so a slow subscriber never stalls
+// the goroutines that are changing the strfs.Tree.
+// While a subscriber is busy, the events waiting for it are coalesced — events for the same path are combined
+// into a single strfs.Event (whose Op has the bits of the events combined).
+// The combined strfs.Event says what is true after the last of them:
+// if its Op has EventRemove, then the file does NOT exist anymore; otherwise it does.
+// (So a file that is created and then removed gets no event, and a file that is removed and then created again gets EventWrite.)
+//
+// Call Close when the strfs.Watcher is no longer needed.
+type Watcher struct {
+	tree *Tree
+	prefix string
+
+	mutex sync.Mutex
+	pending []Event
+	indexes map[string]int
+
+	signal chan struct{}
+	done chan struct{}
+	closeOnce sync.Once
+
+	events chan Event
+	fn func(Event)
+}
+
+func createWatcher(tree *Tree, prefix string, events chan Event, fn func(Event)) *Watcher {
+	for 1 < len(prefix) && '/' == prefix[len(prefix)-1] {
+		prefix = prefix[:len(prefix)-1]
+	}
+
+	var watcher *Watcher = &Watcher{
+		tree:tree,
+		prefix:prefix,
+		indexes:map[string]int{},
+		signal:make(chan struct{}, 1),
+		done:make(chan struct{}),
+		events:events,
+		fn:fn,
+	}
+
+	go watcher.loop()
+
+	return watcher
+}
+
+// Close stops the strfs.Watcher from receiving any more changes.
+//
+// If the strfs.Watcher was returned by Watch, then its Events channel is closed (once any delivery in progress is given up on).
+// Events that have not been delivered yet are dropped.
+//
+// Close can safely be called more than once.
+func (receiver *Watcher) Close() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.closeOnce.Do(func() {
+		if nil != receiver.tree {
+			receiver.tree.mutex.Lock()
+			delete(receiver.tree.watchers, receiver)
+			receiver.tree.mutex.Unlock()
+		}
+
+		close(receiver.done)
+	})
+	return nil
+}
+
+// deliver sends 'event' to the subscriber.
+// It returns false if the strfs.Watcher was closed instead.
+func (receiver *Watcher) deliver(event Event) bool {
+	if nil != receiver.fn {
+		select {
+		case <-receiver.done:
+			return false
+		default:
+		}
+
+		receiver.fn(event)
+		return true
+	}
+
+	select {
+	case receiver.events <- event:
+		return true
+	case <-receiver.done:
+		return false
+	}
+}
+
+// Events returns the channel that the changes are delivered on.
+//
+// Events returns nil if the strfs.Watcher was returned by WatchFunc.
+func (receiver *Watcher) Events() <-chan Event {
+	if nil == receiver {
+		return nil
+	}
+
+	return receiver.events
+}
+
+func (receiver *Watcher) loop() {
+	if nil != receiver.events {
+		defer close(receiver.events)
+	}
+
+	for {
+		select {
+		case <-receiver.done:
+			return
+		case <-receiver.signal:
+		}
+
+		receiver.mutex.Lock()
+		var batch []Event = receiver.pending
+		receiver.pending = nil
+		receiver.indexes = map[string]int{}
+		receiver.mutex.Unlock()
+
+		for _, event := range batch {
+			// (An event whose Op is zero was coalesced away — see coalesceEvent.)
+			if 0 == event.Op {
+				continue
+			}
+			if !receiver.deliver(event) {
+				return
+			}
+		}
+	}
+}
+
+// coalesceEvent combines 'event' into 'pending' (an earlier event, for the same path, that has not been delivered yet).
+//
+// The combined strfs.Event says what is true after 'event' — if it has EventRemove, then the file does NOT exist anymore;
+// otherwise it does:
+//
+//	CREATE then REMOVE  →  (nothing — the Op is zero, and the event is NOT delivered)
+//	REMOVE then CREATE  →  WRITE
+//	WRITE  then REMOVE  →  REMOVE
+func coalesceEvent(pending Event, event Event) Event {
+	switch {
+	case 0 == pending.Op:
+		return event
+	case 0 != event.Op & EventRemove:
+		if 0 != pending.Op & EventCreate && 0 == pending.Op & EventRename {
+			// Created, and then removed — so, as far as the subscriber is concerned, nothing happened.
+			return Event{Name:pending.Name}
+		}
+		pending.Op = pending.Op &^ (EventWrite | EventChmod) | EventRemove
+	case 0 != pending.Op & EventRemove:
+		// Removed, and then created again — so the file is still there, but (possibly) with different content.
+		pending.Op = pending.Op &^ EventRemove | event.Op &^ EventCreate | EventWrite
+	default:
+		pending.Op |= event.Op
+	}
+
+	if "" != event.OldName {
+		pending.OldName = event.OldName
+	}
+
+	return pending
+}
+
+// push queues 'event' (if it matches the prefix of the strfs.Watcher) to be delivered, without blocking.
+func (receiver *Watcher) push(event Event) {
+	if !matchesPrefix(receiver.prefix, event.Name) && !(EventRename == event.Op && matchesPrefix(receiver.prefix, event.OldName)) {
+		return
+	}
+
+	receiver.mutex.Lock()
+	if index, found := receiver.indexes[event.Name]; found {
+		receiver.pending[index] = coalesceEvent(receiver.pending[index], event)
+	} else {
+		receiver.indexes[event.Name] = len(receiver.pending)
+		receiver.pending = append(receiver.pending, event)
+	}
+	receiver.mutex.Unlock()
+
+	select {
+	case receiver.signal <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,256 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"sync"
+	"time"
+
+	"testing"
+)
+
+func TestTree_Watch(t *testing.T) {
+
+	var tree strfs.Tree
+
+	var watcher *strfs.Watcher = tree.Watch("templates/")
+	defer watcher.Close()
+
+	if err := tree.WriteFile("templates/index.html", "once", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	expectEvent(t, watcher, strfs.Event{Name:"templates/index.html", Op:strfs.EventCreate})
+
+	if err := tree.WriteFile("templates.txt", "not watched", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := tree.WriteFile("static/style.css", "not watched", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if err := tree.Chmod("templates/index.html", 0600); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	expectEvent(t, watcher, strfs.Event{Name:"templates/index.html", Op:strfs.EventChmod})
+
+	if err := tree.Rename("templates/index.html", "old/index.html"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	expectEvent(t, watcher, strfs.Event{Name:"old/index.html", OldName:"templates/index.html", Op:strfs.EventRename})
+
+	if err := tree.Rename("static/style.css", "templates/style.css"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	expectEvent(t, watcher, strfs.Event{Name:"templates/style.css", OldName:"static/style.css", Op:strfs.EventRename})
+
+	if err := tree.Remove("templates/style.css"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	expectEvent(t, watcher, strfs.Event{Name:"templates/style.css", Op:strfs.EventRemove})
+
+	if err := watcher.Close(); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	select {
+	case event, ok := <-watcher.Events():
+		if ok {
+			t.Errorf("Did not expect an event after the watcher was closed but actually got one.")
+			t.Logf("EVENT: %s", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected the events channel to be closed after the watcher was closed.")
+	}
+
+	if err := tree.WriteFile("templates/index.html", "twice", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+}
+
+func TestTree_Watch_coalesce(t *testing.T) {
+
+	var tree strfs.Tree
+
+	var watcher *strfs.Watcher = tree.Watch("")
+	defer watcher.Close()
+
+	// The first event is picked up by the watcher's goroutine, which then waits for us to receive it.
+	// Everything after it should be coalesced, because we are a slow subscriber.
+	if err := tree.WriteFile("first.txt", "", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 1000; i++ {
+			if err := tree.WriteFile("file.txt", "content", 0644); nil != err {
+				t.Errorf("Did not expect an error but actually got one: (%T) %s", err, err)
+				return
+			}
+		}
+		if err := tree.Chmod("file.txt", 0600); nil != err {
+			t.Errorf("Did not expect an error but actually got one: (%T) %s", err, err)
+			return
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected a slow subscriber to NOT stall the writer.")
+	}
+
+	expectEvent(t, watcher, strfs.Event{Name:"first.txt", Op:strfs.EventCreate})
+	expectEvent(t, watcher, strfs.Event{Name:"file.txt", Op:strfs.EventCreate|strfs.EventWrite|strfs.EventChmod})
+}
+
+func TestTree_Watch_coalesceOrder(t *testing.T) {
+
+	var tree strfs.Tree
+
+	if err := tree.WriteFile("existing.txt", "before", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var watcher *strfs.Watcher = tree.Watch("")
+	defer watcher.Close()
+
+	// The first event is picked up by the watcher's goroutine, which then waits for us to receive it.
+	// Everything after it should be coalesced, because we are a slow subscriber.
+	if err := tree.WriteFile("first.txt", "", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// CREATE then REMOVE.
+	if err := tree.WriteFile("temporary.txt", "content", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := tree.Remove("temporary.txt"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	// REMOVE then CREATE.
+	if err := tree.Remove("existing.txt"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := tree.WriteFile("existing.txt", "after", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	// WRITE then REMOVE.
+	if err := tree.WriteFile("first.txt", "changed", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+	if err := tree.Remove("first.txt"); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	if err := tree.WriteFile("last.txt", "", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	expectEvent(t, watcher, strfs.Event{Name:"first.txt", Op:strfs.EventCreate})
+	// (There is no event for "temporary.txt", because it was created and then removed.)
+	expectEvent(t, watcher, strfs.Event{Name:"existing.txt", Op:strfs.EventWrite})
+	expectEvent(t, watcher, strfs.Event{Name:"first.txt", Op:strfs.EventRemove})
+	expectEvent(t, watcher, strfs.Event{Name:"last.txt", Op:strfs.EventCreate})
+}
+
+func TestTree_WatchFunc(t *testing.T) {
+
+	tree, err := strfs.CreateTree(strfs.FS{
+		"a/file.txt": strfs.RegularFile{FileContent: strfs.CreateContent("once")},
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	var mutex sync.Mutex
+	var events []strfs.Event
+	var received = make(chan struct{}, 10)
+
+	var watcher *strfs.Watcher = tree.WatchFunc("a", func(event strfs.Event) {
+		mutex.Lock()
+		events = append(events, event)
+		mutex.Unlock()
+
+		received <- struct{}{}
+	})
+	defer watcher.Close()
+
+	if nil != watcher.Events() {
+		t.Errorf("Did not expect a watcher from WatchFunc to have an events channel.")
+	}
+
+	if err := tree.WriteFile("a/file.txt", "twice", 0644); nil != err {
+		t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the callback to be called.")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if expected, actual := 1, len(events); expected != actual {
+		t.Fatalf("The actual number of events was not what was expected: expected %d, actually %d", expected, actual)
+	}
+	if expected, actual := (strfs.Event{Name:"a/file.txt", Op:strfs.EventWrite}), events[0]; expected != actual {
+		t.Errorf("The actual event was not what was expected.")
+		t.Logf("EXPECTED EVENT: %s", expected)
+		t.Logf("ACTUAL   EVENT: %s", actual)
+	}
+}
+
+func TestEventOp_String(t *testing.T) {
+
+	tests := []struct{
+		EventOp  strfs.EventOp
+		Expected string
+	}{
+		{
+			EventOp:  0,
+			Expected: "",
+		},
+		{
+			EventOp:  strfs.EventRemove,
+			Expected: "REMOVE",
+		},
+		{
+			EventOp:  strfs.EventCreate | strfs.EventWrite | strfs.EventChmod,
+			Expected: "CREATE|WRITE|CHMOD",
+		},
+	}
+
+	for testNumber, test := range tests {
+		if expected, actual := test.Expected, test.EventOp.String(); expected != actual {
+			t.Errorf("For test #%d, the actual string was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}
+
+func expectEvent(t *testing.T, watcher *strfs.Watcher, expected strfs.Event) {
+	t.Helper()
+
+	select {
+	case actual := <-watcher.Events():
+		if expected != actual {
+			t.Errorf("The actual event was not what was expected.")
+			t.Logf("EXPECTED EVENT: %s", expected)
+			t.Logf("ACTUAL   EVENT: %s", actual)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected an event but did not actually get one.")
+		t.Logf("EXPECTED EVENT: %s", expected)
+	}
+}
@@ -0,0 +1,68 @@
+package strfs
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/reiver/go-erorr"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadYAMLManifest reads a YAML manifest (see below) from 'reader', and returns the strfs.FS it describes.
+//
+// 'files' is used to load the content of any manifest entries that refer to another file (with "file").
+// 'files' can be nil if there are no such entries.
+//
+// The manifest is strictly validated. Unknown fields, and duplicate paths, are an error.
+// Errors say which path (in the manifest) the problem is with.
+//
+// A YAML manifest looks like:
+//
+//	index.html:
+//	  content: |
+//	    <!DOCTYPE html>
+//	    <html></html>
+//	  mode: "0644"
+//	  mtime: 2022-12-12T10:30:14Z
+//	img/logo.png:
+//	  base64: iVBORw0KGgo=
+//	css/style.css:
+//	  file: style.css
+//	style.css:
+//	  symlink: css/style.css
+func LoadYAMLManifest(reader io.Reader, files fs.FS) (FS, error) {
+	if nil == reader {
+		return nil, errNilReader
+	}
+
+	var decoder *yaml.Decoder = yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+
+	var manifest map[string]manifestEntry
+	if err := decoder.Decode(&manifest); nil != err && io.EOF != err {
+		return nil, erorr.Errorf("manifest: %w", err)
+	}
+
+	return buildManifest(manifest, files)
+}
+
+// SaveYAMLManifest writes a YAML manifest (see LoadYAMLManifest) describing each regular file in 'fsys' to 'writer'.
+//
+// Content that is valid UTF-8 is put inline (as "content"). Anything else is base64 encoded (as "base64").
+func SaveYAMLManifest(writer io.Writer, fsys fs.FS) error {
+	if nil == writer {
+		return errNilWriter
+	}
+
+	manifest, err := createManifest(fsys)
+	if nil != err {
+		return err
+	}
+
+	var encoder *yaml.Encoder = yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(manifest); nil != err {
+		return err
+	}
+	return encoder.Close()
+}
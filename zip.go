@@ -0,0 +1,98 @@
+package strfs
+
+import (
+	"archive/zip"
+	"io"
+	"io/fs"
+
+	"github.com/reiver/go-erorr"
+)
+
+// ExportZip writes each regular file in 'fsys' (such as a strfs.FS) to 'writer' as a zip archive,
+// keeping each file's path, content, modification-time, and permission-bits.
+func ExportZip(writer io.Writer, fsys fs.FS) error {
+	if nil == writer {
+		return errNilWriter
+	}
+
+	var zipwriter *zip.Writer = zip.NewWriter(writer)
+
+	err := exportWalk(fsys, func(name string, fileinfo fs.FileInfo, data []byte) error {
+		var header zip.FileHeader = zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: fileinfo.ModTime(),
+		}
+		header.SetMode(fileinfo.Mode().Perm())
+
+		filewriter, err := zipwriter.CreateHeader(&header)
+		if nil != err {
+			return err
+		}
+		_, err = filewriter.Write(data)
+		return err
+	})
+	if nil != err {
+		return err
+	}
+
+	return zipwriter.Close()
+}
+
+// ImportZip reads a zip archive (that is 'size' bytes long) from 'reader' and returns a strfs.FS with each of its regular files,
+// keeping each file's path, content, modification-time, and permission-bits.
+//
+// Directory entries are skipped (since, in a strfs.FS, directories are implied by the paths of the files).
+// Any other kind of entry (such as a symbolic link) causes an error.
+// So does more than one file with the same path.
+func ImportZip(reader io.ReaderAt, size int64) (FS, error) {
+	if nil == reader {
+		return nil, errNilReader
+	}
+
+	zipreader, err := zip.NewReader(reader, size)
+	if nil != err {
+		return nil, err
+	}
+
+	var fsys FS = FS{}
+
+	for _, file := range zipreader.File {
+		var mode fs.FileMode = file.Mode()
+
+		switch {
+		case mode.IsDir():
+			continue
+		case mode.IsRegular():
+			// Nothing here.
+		default:
+			return nil, erorr.Errorf("zip entry %q has unsupported mode %v", file.Name, mode)
+		}
+
+		name, err := importPath(file.Name)
+		if nil != err {
+			return nil, err
+		}
+		if _, duplicate := fsys[name]; duplicate {
+			return nil, erorr.Errorf("zip archive has more than one file named %q", name)
+		}
+
+		filereader, err := file.Open()
+		if nil != err {
+			return nil, err
+		}
+		data, err := io.ReadAll(filereader)
+		filereader.Close()
+		if nil != err {
+			return nil, err
+		}
+
+		fsys[name] = RegularFile{
+			FileContent: CreateContent(string(data)),
+			FileModTime: file.Modified,
+			FileMode:    mode.Perm(),
+		}
+	}
+
+	return fsys, nil
+}
@@ -0,0 +1,55 @@
+package strfs_test
+
+import (
+	"codeberg.org/reiver/go-strfs"
+
+	"archive/zip"
+	"bytes"
+
+	"testing"
+)
+
+func TestExportZip_ImportZip(t *testing.T) {
+
+	var expected strfs.FS = exampleFS()
+
+	var buffer bytes.Buffer
+	if err := strfs.ExportZip(&buffer, expected); nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	actual, err := strfs.ImportZip(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if nil != err {
+		t.Errorf("Did not expect an error but actually got one.")
+		t.Logf("ERROR: (%T) %s", err, err)
+		return
+	}
+
+	sameFS(t, expected, actual, true)
+}
+
+func TestImportZip_duplicate(t *testing.T) {
+
+	var buffer bytes.Buffer
+	{
+		var writer *zip.Writer = zip.NewWriter(&buffer)
+		for _, content := range []string{"first", "second"} {
+			file, err := writer.Create("dir/file.txt")
+			if nil != err {
+				t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+			}
+			if _, err := file.Write([]byte(content)); nil != err {
+				t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+			}
+		}
+		if err := writer.Close(); nil != err {
+			t.Fatalf("Did not expect an error but actually got one: (%T) %s", err, err)
+		}
+	}
+
+	if _, err := strfs.ImportZip(bytes.NewReader(buffer.Bytes()), int64(buffer.Len())); nil == err {
+		t.Errorf("Expected an error importing a zip archive with two files with the same name, but did not actually get one.")
+	}
+}